@@ -0,0 +1,49 @@
+package bebo
+
+import "net/http"
+
+// Abort renders err with the route's error handler (or the app's) right away
+// and marks the context aborted. Later writes through ctx.ResponseWriter are
+// discarded, and an error returned after Abort is not rendered a second time,
+// so `ctx.Abort(err); return nil` and `ctx.Abort(err); return err` behave the
+// same for the client. Middleware still sees the returned value.
+func (c *Context) Abort(err error) {
+	if c.aborted {
+		return
+	}
+	c.handleError(err)
+	c.aborted = true
+	c.ResponseWriter = &abortedWriter{header: make(http.Header)}
+}
+
+// IsAborted reports whether Abort was called for the request.
+func (c *Context) IsAborted() bool {
+	return c.aborted
+}
+
+func (c *Context) handleError(err error) {
+	if c.errorHandler != nil {
+		c.errorHandler(c, err)
+		return
+	}
+	if c.app != nil && c.app.errorHandler != nil {
+		c.app.errorHandler(c, err)
+		return
+	}
+	defaultErrorHandler(c, err)
+}
+
+// abortedWriter drops everything written after Abort.
+type abortedWriter struct {
+	header http.Header
+}
+
+func (w *abortedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *abortedWriter) WriteHeader(int) {}
+
+func (w *abortedWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
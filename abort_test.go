@@ -0,0 +1,107 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+func TestAbortRendersErrorAndSuppressesWrites(t *testing.T) {
+	app := New()
+	calls := 0
+	app.Use(func(next Handler) Handler {
+		return func(ctx *Context) error {
+			err := next(ctx)
+			if !ctx.IsAborted() {
+				t.Errorf("expected middleware to observe abort")
+			}
+			return err
+		}
+	})
+	app.GET("/", func(ctx *Context) error {
+		calls++
+		ctx.Abort(apperr.NotFound("missing", nil))
+		_ = ctx.Text(http.StatusOK, "late write")
+		return apperr.Internal("ignored", nil)
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "late write") || strings.Contains(body, "ignored") {
+		t.Fatalf("expected later output suppressed, got %q", body)
+	}
+	if !strings.Contains(body, "missing") {
+		t.Fatalf("expected aborted error rendered, got %q", body)
+	}
+	if calls != 1 {
+		t.Fatalf("expected handler called once, got %d", calls)
+	}
+}
+
+func TestAbortUsesGroupErrorHandler(t *testing.T) {
+	app := New()
+	api := app.Group("/api")
+	api.OnError(func(ctx *Context, err error) {
+		_ = ctx.Text(http.StatusTeapot, "group:"+err.Error())
+	})
+	api.GET("/items", func(ctx *Context) error {
+		ctx.Abort(apperr.Forbidden("nope", nil))
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+
+	if rec.Code != http.StatusTeapot || !strings.HasPrefix(rec.Body.String(), "group:") {
+		t.Fatalf("expected group error handler, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAbortWithinRouteTimeout(t *testing.T) {
+	app := New()
+	app.Route(http.MethodGet, "/", func(ctx *Context) error {
+		ctx.Abort(apperr.BadRequest("bad input", nil))
+		return ctx.Text(http.StatusOK, "late write")
+	}, WithTimeout(time.Second))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusBadRequest || strings.Contains(rec.Body.String(), "late write") {
+		t.Fatalf("expected aborted bad request, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAbortWithinTimeoutHandlerSuppressesOuterWrites(t *testing.T) {
+	app := New()
+	app.Use(func(next Handler) Handler {
+		return func(ctx *Context) error {
+			err := next(ctx)
+			if !ctx.IsAborted() {
+				t.Errorf("expected middleware to observe abort")
+			}
+			_, _ = ctx.ResponseWriter.Write([]byte("outer write"))
+			return err
+		}
+	})
+	app.GET("/", TimeoutHandler(func(ctx *Context) error {
+		ctx.Abort(apperr.BadRequest("bad input", nil))
+		return nil
+	}, time.Second))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusBadRequest || strings.Contains(rec.Body.String(), "outer write") {
+		t.Fatalf("expected outer write suppressed after abort, got %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,104 @@
+package bebo
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/devmarvs/bebo/internal/httpx"
+)
+
+// AfterResponseFunc observes a finished request. status is the final status
+// sent (200 when the handler wrote nothing, 101 for hijacked connections) and
+// bytes is the number of body bytes written.
+type AfterResponseFunc func(ctx *Context, status int, bytes int, duration time.Duration)
+
+// AfterResponse registers a hook invoked once per request after the handler,
+// middleware and error handler have finished. Use it for cleanup or metrics
+// that need the final status and size.
+func (a *App) AfterResponse(fn AfterResponseFunc) {
+	if fn == nil {
+		return
+	}
+	a.afterResponse = append(a.afterResponse, fn)
+}
+
+// trackResponse wraps the context writer when hooks are registered and
+// returns the func that runs them.
+func (a *App) trackResponse(ctx *Context) func() {
+	if len(a.afterResponse) == 0 {
+		return func() {}
+	}
+	start := time.Now()
+	tracker := &responseTracker{writer: ctx.ResponseWriter}
+	ctx.ResponseWriter = tracker
+	return func() {
+		duration := time.Since(start)
+		status := tracker.header.Status()
+		switch {
+		case tracker.header.Written():
+		case tracker.hijacked:
+			status = http.StatusSwitchingProtocols
+		default:
+			status = http.StatusOK
+		}
+		for _, fn := range a.afterResponse {
+			fn(ctx, status, tracker.bytes, duration)
+		}
+	}
+}
+
+// responseTracker records the final status and body size.
+type responseTracker struct {
+	writer   http.ResponseWriter
+	header   httpx.WriteHeaderOnce
+	bytes    int
+	hijacked bool
+}
+
+func (t *responseTracker) Header() http.Header {
+	return t.writer.Header()
+}
+
+func (t *responseTracker) WriteHeader(status int) {
+	t.header.WriteHeader(t.writer, status)
+}
+
+func (t *responseTracker) Write(p []byte) (int, error) {
+	t.header.Record(http.StatusOK)
+	n, err := t.writer.Write(p)
+	t.bytes += n
+	return n, err
+}
+
+func (t *responseTracker) Flush() {
+	t.header.Record(http.StatusOK)
+	if flusher, ok := t.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (t *responseTracker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := t.writer.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		t.hijacked = true
+	}
+	return conn, rw, err
+}
+
+func (t *responseTracker) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := t.writer.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return pusher.Push(target, opts)
+}
+
+func (t *responseTracker) Unwrap() http.ResponseWriter {
+	return t.writer
+}
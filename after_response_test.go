@@ -0,0 +1,102 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+type responseHookCall struct {
+	status   int
+	bytes    int
+	duration time.Duration
+	route    string
+}
+
+func TestAfterResponseReportsStatusBytesAndDuration(t *testing.T) {
+	app := New()
+	var calls []responseHookCall
+	app.AfterResponse(func(ctx *Context, status int, bytes int, duration time.Duration) {
+		calls = append(calls, responseHookCall{status: status, bytes: bytes, duration: duration, route: ctx.RoutePattern()})
+	})
+
+	app.GET("/slow", func(ctx *Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return ctx.Text(http.StatusCreated, "hello")
+	})
+	app.GET("/missing", func(ctx *Context) error {
+		return apperr.NotFound("missing", nil)
+	})
+	app.GET("/empty", func(ctx *Context) error {
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if len(calls) != 1 {
+		t.Fatalf("expected one hook call, got %d", len(calls))
+	}
+	if got := calls[0]; got.status != http.StatusCreated || got.bytes != len("hello") || got.route != "/slow" {
+		t.Fatalf("unexpected hook call: %+v", got)
+	}
+	if calls[0].duration < 20*time.Millisecond {
+		t.Fatalf("expected duration to cover the handler, got %v", calls[0].duration)
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if got := calls[1]; got.status != http.StatusNotFound || got.bytes != rec.Body.Len() || got.bytes == 0 {
+		t.Fatalf("expected error response to be reported, got %+v (body %d bytes)", got, rec.Body.Len())
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+	if got := calls[2]; got.status != http.StatusNotFound || got.route != "" {
+		t.Fatalf("expected unmatched request to be reported, got %+v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/empty", nil))
+	if got := calls[3]; got.status != http.StatusOK || got.bytes != 0 {
+		t.Fatalf("expected implicit 200, got %+v", got)
+	}
+}
+
+func TestAfterResponseRunsAfterMiddlewareAndTimeout(t *testing.T) {
+	app := New(WithAutoHead(true))
+	order := []string{}
+	app.Use(func(next Handler) Handler {
+		return func(ctx *Context) error {
+			err := next(ctx)
+			order = append(order, "middleware")
+			return err
+		}
+	})
+	var status, bytes int
+	app.AfterResponse(func(ctx *Context, s int, b int, _ time.Duration) {
+		order = append(order, "hook")
+		status, bytes = s, b
+	})
+
+	app.Route(http.MethodGet, "/timeout", func(ctx *Context) error {
+		return ctx.Text(http.StatusAccepted, "buffered")
+	}, WithTimeout(time.Second))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/timeout", nil))
+	if status != http.StatusAccepted || bytes != len("buffered") {
+		t.Fatalf("expected buffered timeout response to be reported, got %d/%d", status, bytes)
+	}
+	if len(order) != 2 || order[0] != "middleware" || order[1] != "hook" {
+		t.Fatalf("unexpected order: %v", order)
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/timeout", nil))
+	if status != http.StatusAccepted || bytes != 0 {
+		t.Fatalf("expected HEAD to report no body bytes, got %d/%d", status, bytes)
+	}
+}
@@ -37,13 +37,16 @@ type PreMiddleware func(*Context) error
 type ErrorHandler func(*Context, error)
 
 type routeEntry struct {
-	method     string
-	host       string
-	pattern    string
-	handler    Handler
-	middleware []Middleware
-	name       string
-	timeout    time.Duration
+	method       string
+	host         string
+	pattern      string
+	handler      Handler
+	middleware   []Middleware
+	name         string
+	timeout      time.Duration
+	errorHandler ErrorHandler
+	schemas      routeSchemas
+	security     []map[string][]string
 }
 
 // RouteInfo describes a named route.
@@ -83,20 +86,57 @@ type App struct {
 	templateFSDir    string
 	templateFSDevDir string
 	renderer         *render.Engine
+	textRenderer     *render.TextEngine
 	logger           *slog.Logger
+	jsonNaming       JSONNaming
 	config           config.Config
 	templateOpts     render.Options
 	errorHandler     ErrorHandler
 	errorTemplates   map[int]string
 	registry         *Registry
 	authHooks        AuthHooks
+	templateData     []TemplateDataFunc
+	autoOptions      bool
+	autoHead         bool
+	multipartLimits  MultipartLimits
+	afterResponse    []AfterResponseFunc
+	requireTemplates bool
+	basePath         string
 }
 
 // Option customizes the app instance.
 type Option func(*App)
 
-// New creates a new App with defaults.
+// ErrTemplatesRequired indicates WithRequireTemplates was set without a
+// template source.
+var ErrTemplatesRequired = errors.New("templates required but no templates dir or fs configured")
+
+// New creates a new App with defaults. Template load failures are logged;
+// use NewErr to treat them as fatal.
 func New(options ...Option) *App {
+	app, _ := newApp(options)
+	return app
+}
+
+// NewErr is like New but, with WithRequireTemplates, returns the template
+// load error so misconfigured template dirs fail at startup.
+func NewErr(options ...Option) (*App, error) {
+	app, err := newApp(options)
+	if err != nil && app.requireTemplates {
+		return nil, err
+	}
+	return app, nil
+}
+
+// WithRequireTemplates makes NewErr fail when templates cannot be loaded or
+// no template source is configured.
+func WithRequireTemplates() Option {
+	return func(app *App) {
+		app.requireTemplates = true
+	}
+}
+
+func newApp(options []Option) (*App, error) {
 	cfg := config.Default()
 
 	app := &App{
@@ -106,7 +146,7 @@ func New(options ...Option) *App {
 		renderer:       nil,
 		logger:         nil,
 		config:         cfg,
-		templateOpts:   render.Options{Layout: cfg.LayoutTemplate, Reload: cfg.TemplateReload},
+		templateOpts:   render.Options{Layout: cfg.LayoutTemplate, Reload: cfg.TemplateReload, Watch: true},
 		errorHandler:   defaultErrorHandler,
 		errorTemplates: nil,
 		registry:       NewRegistry(),
@@ -122,6 +162,8 @@ func New(options ...Option) *App {
 	}
 
 	if app.renderer == nil {
+		var engine *render.Engine
+		var err error
 		if app.templateFS != nil {
 			opts := app.templateOpts
 			if opts.Reload {
@@ -131,23 +173,20 @@ func New(options ...Option) *App {
 					opts.DevDir = app.config.TemplatesDir
 				}
 			}
-			engine, err := render.NewEngineFromFS(app.templateFS, app.templateFSDir, opts)
-			if err != nil {
-				app.logger.Error("template load failed", slog.String("error", err.Error()))
-			} else {
-				app.renderer = engine
-			}
+			engine, err = render.NewEngineFromFS(app.templateFS, app.templateFSDir, opts)
 		} else if app.config.TemplatesDir != "" {
-			engine, err := render.NewEngineWithOptions(app.config.TemplatesDir, app.templateOpts)
-			if err != nil {
-				app.logger.Error("template load failed", slog.String("error", err.Error()))
-			} else {
-				app.renderer = engine
-			}
+			engine, err = render.NewEngineWithOptions(app.config.TemplatesDir, app.templateOpts)
+		} else if app.requireTemplates {
+			return app, ErrTemplatesRequired
 		}
+		if err != nil {
+			app.logger.Error("template load failed", slog.String("error", err.Error()))
+			return app, fmt.Errorf("template load failed: %w", err)
+		}
+		app.renderer = engine
 	}
 
-	return app
+	return app, nil
 }
 
 // WithConfig overrides the default config.
@@ -159,10 +198,30 @@ func WithConfig(cfg config.Config) Option {
 	}
 }
 
-// WithLogger uses a custom logger.
+// WithBasePath serves the app under prefix, e.g. "/api/v1" behind a reverse
+// proxy. The prefix is stripped from request paths before routing (requests
+// outside it get a 404), so routes are registered without it; App.Path and
+// AddOpenAPIRoutes add it back.
+func WithBasePath(prefix string) Option {
+	return func(app *App) {
+		app.basePath = cleanPrefix(prefix)
+		if app.basePath == "/" {
+			app.basePath = ""
+		}
+	}
+}
+
+// BasePath returns the prefix configured with WithBasePath.
+func (a *App) BasePath() string {
+	return a.basePath
+}
+
+// WithLogger uses a custom logger. A nil logger is ignored.
 func WithLogger(logger *slog.Logger) Option {
 	return func(app *App) {
-		app.logger = logger
+		if logger != nil {
+			app.logger = logger
+		}
 	}
 }
 
@@ -173,6 +232,14 @@ func WithRenderer(engine *render.Engine) Option {
 	}
 }
 
+// WithTextRenderer sets a plain-text template engine for ctx.RenderText,
+// e.g. for email bodies. ctx.HTML keeps using the HTML engine.
+func WithTextRenderer(engine *render.TextEngine) Option {
+	return func(app *App) {
+		app.textRenderer = engine
+	}
+}
+
 // WithTemplateFuncs registers template functions for the built-in renderer.
 func WithTemplateFuncs(funcs render.FuncMap) Option {
 	return func(app *App) {
@@ -189,13 +256,22 @@ func WithTemplateFuncs(funcs render.FuncMap) Option {
 	}
 }
 
-// WithTemplateReload enables template reloading for development.
+// WithTemplateReload enables template reloading for development. Templates
+// on disk are watched and re-parsed only after a change (see WithTemplateWatch).
 func WithTemplateReload(enabled bool) Option {
 	return func(app *App) {
 		app.templateOpts.Reload = enabled
 	}
 }
 
+// WithTemplateWatch toggles the file watcher used by template reloading
+// (enabled by default). Disabled, every render re-parses the templates.
+func WithTemplateWatch(enabled bool) Option {
+	return func(app *App) {
+		app.templateOpts.Watch = enabled
+	}
+}
+
 // WithTemplatePartials configures glob patterns for partial templates.
 func WithTemplatePartials(patterns ...string) Option {
 	return func(app *App) {
@@ -203,6 +279,25 @@ func WithTemplatePartials(patterns ...string) Option {
 	}
 }
 
+// WithTemplateLayouts registers additional layouts selectable with ctx.HTMLWithLayout.
+func WithTemplateLayouts(layouts ...string) Option {
+	return func(app *App) {
+		app.templateOpts.Layouts = append([]string{}, layouts...)
+	}
+}
+
+// WithTemplatePageLayouts assigns layouts to pages by glob, e.g.
+// {"admin/**": "admin/layout.html"}; ctx.HTML then wraps matching pages in
+// their layout instead of the default.
+func WithTemplatePageLayouts(layouts map[string]string) Option {
+	return func(app *App) {
+		app.templateOpts.PageLayouts = make(map[string]string, len(layouts))
+		for pattern, layout := range layouts {
+			app.templateOpts.PageLayouts[pattern] = layout
+		}
+	}
+}
+
 // WithTemplateSubdirs enables nested template directories.
 func WithTemplateSubdirs(enabled bool) Option {
 	return func(app *App) {
@@ -258,6 +353,23 @@ func WithAuthHooks(hooks AuthHooks) Option {
 	}
 }
 
+// WithAutoOptions answers OPTIONS requests for known paths with 204 and an Allow
+// header, without registering OPTIONS handlers.
+func WithAutoOptions(enabled bool) Option {
+	return func(app *App) {
+		app.autoOptions = enabled
+	}
+}
+
+// WithAutoHead serves HEAD requests with the matching GET handler when no HEAD
+// route is registered. The response body is discarded; headers and
+// Content-Length are preserved.
+func WithAutoHead(enabled bool) Option {
+	return func(app *App) {
+		app.autoHead = enabled
+	}
+}
+
 // Registry returns the app registry.
 func (a *App) Registry() *Registry {
 	if a.registry == nil {
@@ -361,7 +473,14 @@ func (a *App) Path(name string, params map[string]string) (string, bool) {
 	if !ok {
 		return "", false
 	}
-	return buildPath(info.Pattern, params)
+	path, ok := buildPath(info.Pattern, params)
+	if !ok || a.basePath == "" {
+		return path, ok
+	}
+	if path == "/" {
+		return a.basePath, true
+	}
+	return a.basePath + path, true
 }
 
 func (a *App) handleWithOptions(method, path string, handler Handler, middleware []Middleware, options ...RouteOption) {
@@ -387,13 +506,16 @@ func (a *App) handleWithOptions(method, path string, handler Handler, middleware
 	combined = append(combined, cfg.middleware...)
 
 	a.routes[id] = &routeEntry{
-		method:     method,
-		host:       cfg.host,
-		pattern:    path,
-		handler:    handler,
-		middleware: combined,
-		name:       cfg.name,
-		timeout:    cfg.timeout,
+		method:       method,
+		host:         cfg.host,
+		pattern:      path,
+		handler:      handler,
+		middleware:   combined,
+		name:         cfg.name,
+		timeout:      cfg.timeout,
+		errorHandler: cfg.errorHandler,
+		schemas:      cfg.schemas,
+		security:     cfg.security,
 	}
 
 	if cfg.name != "" {
@@ -404,18 +526,46 @@ func (a *App) handleWithOptions(method, path string, handler Handler, middleware
 // ServeHTTP implements http.Handler.
 func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := NewContext(w, r, router.Params{}, a)
+	defer a.trackResponse(ctx)()
 	if err := a.runPreMiddleware(ctx); err != nil {
 		a.errorHandler(ctx, err)
 		return
 	}
+	if a.basePath != "" {
+		if !hasPathPrefix(ctx.Request.URL.Path, a.basePath) {
+			a.runWithMiddleware(ctx, func(ctx *Context) error {
+				return apperr.NotFound("not found", nil)
+			})
+			return
+		}
+		ctx.Request = stripPrefix(ctx.Request, a.basePath)
+	}
 	r = ctx.Request
 
 	reqHost := requestHost(r)
 	id, params, ok := a.router.MatchHost(r.Method, reqHost, r.URL.Path)
+	autoHead := false
+	if !ok && a.autoHead && r.Method == http.MethodHead {
+		id, params, ok = a.router.MatchHost(http.MethodGet, reqHost, r.URL.Path)
+		autoHead = ok
+	}
 	if !ok {
 		allowed := a.router.AllowedHost(reqHost, r.URL.Path)
 		if len(allowed) > 0 {
+			if a.autoHead && containsMethod(allowed, http.MethodGet) {
+				allowed = appendMethod(allowed, http.MethodHead)
+			}
+			if a.autoOptions {
+				allowed = appendMethod(allowed, http.MethodOptions)
+			}
 			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			if a.autoOptions && r.Method == http.MethodOptions {
+				a.runWithMiddleware(ctx, func(ctx *Context) error {
+					ctx.ResponseWriter.WriteHeader(http.StatusNoContent)
+					return nil
+				})
+				return
+			}
 			a.runWithMiddleware(ctx, func(ctx *Context) error {
 				return apperr.MethodNotAllowed("method not allowed", nil)
 			})
@@ -429,6 +579,8 @@ func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	entry := a.routes[id]
 	ctx.Params = params
+	ctx.errorHandler = entry.errorHandler
+	routeInfoKey.Set(ctx, RouteInfo{Name: entry.name, Method: entry.method, Host: entry.host, Pattern: entry.pattern})
 
 	h := entry.handler
 	for i := len(entry.middleware) - 1; i >= 0; i-- {
@@ -441,8 +593,14 @@ func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h = TimeoutHandler(h, entry.timeout)
 	}
 
-	if err := h(ctx); err != nil {
-		a.errorHandler(ctx, err)
+	if autoHead {
+		writer := newHeadResponseWriter(ctx.ResponseWriter)
+		ctx.ResponseWriter = writer
+		defer writer.finish()
+	}
+
+	if err := h(ctx); err != nil && !ctx.aborted {
+		ctx.handleError(err)
 	}
 }
 
@@ -480,7 +638,10 @@ func (a *App) Run(ctx context.Context) error {
 
 	select {
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+		if a.config.ShutdownTimeout <= 0 {
+			a.logger.Warn("shutdown timeout not set, using default", slog.Duration("timeout", a.ShutdownTimeout()))
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), a.ShutdownTimeout())
 		defer cancel()
 		_ = server.Shutdown(shutdownCtx)
 		err := <-errCh
@@ -516,7 +677,7 @@ func (a *App) runWithMiddleware(ctx *Context, handler Handler) {
 	for i := len(a.middleware) - 1; i >= 0; i-- {
 		handler = a.middleware[i](handler)
 	}
-	if err := handler(ctx); err != nil {
+	if err := handler(ctx); err != nil && !ctx.aborted {
 		a.errorHandler(ctx, err)
 	}
 }
@@ -548,7 +709,7 @@ func defaultErrorHandler(ctx *Context, err error) {
 		fields = validationErrors.Fields
 	}
 
-	if wantsJSON(ctx.Request) {
+	if PrefersJSON(ctx.Request) {
 		payload := map[string]any{
 			"error": map[string]any{
 				"code":    code,
@@ -558,6 +719,9 @@ func defaultErrorHandler(ctx *Context, err error) {
 		if len(fields) > 0 {
 			payload["error"].(map[string]any)["fields"] = fields
 		}
+		if requestID, ok := requestIDKey.Get(ctx); ok && requestID != "" {
+			payload["error"].(map[string]any)["request_id"] = requestID
+		}
 		_ = ctx.JSON(status, payload)
 		return
 	}
@@ -641,13 +805,11 @@ func renderDefaultErrorHTML(w http.ResponseWriter, data ErrorPageData) {
 	fmt.Fprint(w, "</main></body></html>")
 }
 
-func wantsJSON(r *http.Request) bool {
-	accept := r.Header.Get("Accept")
-	return accept == "" || strings.Contains(strings.ToLower(accept), "application/json")
-}
-
 // ShutdownTimeout returns the configured graceful shutdown timeout.
 func (a *App) ShutdownTimeout() time.Duration {
+	if a.config.ShutdownTimeout <= 0 {
+		return config.Default().ShutdownTimeout
+	}
 	return a.config.ShutdownTimeout
 }
 
@@ -675,3 +837,19 @@ func requestHost(r *http.Request) string {
 	}
 	return host
 }
+
+func appendMethod(methods []string, method string) []string {
+	if containsMethod(methods, method) {
+		return methods
+	}
+	return append(methods, method)
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, existing := range methods {
+		if existing == method {
+			return true
+		}
+	}
+	return false
+}
@@ -2,15 +2,22 @@ package bebo
 
 import (
 	"context"
+	"errors"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/devmarvs/bebo/apperr"
+	"github.com/devmarvs/bebo/config"
 	"github.com/devmarvs/bebo/render"
 )
 
@@ -34,6 +41,99 @@ func TestMethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestAutoOptions(t *testing.T) {
+	app := New(WithAutoOptions(true))
+	app.GET("/users/:id", func(ctx *Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	app.DELETE("/users/:id", func(ctx *Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/users/123", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	allow := rec.Header().Get("Allow")
+	for _, method := range []string{http.MethodGet, http.MethodDelete, http.MethodOptions} {
+		if !strings.Contains(allow, method) {
+			t.Fatalf("expected %s in Allow header, got %q", method, allow)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodOptions, "/missing", nil)
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown path, got %d", rec.Code)
+	}
+}
+
+func TestAutoOptionsDisabled(t *testing.T) {
+	app := New()
+	app.GET("/users/:id", func(ctx *Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/users/123", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestAutoHead(t *testing.T) {
+	app := New(WithAutoHead(true))
+	app.GET("/report", func(ctx *Context) error {
+		ctx.ResponseWriter.Header().Set("X-Report", "daily")
+		return ctx.Text(http.StatusOK, "report body")
+	})
+
+	req := httptest.NewRequest(http.MethodHead, "/report", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected empty body, got %q", rec.Body.String())
+	}
+	if rec.Header().Get("X-Report") != "daily" {
+		t.Fatalf("expected handler headers")
+	}
+	if rec.Header().Get("Content-Length") != "11" {
+		t.Fatalf("expected content length 11, got %q", rec.Header().Get("Content-Length"))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/report", nil)
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if allow := rec.Header().Get("Allow"); !strings.Contains(allow, http.MethodHead) {
+		t.Fatalf("expected HEAD in Allow header, got %q", allow)
+	}
+}
+
+func TestAutoHeadDisabled(t *testing.T) {
+	app := New()
+	app.GET("/report", func(ctx *Context) error {
+		return ctx.Text(http.StatusOK, "report body")
+	})
+
+	req := httptest.NewRequest(http.MethodHead, "/report", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
+
 func TestPath(t *testing.T) {
 	app := New()
 	app.Route(http.MethodGet, "/users/:id", func(ctx *Context) error {
@@ -96,6 +196,34 @@ func TestErrorPageHTML(t *testing.T) {
 	}
 }
 
+func TestJSONErrorRequestIDOnlyWhenRecorded(t *testing.T) {
+	app := New()
+	app.GET("/plain", func(ctx *Context) error {
+		return apperr.NotFound("missing", nil)
+	})
+	app.GET("/recorded", func(ctx *Context) error {
+		SetRequestID(ctx, "req-1")
+		return apperr.NotFound("missing", nil)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/plain", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(RequestIDHeader, "client-id")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if strings.Contains(rec.Body.String(), "request_id") {
+		t.Fatalf("expected no request_id without a recorded id, got %s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/recorded", nil)
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"request_id":"req-1"`) {
+		t.Fatalf("expected recorded request_id, got %s", rec.Body.String())
+	}
+}
+
 type captureHandler struct {
 	mu     sync.Mutex
 	levels []slog.Level
@@ -149,3 +277,125 @@ func TestNotFoundLoggingLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestRunZeroShutdownTimeoutDrainsRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	cfg := config.Default()
+	cfg.Address = addr
+	cfg.ShutdownTimeout = 0
+	handler := &captureHandler{}
+	app := New(WithConfig(cfg), WithLogger(slog.New(handler)))
+	if got := app.ShutdownTimeout(); got != config.Default().ShutdownTimeout {
+		t.Fatalf("expected default shutdown timeout, got %v", got)
+	}
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	app.GET("/slow", func(ctx *Context) error {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		finished.Store(true)
+		return ctx.Text(http.StatusOK, "done")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- app.Run(ctx) }()
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			resp, err := http.Get("http://" + addr + "/slow")
+			if err == nil {
+				resp.Body.Close()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("request did not reach the handler")
+	}
+	cancel()
+
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return")
+	}
+	if !finished.Load() {
+		t.Fatal("expected Run to wait for the in-flight request")
+	}
+
+	warned := false
+	for _, level := range handler.Levels() {
+		if level == slog.LevelWarn {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Fatal("expected a warning about the missing shutdown timeout")
+	}
+}
+
+func TestNewErrRequireTemplates(t *testing.T) {
+	quiet := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	cfg := config.Default()
+	cfg.TemplatesDir = filepath.Join(t.TempDir(), "missing")
+	app, err := NewErr(WithConfig(cfg), WithLogger(quiet), WithRequireTemplates())
+	if err == nil || app != nil {
+		t.Fatalf("expected error for missing template dir, got %v", err)
+	}
+
+	if _, err := NewErr(WithLogger(quiet), WithRequireTemplates()); !errors.Is(err, ErrTemplatesRequired) {
+		t.Fatalf("expected ErrTemplatesRequired, got %v", err)
+	}
+
+	// Without the option NewErr keeps New's lenient behavior.
+	if app, err := NewErr(WithConfig(cfg), WithLogger(quiet)); err != nil || app == nil {
+		t.Fatalf("expected lenient app, got %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "home.html"), []byte("ok"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	cfg.TemplatesDir = dir
+	app, err = NewErr(WithConfig(cfg), WithLogger(quiet), WithRequireTemplates())
+	if err != nil {
+		t.Fatalf("new err: %v", err)
+	}
+	app.GET("/", func(ctx *Context) error {
+		return ctx.HTML(http.StatusOK, "home.html", nil)
+	})
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Body.String() != "ok" {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+}
+
+func TestNewErrRequireTemplatesBadTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.html"), []byte("{{ if }"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	cfg := config.Default()
+	cfg.TemplatesDir = dir
+	quiet := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if _, err := NewErr(WithConfig(cfg), WithLogger(quiet), WithRequireTemplates()); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
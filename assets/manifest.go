@@ -0,0 +1,113 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// Manifest maps asset paths to content-fingerprinted file names, such as
+// app.css to app.abcd1234.css. Hashes are computed once, when the manifest
+// is built, so build it at startup after assets are in place.
+type Manifest struct {
+	prefix   string
+	hashed   map[string]string
+	original map[string]string
+}
+
+// NewManifest scans dir and fingerprints every file. URLs are built under
+// prefix, e.g. "/static".
+func NewManifest(dir, prefix string) (*Manifest, error) {
+	return NewManifestFS(os.DirFS(dir), prefix)
+}
+
+// NewManifestFS scans fsys and fingerprints every file.
+func NewManifestFS(fsys fs.FS, prefix string) (*Manifest, error) {
+	manifest := &Manifest{
+		prefix:   "/" + strings.Trim(prefix, "/"),
+		hashed:   make(map[string]string),
+		original: make(map[string]string),
+	}
+	if manifest.prefix == "/" {
+		manifest.prefix = ""
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		hash, err := hashFSFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		fingerprinted := fingerprintName(name, hash[:8])
+		manifest.hashed[name] = fingerprinted
+		manifest.original[fingerprinted] = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Path returns the fingerprinted path for assetPath relative to the scanned
+// root, or assetPath unchanged when the file is unknown.
+func (m *Manifest) Path(assetPath string) string {
+	if hashed, ok := m.hashed[cleanAssetPath(assetPath)]; ok {
+		return hashed
+	}
+	return assetPath
+}
+
+// URL returns the prefixed, fingerprinted URL for assetPath. Unknown files
+// get the prefixed original path.
+func (m *Manifest) URL(assetPath string) string {
+	return m.prefix + "/" + cleanAssetPath(m.Path(assetPath))
+}
+
+// Original maps a fingerprinted path back to the file it was built from.
+func (m *Manifest) Original(fingerprinted string) (string, bool) {
+	name, ok := m.original[cleanAssetPath(fingerprinted)]
+	return name, ok
+}
+
+// Func returns a template helper function producing URLs.
+func (m *Manifest) Func() func(string) string {
+	return m.URL
+}
+
+// FuncMap returns an "asset" template function for WithTemplateFuncs.
+func (m *Manifest) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": m.URL}
+}
+
+func cleanAssetPath(assetPath string) string {
+	return strings.TrimPrefix(path.Clean("/"+assetPath), "/")
+}
+
+// fingerprintName inserts hash before the extension: css/app.css becomes
+// css/app.<hash>.css.
+func fingerprintName(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+func hashFSFile(fsys fs.FS, name string) (string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
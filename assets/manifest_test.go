@@ -0,0 +1,64 @@
+package assets
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestManifestFingerprintsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.css":         {Data: []byte("body{}")},
+		"js/app.min.js":   {Data: []byte("console.log(1)")},
+		"img/logo.svg":    {Data: []byte("<svg/>")},
+		"img/logo2.svg":   {Data: []byte("<svg/>")},
+		"fonts/LICENSE":   {Data: []byte("ofl")},
+		"fonts/dir/.keep": {Data: nil},
+	}
+	manifest, err := NewManifestFS(fsys, "/static/")
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+
+	url := manifest.URL("app.css")
+	if !strings.HasPrefix(url, "/static/app.") || !strings.HasSuffix(url, ".css") || len(url) != len("/static/app.12345678.css") {
+		t.Fatalf("unexpected url %q", url)
+	}
+	if got := manifest.URL("/js/app.min.js"); !strings.HasPrefix(got, "/static/js/app.min.") || !strings.HasSuffix(got, ".js") {
+		t.Fatalf("unexpected nested url %q", got)
+	}
+	if manifest.Path("img/logo.svg")[len("img/logo."):] != manifest.Path("img/logo2.svg")[len("img/logo2."):] {
+		t.Fatal("expected identical content to share a hash")
+	}
+	if got := manifest.URL("missing.css"); got != "/static/missing.css" {
+		t.Fatalf("expected unknown asset to pass through, got %q", got)
+	}
+
+	original, ok := manifest.Original(strings.TrimPrefix(url, "/static/"))
+	if !ok || original != "app.css" {
+		t.Fatalf("expected reverse lookup, got %q %v", original, ok)
+	}
+	if _, ok := manifest.Original("app.css"); ok {
+		t.Fatal("expected plain names not to resolve as fingerprints")
+	}
+}
+
+func TestManifestChangesWithContent(t *testing.T) {
+	first, err := NewManifestFS(fstest.MapFS{"app.css": {Data: []byte("a")}}, "")
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	second, err := NewManifestFS(fstest.MapFS{"app.css": {Data: []byte("b")}}, "")
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	if first.URL("app.css") == second.URL("app.css") {
+		t.Fatal("expected hash to change with content")
+	}
+	if !strings.HasPrefix(first.URL("app.css"), "/app.") {
+		t.Fatalf("unexpected root url %q", first.URL("app.css"))
+	}
+	if _, ok := first.FuncMap()["asset"]; !ok {
+		t.Fatal("expected asset func")
+	}
+}
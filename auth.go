@@ -17,19 +17,21 @@ type Authorizer interface {
 	Authorize(*Context, *Principal) error
 }
 
-const principalKey = "bebo.principal"
+var principalKey = NewContextKey[*Principal]("bebo.principal")
 
 // PrincipalFromContext extracts the principal from context storage.
 func PrincipalFromContext(ctx *Context) (*Principal, bool) {
-	value, ok := ctx.Get(principalKey)
-	if !ok {
-		return nil, false
-	}
-	principal, ok := value.(*Principal)
-	return principal, ok
+	return principalKey.Get(ctx)
 }
 
 // SetPrincipal stores the principal in context storage.
 func SetPrincipal(ctx *Context, principal *Principal) {
-	ctx.Set(principalKey, principal)
+	principalKey.Set(ctx, principal)
+}
+
+// Principal returns the authenticated principal, or nil for unauthenticated
+// requests.
+func (c *Context) Principal() *Principal {
+	principal, _ := principalKey.Get(c)
+	return principal
 }
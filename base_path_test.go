@@ -0,0 +1,78 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devmarvs/bebo/openapi"
+)
+
+func newBasePathApp() *App {
+	app := New(WithBasePath("/api/v1/"))
+	app.Route(http.MethodGet, "/users/:id", func(ctx *Context) error {
+		return ctx.Text(http.StatusOK, ctx.Param("id")+" "+ctx.Request.URL.Path)
+	}, WithName("users.show"))
+	app.Route(http.MethodGet, "/", func(ctx *Context) error {
+		return ctx.Text(http.StatusOK, "root")
+	}, WithName("root"))
+	return app
+}
+
+func TestBasePathRouting(t *testing.T) {
+	app := newBasePathApp()
+
+	cases := map[string]struct {
+		status int
+		body   string
+	}{
+		"/api/v1/users/1":  {http.StatusOK, "1 /users/1"},
+		"/api/v1":          {http.StatusOK, "root"},
+		"/api/v1/":         {http.StatusOK, "root"},
+		"/users/1":         {http.StatusNotFound, ""},
+		"/api/v10/users/1": {http.StatusNotFound, ""},
+	}
+	for path, want := range cases {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want.status {
+			t.Fatalf("%s: expected %d, got %d", path, want.status, rec.Code)
+		}
+		if want.body != "" && rec.Body.String() != want.body {
+			t.Fatalf("%s: unexpected body %q", path, rec.Body.String())
+		}
+	}
+}
+
+func TestBasePathReverseRouting(t *testing.T) {
+	app := newBasePathApp()
+
+	if path, ok := app.Path("users.show", map[string]string{"id": "42"}); !ok || path != "/api/v1/users/42" {
+		t.Fatalf("unexpected path %q %v", path, ok)
+	}
+	if path, ok := app.Path("root", nil); !ok || path != "/api/v1" {
+		t.Fatalf("unexpected root path %q %v", path, ok)
+	}
+	if path, ok := app.PathWithQuery("users.show", map[string]string{"id": "7"}, map[string]string{"tab": "posts"}); !ok || path != "/api/v1/users/7?tab=posts" {
+		t.Fatalf("unexpected path with query %q %v", path, ok)
+	}
+	if app.BasePath() != "/api/v1" {
+		t.Fatalf("unexpected base path %q", app.BasePath())
+	}
+}
+
+func TestBasePathOpenAPIServer(t *testing.T) {
+	app := newBasePathApp()
+	spec := openapi.New(openapi.Info{Title: "api", Version: "v1"})
+	if err := app.AddOpenAPIRoutes(spec); err != nil {
+		t.Fatalf("add routes: %v", err)
+	}
+
+	doc := spec.Document()
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "/api/v1" {
+		t.Fatalf("unexpected servers %+v", doc.Servers)
+	}
+	if _, ok := doc.Paths["/users/{id}"]; !ok {
+		t.Fatalf("expected unprefixed path relative to the server, got %v", doc.Paths)
+	}
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// SetJSON stores value encoded as JSON.
+func SetJSON(ctx context.Context, store Store, key string, value any, ttl time.Duration) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return store.Set(ctx, key, payload, ttl)
+}
+
+// GetJSON loads a JSON entry stored by SetJSON. ok is false on a cache miss.
+func GetJSON[T any](ctx context.Context, store Store, key string) (value T, ok bool, err error) {
+	payload, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return value, false, err
+	}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
@@ -68,6 +68,30 @@ func NewRedisStore(options RedisOptions) *RedisStore {
 	return &RedisStore{options: cfg, client: client}
 }
 
+// NewRedis creates a cache store on a client built from options, so pool
+// settings such as MaxIdle and MaxActive apply. Keys use the default
+// "bebo:cache:" prefix; see Namespace.
+func NewRedis(options redis.Options) *RedisStore {
+	return &RedisStore{
+		options: RedisOptions{Prefix: "bebo:cache:"},
+		client:  redis.New(options),
+	}
+}
+
+// Namespace returns a store sharing s's connection pool whose keys are
+// further prefixed with prefix, e.g. store.Namespace("users:").
+func (s *RedisStore) Namespace(prefix string) *RedisStore {
+	options := s.options
+	options.Prefix += prefix
+	return &RedisStore{options: options, client: s.client}
+}
+
+// Close releases the pooled connections, including for namespaced stores
+// sharing them.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
 // Get returns a cache entry by key.
 func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
 	if ctx == nil {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/devmarvs/bebo/internal/redistest"
+	"github.com/devmarvs/bebo/redis"
 )
 
 func TestRedisStoreRoundTrip(t *testing.T) {
@@ -46,3 +47,80 @@ func TestRedisStoreRoundTrip(t *testing.T) {
 		t.Fatalf("expected cache miss after delete")
 	}
 }
+
+func TestRedisStoreTTLExpiry(t *testing.T) {
+	clock := redistest.NewClock()
+	addr, shutdown := redistest.StartWithOptions(t, redistest.Options{Now: clock.Now})
+	defer shutdown()
+
+	store := NewRedisStore(RedisOptions{Address: addr, DefaultTTL: time.Minute})
+	ctx := context.Background()
+	if err := store.Set(ctx, "short", []byte("value"), time.Second); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := store.Set(ctx, "default", []byte("value"), 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	clock.Advance(2 * time.Second)
+	if _, ok, _ := store.Get(ctx, "short"); ok {
+		t.Fatalf("expected short entry to expire")
+	}
+	if _, ok, _ := store.Get(ctx, "default"); !ok {
+		t.Fatalf("expected default ttl entry to remain")
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok, _ := store.Get(ctx, "default"); ok {
+		t.Fatalf("expected default ttl entry to expire")
+	}
+}
+
+func TestNewRedisNamespaceAndJSON(t *testing.T) {
+	addr, shutdown := redistest.Start(t)
+	defer shutdown()
+
+	store := NewRedis(redis.Options{Address: addr, MaxIdle: 2})
+	defer store.Close()
+	users := store.Namespace("users:")
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	ctx := context.Background()
+	if err := SetJSON(ctx, users, "1", user{ID: 1, Name: "Ada"}, time.Minute); err != nil {
+		t.Fatalf("set json: %v", err)
+	}
+
+	loaded, ok, err := GetJSON[user](ctx, users, "1")
+	if err != nil || !ok {
+		t.Fatalf("get json: ok=%v err=%v", ok, err)
+	}
+	if loaded.Name != "Ada" {
+		t.Fatalf("unexpected user %+v", loaded)
+	}
+	if _, ok, _ := store.Get(ctx, "1"); ok {
+		t.Fatalf("expected namespaced key to be hidden from the parent store")
+	}
+
+	raw := redis.New(redis.Options{Address: addr})
+	defer raw.Close()
+	payload, err := raw.Do("GET", "bebo:cache:users:1")
+	if err != nil {
+		t.Fatalf("raw get: %v", err)
+	}
+	if string(payload.([]byte)) != `{"id":1,"name":"Ada"}` {
+		t.Fatalf("unexpected stored payload %s", payload)
+	}
+
+	if _, ok, err := GetJSON[user](ctx, users, "2"); ok || err != nil {
+		t.Fatalf("expected miss, ok=%v err=%v", ok, err)
+	}
+	if err := users.Set(ctx, "bad", []byte("not json"), 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, ok, err := GetJSON[user](ctx, users, "bad"); ok || err == nil {
+		t.Fatalf("expected decode error, ok=%v err=%v", ok, err)
+	}
+}
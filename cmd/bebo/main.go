@@ -13,6 +13,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/devmarvs/bebo"
 	"github.com/devmarvs/bebo/migrate"
 )
 
@@ -31,6 +32,8 @@ func main() {
 		crudCmd(os.Args[2:])
 	case "migrate":
 		migrateCmd(os.Args[2:])
+	case "key":
+		keyCmd(os.Args[2:])
 	default:
 		usage()
 	}
@@ -42,10 +45,21 @@ func usage() {
 	fmt.Println("  bebo new <dir> -module <module> [-version v0.0.0] [-api|-web|-desktop] [-template] [-profile]")
 	fmt.Println("  bebo route add -method GET -path /users/:id [-name user.show]")
 	fmt.Println("  bebo crud new <resource> [-dir handlers] [-package handlers] [-templates templates] [-tests=true]")
-	fmt.Println("  bebo migrate new -dir ./migrations -name create_users")
+	fmt.Println("  bebo migrate new -dir ./migrations -name create_users [-template create-table|add-column|add-index|drop-table -table users -columns email:text:notnull]")
 	fmt.Println("  bebo migrate plan -dir ./migrations [-driver postgres -dsn <dsn>]")
 	fmt.Println("  bebo migrate up -dir ./migrations -driver postgres -dsn <dsn> [-lock-id 0]")
 	fmt.Println("  bebo migrate down -dir ./migrations -driver postgres -dsn <dsn> -steps 1 [-lock-id 0]")
+	fmt.Println("  bebo migrate seed -dir ./seeds -driver postgres -dsn <dsn> [-lock-id 0]")
+	fmt.Println("  bebo key [-bytes 32]")
+}
+
+func keyCmd(args []string) {
+	fs := flag.NewFlagSet("key", flag.ExitOnError)
+	size := fs.Int("bytes", bebo.MinKeyLength, "Key size in bytes")
+	_ = fs.Parse(args)
+
+	_, encoded := bebo.GenerateKey(*size)
+	fmt.Println(encoded)
 }
 
 func newCmd(args []string) {
@@ -235,7 +249,7 @@ func crudNewCmd(args []string) {
 
 func migrateCmd(args []string) {
 	if len(args) == 0 {
-		fmt.Println("usage: bebo migrate <new|plan|up|down> ...")
+		fmt.Println("usage: bebo migrate <new|plan|up|down|seed> ...")
 		return
 	}
 
@@ -248,8 +262,10 @@ func migrateCmd(args []string) {
 		migrateUpCmd(args[1:])
 	case "down":
 		migrateDownCmd(args[1:])
+	case "seed":
+		migrateSeedCmd(args[1:])
 	default:
-		fmt.Println("usage: bebo migrate <new|plan|up|down> ...")
+		fmt.Println("usage: bebo migrate <new|plan|up|down|seed> ...")
 	}
 }
 
@@ -257,13 +273,33 @@ func migrateNewCmd(args []string) {
 	fs := flag.NewFlagSet("migrate new", flag.ExitOnError)
 	dir := fs.String("dir", "migrations", "Migrations directory")
 	name := fs.String("name", "", "Migration name")
+	template := fs.String("template", "", "Scaffold template (create-table, add-column, add-index, drop-table)")
+	table := fs.String("table", "", "Table name for the template")
+	columns := fs.String("columns", "", "Columns as name:type[:notnull|unique|pk], comma separated")
+	index := fs.String("index", "", "Index name for add-index")
+	unique := fs.Bool("unique", false, "Create a unique index for add-index")
 	_ = fs.Parse(args)
 
 	if *name == "" {
-		fmt.Println("usage: bebo migrate new -dir ./migrations -name create_users")
+		fmt.Println("usage: bebo migrate new -dir ./migrations -name create_users [-template create-table -table users -columns \"email:text:notnull\"]")
 		return
 	}
 
+	parsedColumns, err := migrate.ParseColumns(*columns)
+	if err != nil {
+		fatal(err)
+	}
+	upSQL, downSQL, err := migrate.Scaffold(migrate.ScaffoldOptions{
+		Template: *template,
+		Table:    *table,
+		Columns:  parsedColumns,
+		Index:    *index,
+		Unique:   *unique,
+	})
+	if err != nil {
+		fatal(err)
+	}
+
 	if err := os.MkdirAll(*dir, 0o755); err != nil {
 		fatal(err)
 	}
@@ -275,10 +311,10 @@ func migrateNewCmd(args []string) {
 	upPath := filepath.Join(*dir, base+".up.sql")
 	downPath := filepath.Join(*dir, base+".down.sql")
 
-	if err := writeFile(upPath, "-- write migration here\n"); err != nil {
+	if err := writeFile(upPath, upSQL); err != nil {
 		fatal(err)
 	}
-	if err := writeFile(downPath, "-- rollback migration here\n"); err != nil {
+	if err := writeFile(downPath, downSQL); err != nil {
 		fatal(err)
 	}
 
@@ -288,7 +324,7 @@ func migrateNewCmd(args []string) {
 
 func migratePlanCmd(args []string) {
 	fs := flag.NewFlagSet("migrate plan", flag.ExitOnError)
-	dir := fs.String("dir", "migrations", "Migrations directory")
+	dir := fs.String("dir", "migrations", "Migrations directories, comma separated")
 	driver := fs.String("driver", "", "Database driver")
 	dsn := fs.String("dsn", "", "Database DSN")
 	_ = fs.Parse(args)
@@ -303,7 +339,7 @@ func migratePlanCmd(args []string) {
 		defer db.Close()
 	}
 
-	runner := migrate.New(db, *dir)
+	runner := migrationRunner(db, *dir)
 	plan, err := runner.Plan(context.Background())
 	if err != nil {
 		fatal(err)
@@ -320,7 +356,7 @@ func migratePlanCmd(args []string) {
 
 func migrateUpCmd(args []string) {
 	fs := flag.NewFlagSet("migrate up", flag.ExitOnError)
-	dir := fs.String("dir", "migrations", "Migrations directory")
+	dir := fs.String("dir", "migrations", "Migrations directories, comma separated")
 	driver := fs.String("driver", "", "Database driver")
 	dsn := fs.String("dsn", "", "Database DSN")
 	lockID := fs.Int64("lock-id", 0, "Postgres advisory lock ID")
@@ -337,7 +373,7 @@ func migrateUpCmd(args []string) {
 	}
 	defer db.Close()
 
-	runner := migrate.New(db, *dir)
+	runner := migrationRunner(db, *dir)
 	if *lockID != 0 {
 		runner.Locker = migrate.AdvisoryLocker{ID: *lockID}
 	}
@@ -351,7 +387,7 @@ func migrateUpCmd(args []string) {
 
 func migrateDownCmd(args []string) {
 	fs := flag.NewFlagSet("migrate down", flag.ExitOnError)
-	dir := fs.String("dir", "migrations", "Migrations directory")
+	dir := fs.String("dir", "migrations", "Migrations directories, comma separated")
 	driver := fs.String("driver", "", "Database driver")
 	dsn := fs.String("dsn", "", "Database DSN")
 	steps := fs.Int("steps", 1, "Number of migrations to rollback")
@@ -369,7 +405,7 @@ func migrateDownCmd(args []string) {
 	}
 	defer db.Close()
 
-	runner := migrate.New(db, *dir)
+	runner := migrationRunner(db, *dir)
 	if *lockID != 0 {
 		runner.Locker = migrate.AdvisoryLocker{ID: *lockID}
 	}
@@ -381,6 +417,49 @@ func migrateDownCmd(args []string) {
 	fmt.Printf("rolled back %d migrations\n", count)
 }
 
+// migrationRunner builds a runner for dir, which may list comma-separated
+// directories to merge, such as "migrations,migrations/test".
+func migrationRunner(db *sql.DB, dir string) *migrate.Runner {
+	var dirs []string
+	for _, part := range strings.Split(dir, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			dirs = append(dirs, part)
+		}
+	}
+	return migrate.NewMulti(db, dirs...)
+}
+
+func migrateSeedCmd(args []string) {
+	fs := flag.NewFlagSet("migrate seed", flag.ExitOnError)
+	dir := fs.String("dir", "seeds", "Seeds directory")
+	driver := fs.String("driver", "", "Database driver")
+	dsn := fs.String("dsn", "", "Database DSN")
+	lockID := fs.Int64("lock-id", 0, "Postgres advisory lock ID")
+	_ = fs.Parse(args)
+
+	if *driver == "" || *dsn == "" {
+		fmt.Println("usage: bebo migrate seed -dir ./seeds -driver postgres -dsn <dsn> [-lock-id 0]")
+		return
+	}
+
+	db, err := sql.Open(*driver, *dsn)
+	if err != nil {
+		fatal(err)
+	}
+	defer db.Close()
+
+	runner := migrate.New(db, "")
+	if *lockID != 0 {
+		runner.Locker = migrate.AdvisoryLocker{ID: *lockID}
+	}
+
+	count, err := runner.Seed(context.Background(), *dir)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("applied %d seeds\n", count)
+}
+
 func goMod(module, version string) string {
 	return fmt.Sprintf("module %s\n\ngo 1.23\n\nrequire github.com/devmarvs/bebo %s\n", module, version)
 }
@@ -832,7 +911,6 @@ func crudHandlerTemplate(pkg, singular, plural string) string {
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/devmarvs/bebo"
 )
@@ -863,7 +941,7 @@ func %s(ctx *bebo.Context) error {
 		Title: "%s",
 		Items: items,
 	}
-	if acceptsHTML(ctx.Request) {
+	if bebo.PrefersHTML(ctx.Request) {
 		if err := ctx.HTML(http.StatusOK, "%s/index.html", data); err == nil {
 			return nil
 		}
@@ -878,7 +956,7 @@ func %s(ctx *bebo.Context) error {
 		Title: "%s",
 		Item:  item,
 	}
-	if acceptsHTML(ctx.Request) {
+	if bebo.PrefersHTML(ctx.Request) {
 		if err := ctx.HTML(http.StatusOK, "%s/show.html", data); err == nil {
 			return nil
 		}
@@ -890,7 +968,7 @@ func %s(ctx *bebo.Context) error {
 	data := %s{
 		Title: "%s",
 	}
-	if acceptsHTML(ctx.Request) {
+	if bebo.PrefersHTML(ctx.Request) {
 		if err := ctx.HTML(http.StatusOK, "%s/new.html", data); err == nil {
 			return nil
 		}
@@ -905,7 +983,7 @@ func %s(ctx *bebo.Context) error {
 		Title: "%s",
 		Item:  item,
 	}
-	if acceptsHTML(ctx.Request) {
+	if bebo.PrefersHTML(ctx.Request) {
 		if err := ctx.HTML(http.StatusOK, "%s/edit.html", data); err == nil {
 			return nil
 		}
@@ -926,11 +1004,6 @@ func %s(ctx *bebo.Context) error {
 	ctx.ResponseWriter.WriteHeader(http.StatusNoContent)
 	return nil
 }
-
-func acceptsHTML(r *http.Request) bool {
-	accept := strings.ToLower(r.Header.Get("Accept"))
-	return strings.Contains(accept, "text/html")
-}
 `, pkg,
 		typeName,
 		pageType, typeName, typeName,
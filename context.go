@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -11,6 +13,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/devmarvs/bebo/apperr"
 	"github.com/devmarvs/bebo/render"
@@ -23,8 +26,12 @@ type Context struct {
 	Request        *http.Request
 	Params         router.Params
 
-	app    *App
-	values map[string]any
+	app          *App
+	values       map[any]any
+	funcs        render.FuncMap
+	errorHandler ErrorHandler
+	aborted      bool
+	hijacked     bool
 }
 
 // NewContext constructs a Context.
@@ -34,11 +41,10 @@ func NewContext(w http.ResponseWriter, r *http.Request, params router.Params, ap
 		Request:        r,
 		Params:         params,
 		app:            app,
-		values:         make(map[string]any),
+		values:         make(map[any]any),
 	}
 }
 
-
 // App returns the owning app instance when available.
 func (c *Context) App() *App {
 	return c.app
@@ -151,14 +157,27 @@ func (c *Context) Get(key string) (any, bool) {
 	return value, ok
 }
 
+// GetOr retrieves a stored value, or fallback when the key is unset.
+// Prefer a ContextKey for values shared across packages.
+func (c *Context) GetOr(key string, fallback any) any {
+	if value, ok := c.values[key]; ok {
+		return value
+	}
+	return fallback
+}
+
 // Logger returns the app logger.
 func (c *Context) Logger() Logger {
-	return LoggerFromRequest(c.Request, c.app.logger)
+	var logger *slog.Logger
+	if c.app != nil {
+		logger = c.app.logger
+	}
+	return LoggerFromRequest(c.Request, logger)
 }
 
 // JSON responds with JSON.
 func (c *Context) JSON(status int, payload any) error {
-	return render.JSON(c.ResponseWriter, status, payload)
+	return render.JSON(c.ResponseWriter, status, c.jsonPayload(payload))
 }
 
 // Text responds with plain text.
@@ -171,11 +190,73 @@ func (c *Context) HTML(status int, name string, data any) error {
 	if c.app.renderer == nil {
 		return apperr.Internal("template engine not configured", nil)
 	}
-	return c.app.renderer.Render(c.ResponseWriter, status, name, data)
+	data, err := c.templateData(data)
+	if err != nil {
+		return err
+	}
+	return c.app.renderer.RenderWithFuncs(c.ResponseWriter, status, "", c.app.renderer.LocalizedName(name, c.Locale()), data, c.funcs)
+}
+
+// HTMLWithLayout renders a template wrapped in the named layout.
+// The layout must be configured with WithTemplateLayouts (or render.Options.Layouts).
+func (c *Context) HTMLWithLayout(status int, layout, name string, data any) error {
+	if c.app.renderer == nil {
+		return apperr.Internal("template engine not configured", nil)
+	}
+	data, err := c.templateData(data)
+	if err != nil {
+		return err
+	}
+	return c.app.renderer.RenderWithFuncs(c.ResponseWriter, status, layout, c.app.renderer.LocalizedName(name, c.Locale()), data, c.funcs)
+}
+
+// AddTemplateFuncs registers template funcs for the rest of this request;
+// ctx.HTML, HTMLWithLayout and RenderString pass them to the engine, where
+// they override same-named engine funcs. Templates still parse against the
+// engine's funcs, so register a placeholder there (see WithTemplateFuncs).
+// Only pages that call an added func pay for the per-request clone.
+func (c *Context) AddTemplateFuncs(funcs render.FuncMap) {
+	if c.funcs == nil {
+		c.funcs = make(render.FuncMap, len(funcs))
+	}
+	for name, fn := range funcs {
+		c.funcs[name] = fn
+	}
+}
+
+// RenderString renders a template with the default layout into a string
+// without touching the response, applying shared template data and locale
+// selection like HTML.
+func (c *Context) RenderString(name string, data any) (string, error) {
+	if c.app.renderer == nil {
+		return "", apperr.Internal("template engine not configured", nil)
+	}
+	data, err := c.templateData(data)
+	if err != nil {
+		return "", err
+	}
+	out, err := c.app.renderer.RenderToBytesWithFuncs("", c.app.renderer.LocalizedName(name, c.Locale()), data, c.funcs)
+	return string(out), err
+}
+
+// RenderText renders a plain-text template from the engine set with
+// WithTextRenderer into a string, without HTML escaping.
+func (c *Context) RenderText(name string, data any) (string, error) {
+	if c.app.textRenderer == nil {
+		return "", apperr.Internal("text template engine not configured", nil)
+	}
+	data, err := c.templateData(data)
+	if err != nil {
+		return "", err
+	}
+	return c.app.textRenderer.RenderToString(c.app.textRenderer.LocalizedName(name, c.Locale()), data)
 }
 
 // BindJSON binds the request body to a struct.
 func (c *Context) BindJSON(dst any) error {
+	if err := validateBodyFraming(c.Request, c.bodyLimit()); err != nil {
+		return err
+	}
 	decoder := json.NewDecoder(c.Request.Body)
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(dst); err != nil {
@@ -195,6 +276,9 @@ const DefaultMultipartMemory int64 = 32 << 20
 
 // BindForm binds URL-encoded form values into dst.
 func (c *Context) BindForm(dst any) error {
+	if err := validateBodyFraming(c.Request, c.bodyLimit()); err != nil {
+		return err
+	}
 	if err := c.Request.ParseForm(); err != nil {
 		return apperr.BadRequest("invalid form", err)
 	}
@@ -205,13 +289,17 @@ func (c *Context) BindForm(dst any) error {
 	return bindValues(values, dst)
 }
 
-// BindMultipart binds multipart form values into dst.
+// BindMultipart binds multipart form values into dst. The number of parts and
+// fields is capped by MultipartLimits (see WithMultipartLimits).
 func (c *Context) BindMultipart(dst any, maxMemory int64) error {
 	if maxMemory <= 0 {
 		maxMemory = DefaultMultipartMemory
 	}
-	if err := c.Request.ParseMultipartForm(maxMemory); err != nil {
-		return apperr.BadRequest("invalid multipart form", err)
+	if err := validateBodyFraming(c.Request, c.bodyLimit()); err != nil {
+		return err
+	}
+	if err := c.parseMultipart(maxMemory); err != nil {
+		return err
 	}
 	values := url.Values{}
 	if c.Request.MultipartForm != nil {
@@ -222,13 +310,168 @@ func (c *Context) BindMultipart(dst any, maxMemory int64) error {
 	return bindValues(values, dst)
 }
 
+// BindParams binds route params into dst using `param` tags (falling back to field names).
+// Fields with an explicit `param` tag are required.
+func (c *Context) BindParams(dst any) error {
+	return bindValuesWith(paramValues(c.Params), dst, paramFieldName)
+}
+
+// BindQuery binds query values into dst using `query` tags (falling back to form/json tags and field names).
+func (c *Context) BindQuery(dst any) error {
+	return bindValuesWith(c.Request.URL.Query(), dst, queryFieldName)
+}
+
+// BindAll binds path params (`param` tags), query values (`query` tags), and the request
+// body (`json`/`form` tags) into dst. Sources are applied in that order, so later sources
+// override earlier ones where a field carries more than one tag.
+// The body is decoded by Content-Type: form and multipart bodies use form binding,
+// anything else is treated as JSON. Requests without a body skip the body step.
+// Fields tagged only `param` or `query` (no `json`/`form` tag) are never set from
+// the body, so a body key such as "id" cannot override a path parameter.
+func (c *Context) BindAll(dst any) error {
+	if err := bindValuesWith(paramValues(c.Params), dst, taggedOnly("param", paramFieldName)); err != nil {
+		return err
+	}
+	if err := bindValuesWith(c.Request.URL.Query(), dst, taggedOnly("query", queryFieldName)); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(dst).Elem()
+	if rv.Kind() != reflect.Struct {
+		return c.bindBody(dst)
+	}
+	saved := map[string]reflect.Value{}
+	walkSourceOnlyFields(rv, "", func(key string, field reflect.Value) {
+		value := reflect.New(field.Type()).Elem()
+		value.Set(field)
+		saved[key] = value
+	})
+	if err := c.bindBody(dst); err != nil {
+		return err
+	}
+	walkSourceOnlyFields(rv, "", func(key string, field reflect.Value) {
+		if value, ok := saved[key]; ok {
+			field.Set(value)
+			return
+		}
+		field.SetZero()
+	})
+	return nil
+}
+
+// walkSourceOnlyFields visits the settable fields of rv that carry a `param` or
+// `query` tag but no `json`/`form` tag, descending into non-nil embedded structs.
+// Keys identify fields by their index path.
+func walkSourceOnlyFields(rv reflect.Value, prefix string, visit func(key string, field reflect.Value)) {
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+		key := prefix + "." + strconv.Itoa(i)
+		if isEmbeddedStruct(field) {
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			walkSourceOnlyFields(value, key, visit)
+			continue
+		}
+		if field.PkgPath != "" || !value.CanSet() {
+			continue
+		}
+		_, param := field.Tag.Lookup("param")
+		_, query := field.Tag.Lookup("query")
+		_, jsonTag := field.Tag.Lookup("json")
+		_, form := field.Tag.Lookup("form")
+		if (param || query) && !jsonTag && !form {
+			visit(key, value)
+		}
+	}
+}
+
+// BindPatch binds the request body for partial updates. Declare dst fields as
+// pointers: keys absent from the body leave the pointer nil, while present keys
+// (including zero values such as 0, false or "") allocate it. Use PresentFields
+// to list the provided fields. A JSON null is treated as absent.
+func (c *Context) BindPatch(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return apperr.BadRequest("destination must be a pointer to a struct", nil)
+	}
+	return c.bindBody(dst)
+}
+
+// PresentFields returns the bind names (json/form tag, else field name) of the
+// non-nil pointer fields in dst, i.e. the fields provided to BindPatch.
+func PresentFields(dst any) []string {
+	rv := reflect.ValueOf(dst)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	return appendPresentFields(nil, rv)
+}
+
+func appendPresentFields(names []string, rv reflect.Value) []string {
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+		if isEmbeddedStruct(field) {
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			names = appendPresentFields(names, value)
+			continue
+		}
+		if field.PkgPath != "" || value.Kind() != reflect.Pointer || value.IsNil() {
+			continue
+		}
+		name := bindFieldName(field)
+		if name == "-" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func (c *Context) bindBody(dst any) error {
+	r := c.Request
+	if err := validateBodyFraming(r, c.bodyLimit()); err != nil {
+		return err
+	}
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		return c.BindForm(dst)
+	case "multipart/form-data":
+		return c.BindMultipart(dst, DefaultMultipartMemory)
+	default:
+		return c.BindJSON(dst)
+	}
+}
+
 // FormFile returns a file header from a multipart request.
 func (c *Context) FormFile(name string, maxMemory int64) (*multipart.FileHeader, error) {
 	if maxMemory <= 0 {
 		maxMemory = DefaultMultipartMemory
 	}
-	if err := c.Request.ParseMultipartForm(maxMemory); err != nil {
-		return nil, apperr.BadRequest("invalid multipart form", err)
+	if err := c.parseMultipart(maxMemory); err != nil {
+		return nil, err
 	}
 	file, header, err := c.Request.FormFile(name)
 	if err != nil {
@@ -269,7 +512,72 @@ func (c *Context) RequestID() string {
 	return RequestIDFromHeader(c.Request)
 }
 
+var routeInfoKey = NewContextKey[RouteInfo]("bebo.route")
+
+// Route returns the matched route for the request.
+// It reports false for unmatched requests and in pre-routing middleware.
+func (c *Context) Route() (RouteInfo, bool) {
+	return routeInfoKey.Get(c)
+}
+
+// RoutePattern returns the matched route pattern, such as "/users/:id".
+func (c *Context) RoutePattern() string {
+	route, _ := c.Route()
+	return route.Pattern
+}
+
+// RouteName returns the matched route name, if the route is named.
+func (c *Context) RouteName() string {
+	route, _ := c.Route()
+	return route.Name
+}
+
+var localeKey = NewContextKey[string]("bebo.locale")
+
+// SetLocale stores the negotiated locale for the request.
+// HTML and HTMLWithLayout prefer a "<name>.<locale>.html" template when present.
+func (c *Context) SetLocale(locale string) {
+	localeKey.Set(c, locale)
+}
+
+// Locale returns the locale set by SetLocale (or middleware.Locale).
+func (c *Context) Locale() string {
+	return localeKey.GetOr(c, "")
+}
+
+// NoDeadline is returned by TimeRemaining when the request has no deadline.
+const NoDeadline time.Duration = -1
+
+// Deadline returns the request context deadline, if any.
+func (c *Context) Deadline() (time.Time, bool) {
+	if c.Request == nil {
+		return time.Time{}, false
+	}
+	return c.Request.Context().Deadline()
+}
+
+// TimeRemaining returns the time left before the request deadline.
+// It returns 0 once the deadline has passed and NoDeadline when none is set.
+func (c *Context) TimeRemaining() time.Duration {
+	deadline, ok := c.Deadline()
+	if !ok {
+		return NoDeadline
+	}
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// fieldNamer resolves the binding name for a struct field and whether it is required.
+type fieldNamer func(reflect.StructField) (string, bool)
+
 func bindValues(values url.Values, dst any) error {
+	return bindValuesWith(values, dst, formFieldName)
+}
+
+func bindValuesWith(values url.Values, dst any, namer fieldNamer) error {
 	if dst == nil {
 		return apperr.BadRequest("destination is required", nil)
 	}
@@ -283,7 +591,7 @@ func bindValues(values url.Values, dst any) error {
 
 	switch rv.Kind() {
 	case reflect.Struct:
-		return bindStruct(values, rv)
+		return bindStruct(values, rv, namer)
 	case reflect.Map:
 		return bindMap(values, rv)
 	default:
@@ -322,19 +630,28 @@ func bindMap(values url.Values, rv reflect.Value) error {
 	return nil
 }
 
-func bindStruct(values url.Values, rv reflect.Value) error {
+func bindStruct(values url.Values, rv reflect.Value, namer fieldNamer) error {
 	rt := rv.Type()
 	for i := 0; i < rv.NumField(); i++ {
 		field := rt.Field(i)
+		if isEmbeddedStruct(field) {
+			if err := bindEmbedded(values, rv.Field(i), namer); err != nil {
+				return err
+			}
+			continue
+		}
 		if field.PkgPath != "" {
 			continue
 		}
-		name := bindFieldName(field)
+		name, required := namer(field)
 		if name == "" || name == "-" {
 			continue
 		}
 		vals, ok := values[name]
 		if !ok || len(vals) == 0 {
+			if required {
+				return apperr.BadRequest(name+" is required", nil)
+			}
 			continue
 		}
 		if err := setFieldValue(rv.Field(i), name, vals); err != nil {
@@ -344,6 +661,47 @@ func bindStruct(values url.Values, rv reflect.Value) error {
 	return nil
 }
 
+// isEmbeddedStruct reports whether an anonymous struct field should be
+// flattened into its parent, as encoding/json does for untagged embeds.
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.Struct {
+		return false
+	}
+	for _, key := range []string{"form", "json", "query", "param"} {
+		if _, ok := tagName(field.Tag.Get(key)); ok {
+			return false
+		}
+	}
+	return true
+}
+
+func bindEmbedded(values url.Values, field reflect.Value, namer fieldNamer) error {
+	if field.Kind() != reflect.Pointer {
+		return bindStruct(values, field, namer)
+	}
+	if !field.IsNil() {
+		return bindStruct(values, field.Elem(), namer)
+	}
+	if !field.CanSet() {
+		return nil
+	}
+	value := reflect.New(field.Type().Elem())
+	if err := bindStruct(values, value.Elem(), namer); err != nil {
+		return err
+	}
+	if !value.Elem().IsZero() {
+		field.Set(value)
+	}
+	return nil
+}
+
 func bindFieldName(field reflect.StructField) string {
 	if tag, ok := tagName(field.Tag.Get("form")); ok {
 		return tag
@@ -354,6 +712,99 @@ func bindFieldName(field reflect.StructField) string {
 	return field.Name
 }
 
+func formFieldName(field reflect.StructField) (string, bool) {
+	return bindFieldName(field), false
+}
+
+func paramFieldName(field reflect.StructField) (string, bool) {
+	if tag, ok := tagName(field.Tag.Get("param")); ok {
+		return tag, true
+	}
+	return field.Name, false
+}
+
+func queryFieldName(field reflect.StructField) (string, bool) {
+	if tag, ok := tagName(field.Tag.Get("query")); ok {
+		return tag, false
+	}
+	return bindFieldName(field), false
+}
+
+// taggedOnly restricts a namer to fields that carry the given struct tag.
+func taggedOnly(tag string, namer fieldNamer) fieldNamer {
+	return func(field reflect.StructField) (string, bool) {
+		if _, ok := field.Tag.Lookup(tag); !ok {
+			return "", false
+		}
+		return namer(field)
+	}
+}
+
+func paramValues(params router.Params) url.Values {
+	values := make(url.Values, len(params))
+	for key, value := range params {
+		values.Set(key, value)
+	}
+	return values
+}
+
+var bodyLimitKey = NewContextKey[int64]("bebo.body_limit")
+
+// SetBodyLimit records the request body limit enforced by middleware such as
+// middleware.BodyLimit, so binders reject a larger declared Content-Length with
+// 413 before reading the body.
+func SetBodyLimit(ctx *Context, maxBytes int64) {
+	bodyLimitKey.Set(ctx, maxBytes)
+}
+
+func (c *Context) bodyLimit() int64 {
+	return bodyLimitKey.GetOr(c, 0)
+}
+
+// validateBodyFraming rejects requests whose declared body length is ambiguous,
+// such as a Content-Length alongside chunked encoding or conflicting lengths, or
+// larger than a positive limit.
+func validateBodyFraming(r *http.Request, limit int64) error {
+	if r.ContentLength < -1 {
+		return apperr.BadRequest("invalid content length", nil)
+	}
+
+	lengths := r.Header.Values("Content-Length")
+	chunked := false
+	for _, encoding := range r.TransferEncoding {
+		if strings.EqualFold(encoding, "chunked") {
+			chunked = true
+		}
+	}
+	for _, value := range r.Header.Values("Transfer-Encoding") {
+		if strings.Contains(strings.ToLower(value), "chunked") {
+			chunked = true
+		}
+	}
+	if chunked && len(lengths) > 0 {
+		return apperr.BadRequest("conflicting content length and transfer encoding", nil)
+	}
+
+	declared := int64(-1)
+	for _, value := range lengths {
+		length, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || length < 0 {
+			return apperr.BadRequest("invalid content length", err)
+		}
+		if declared >= 0 && length != declared {
+			return apperr.BadRequest("conflicting content length", nil)
+		}
+		declared = length
+	}
+	if declared >= 0 && r.ContentLength >= 0 && declared != r.ContentLength {
+		return apperr.BadRequest("conflicting content length", nil)
+	}
+	if limit > 0 && max(declared, r.ContentLength) > limit {
+		return apperr.PayloadTooLarge("request body too large", nil)
+	}
+	return nil
+}
+
 func tagName(tag string) (string, bool) {
 	if tag == "" {
 		return "", false
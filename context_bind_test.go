@@ -2,6 +2,7 @@ package bebo
 
 import (
 	"bytes"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/devmarvs/bebo/apperr"
 )
 
 type formPayload struct {
@@ -86,3 +89,315 @@ func TestBindMultipartAndSaveFile(t *testing.T) {
 		t.Fatalf("unexpected file contents: %s", string(contents))
 	}
 }
+
+type paramsPayload struct {
+	ID   int64  `param:"id"`
+	Slug string `param:"slug"`
+}
+
+func TestBindParams(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/posts/42/hello", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, Params{"id": "42", "slug": "hello"}, New())
+
+	var payload paramsPayload
+	if err := ctx.BindParams(&payload); err != nil {
+		t.Fatalf("bind params: %v", err)
+	}
+	if payload.ID != 42 || payload.Slug != "hello" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestBindParamsMissingRequired(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/posts/42", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, Params{"id": "42"}, New())
+
+	var payload paramsPayload
+	err := ctx.BindParams(&payload)
+	appErr := apperr.As(err)
+	if appErr == nil || appErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+}
+
+func TestBindParamsInvalidConversion(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/posts/abc/hello", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, Params{"id": "abc", "slug": "hello"}, New())
+
+	var payload paramsPayload
+	err := ctx.BindParams(&payload)
+	appErr := apperr.As(err)
+	if appErr == nil || appErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+	if appErr.Message != "id must be an integer" {
+		t.Fatalf("unexpected message: %s", appErr.Message)
+	}
+}
+
+type listPayload struct {
+	Page  int    `query:"page"`
+	Limit int    `query:"limit"`
+	Sort  string `query:"sort"`
+}
+
+func TestBindQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items?page=2&limit=50&sort=name", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	var payload listPayload
+	if err := ctx.BindQuery(&payload); err != nil {
+		t.Fatalf("bind query: %v", err)
+	}
+	if payload.Page != 2 || payload.Limit != 50 || payload.Sort != "name" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+type updatePayload struct {
+	ID     int64  `param:"id"`
+	Notify bool   `query:"notify"`
+	Sort   string `query:"sort" json:"sort"`
+	Title  string `json:"title"`
+}
+
+func TestBindAllSources(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/items/7?notify=true", strings.NewReader(`{"title":"hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := NewContext(httptest.NewRecorder(), req, Params{"id": "7"}, New())
+
+	var payload updatePayload
+	if err := ctx.BindAll(&payload); err != nil {
+		t.Fatalf("bind all: %v", err)
+	}
+	if payload.ID != 7 || !payload.Notify || payload.Title != "hello" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestBindAllBodyOverridesQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/items/7?sort=asc", strings.NewReader(`{"sort":"desc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := NewContext(httptest.NewRecorder(), req, Params{"id": "7"}, New())
+
+	var payload updatePayload
+	if err := ctx.BindAll(&payload); err != nil {
+		t.Fatalf("bind all: %v", err)
+	}
+	if payload.Sort != "desc" {
+		t.Fatalf("expected body to override query, got %q", payload.Sort)
+	}
+}
+
+func TestBindAllBodyCannotOverrideParams(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/items/7?notify=true", strings.NewReader(`{"id":99,"ID":98,"notify":false,"title":"hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := NewContext(httptest.NewRecorder(), req, Params{"id": "7"}, New())
+
+	var payload updatePayload
+	if err := ctx.BindAll(&payload); err != nil {
+		t.Fatalf("bind all: %v", err)
+	}
+	if payload.ID != 7 || !payload.Notify || payload.Title != "hello" {
+		t.Fatalf("expected body to leave param and query fields alone, got %+v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/items/7", strings.NewReader("id=99&ID=98&title=hello"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx = NewContext(httptest.NewRecorder(), req, Params{"id": "7"}, New())
+
+	payload = updatePayload{}
+	if err := ctx.BindAll(&payload); err != nil {
+		t.Fatalf("bind all form: %v", err)
+	}
+	if payload.ID != 7 || payload.Title != "hello" {
+		t.Fatalf("expected form body to leave param field alone, got %+v", payload)
+	}
+}
+
+func TestBindAllWithoutBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items/7?sort=asc", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, Params{"id": "7"}, New())
+
+	var payload updatePayload
+	if err := ctx.BindAll(&payload); err != nil {
+		t.Fatalf("bind all: %v", err)
+	}
+	if payload.ID != 7 || payload.Sort != "asc" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestBindRejectsAmbiguousFraming(t *testing.T) {
+	cases := map[string]func(*http.Request){
+		"length with chunked": func(req *http.Request) {
+			req.TransferEncoding = []string{"chunked"}
+			req.Header.Set("Content-Length", "13")
+		},
+		"conflicting lengths": func(req *http.Request) {
+			req.Header["Content-Length"] = []string{"13", "20"}
+		},
+		"negative header length": func(req *http.Request) {
+			req.Header.Set("Content-Length", "-5")
+		},
+		"negative declared length": func(req *http.Request) {
+			req.ContentLength = -10
+		},
+	}
+
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=ada&age=3"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			mutate(req)
+			ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+			var payload formPayload
+			err := ctx.BindForm(&payload)
+			appErr := apperr.As(err)
+			if appErr == nil || appErr.Status != http.StatusBadRequest {
+				t.Fatalf("expected bad request, got %v", err)
+			}
+			if err := ctx.BindJSON(&payload); apperr.As(err) == nil {
+				t.Fatalf("expected JSON binding to reject framing, got %v", err)
+			}
+		})
+	}
+}
+
+type pagination struct {
+	Page    int `query:"page"`
+	PerPage int `query:"per_page"`
+}
+
+type Sorting struct {
+	Sort string `query:"sort"`
+}
+
+type listRequest struct {
+	pagination
+	*Sorting
+	Search string `query:"q"`
+}
+
+func TestBindQueryEmbeddedStructs(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?page=2&per_page=50&sort=name&q=kim", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	var payload listRequest
+	if err := ctx.BindQuery(&payload); err != nil {
+		t.Fatalf("bind query: %v", err)
+	}
+	if payload.Page != 2 || payload.PerPage != 50 || payload.Search != "kim" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if payload.Sorting == nil || payload.Sorting.Sort != "name" {
+		t.Fatalf("expected embedded pointer to bind, got %+v", payload.Sorting)
+	}
+}
+
+func TestBindFormEmbeddedPointerLeftNilWhenUnset(t *testing.T) {
+	body := strings.NewReader("q=kim")
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	var payload struct {
+		*Sorting
+		Search string `form:"q"`
+	}
+	if err := ctx.BindForm(&payload); err != nil {
+		t.Fatalf("bind form: %v", err)
+	}
+	if payload.Search != "kim" || payload.Sorting != nil {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+type patchUser struct {
+	Name   *string `json:"name" form:"name"`
+	Age    *int    `json:"age" form:"age"`
+	Active *bool   `json:"active" form:"active"`
+}
+
+func TestBindPatchJSONDistinguishesZeroFromAbsent(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"age":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	var payload patchUser
+	if err := ctx.BindPatch(&payload); err != nil {
+		t.Fatalf("bind patch: %v", err)
+	}
+	if payload.Age == nil || *payload.Age != 0 {
+		t.Fatalf("expected age set to zero, got %v", payload.Age)
+	}
+	if payload.Name != nil || payload.Active != nil {
+		t.Fatalf("expected omitted fields nil, got %+v", payload)
+	}
+	if got := PresentFields(&payload); len(got) != 1 || got[0] != "age" {
+		t.Fatalf("expected present fields [age], got %v", got)
+	}
+}
+
+func TestBindPatchForm(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader("active=false&name="))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	var payload patchUser
+	if err := ctx.BindPatch(&payload); err != nil {
+		t.Fatalf("bind patch: %v", err)
+	}
+	if payload.Active == nil || *payload.Active {
+		t.Fatalf("expected active set to false, got %v", payload.Active)
+	}
+	if payload.Name == nil || *payload.Name != "" {
+		t.Fatalf("expected name set to empty string, got %v", payload.Name)
+	}
+	if payload.Age != nil {
+		t.Fatalf("expected age nil")
+	}
+	got := PresentFields(payload)
+	if len(got) != 2 || got[0] != "name" || got[1] != "active" {
+		t.Fatalf("unexpected present fields %v", got)
+	}
+}
+
+func TestBindPatchRequiresStructPointer(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{}`))
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	values := map[string]any{}
+	if err := ctx.BindPatch(&values); err == nil {
+		t.Fatalf("expected error for map destination")
+	}
+}
+
+type unreadBody struct {
+	t *testing.T
+}
+
+func (b unreadBody) Read([]byte) (int, error) {
+	b.t.Fatalf("expected body not to be read")
+	return 0, io.EOF
+}
+
+func TestBindRejectsDeclaredLengthOverLimit(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", unreadBody{t: t})
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Length", "4096")
+	req.ContentLength = 4096
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+	SetBodyLimit(ctx, 1024)
+
+	var payload formPayload
+	err := ctx.BindJSON(&payload)
+	appErr := apperr.As(err)
+	if appErr == nil || appErr.Status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %v", err)
+	}
+	if err := ctx.BindAll(&payload); apperr.As(err) == nil || apperr.As(err).Status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected BindAll to reject declared length, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package bebo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContextDeadline(t *testing.T) {
+	reqCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(reqCtx)
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatalf("expected deadline")
+	}
+	want, _ := reqCtx.Deadline()
+	if !deadline.Equal(want) {
+		t.Fatalf("expected deadline %v, got %v", want, deadline)
+	}
+
+	remaining := ctx.TimeRemaining()
+	if remaining <= 0 || remaining > time.Minute {
+		t.Fatalf("unexpected remaining time %v", remaining)
+	}
+}
+
+func TestContextDeadlineExpired(t *testing.T) {
+	reqCtx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(reqCtx)
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	if remaining := ctx.TimeRemaining(); remaining != 0 {
+		t.Fatalf("expected 0 remaining, got %v", remaining)
+	}
+}
+
+func TestContextNoDeadline(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatalf("expected no deadline")
+	}
+	if remaining := ctx.TimeRemaining(); remaining != NoDeadline {
+		t.Fatalf("expected NoDeadline, got %v", remaining)
+	}
+}
+
+func TestContextDeadlineFromRouteTimeout(t *testing.T) {
+	app := New()
+	var remaining time.Duration
+	app.Route(http.MethodGet, "/slow", func(ctx *Context) error {
+		remaining = ctx.TimeRemaining()
+		return ctx.Text(http.StatusOK, "ok")
+	}, WithTimeout(time.Minute))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	if remaining <= 0 || remaining > time.Minute {
+		t.Fatalf("unexpected remaining time %v", remaining)
+	}
+}
+
+func TestRouteTimeoutWritesError(t *testing.T) {
+	app := New()
+	app.Route(http.MethodGet, "/slow", func(ctx *Context) error {
+		<-ctx.Request.Context().Done()
+		return ctx.Text(http.StatusOK, "late")
+	}, WithTimeout(20*time.Millisecond))
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d", rec.Code)
+	}
+}
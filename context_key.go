@@ -0,0 +1,44 @@
+package bebo
+
+// ContextKey is a typed key for values stored on a Context. Keys are compared
+// by identity, so two packages that pick the same name never collide, and
+// they never collide with plain string keys used with Set/Get.
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey creates a key. Declare keys as unexported package variables:
+//
+//	var userKey = bebo.NewContextKey[*User]("currentUser")
+func NewContextKey[T any](name string) *ContextKey[T] {
+	return &ContextKey[T]{name: name}
+}
+
+// String returns the key name for debugging.
+func (k *ContextKey[T]) String() string {
+	return k.name
+}
+
+// Set stores value on ctx under the key.
+func (k *ContextKey[T]) Set(ctx *Context, value T) {
+	ctx.values[k] = value
+}
+
+// Get returns the value stored under the key.
+func (k *ContextKey[T]) Get(ctx *Context) (T, bool) {
+	value, ok := ctx.values[k].(T)
+	return value, ok
+}
+
+// GetOr returns the value stored under the key, or fallback when unset.
+func (k *ContextKey[T]) GetOr(ctx *Context, fallback T) T {
+	if value, ok := k.Get(ctx); ok {
+		return value
+	}
+	return fallback
+}
+
+// Delete removes the value stored under the key.
+func (k *ContextKey[T]) Delete(ctx *Context) {
+	delete(ctx.values, k)
+}
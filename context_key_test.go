@@ -0,0 +1,86 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Two packages that both pick "user" as a key name.
+var (
+	billingUserKey = NewContextKey[string]("user")
+	authUserKey    = NewContextKey[int]("user")
+)
+
+func TestContextKeysDoNotCollide(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, New())
+
+	billingUserKey.Set(ctx, "acct_42")
+	authUserKey.Set(ctx, 7)
+	ctx.Set("user", "plain")
+
+	if value, ok := billingUserKey.Get(ctx); !ok || value != "acct_42" {
+		t.Fatalf("expected billing value, got %q %v", value, ok)
+	}
+	if value, ok := authUserKey.Get(ctx); !ok || value != 7 {
+		t.Fatalf("expected auth value, got %d %v", value, ok)
+	}
+	if value, _ := ctx.Get("user"); value != "plain" {
+		t.Fatalf("expected string key untouched, got %v", value)
+	}
+	if billingUserKey.String() != "user" {
+		t.Fatalf("unexpected key name: %s", billingUserKey)
+	}
+
+	authUserKey.Delete(ctx)
+	if _, ok := authUserKey.Get(ctx); ok {
+		t.Fatal("expected deleted key to be unset")
+	}
+	if _, ok := billingUserKey.Get(ctx); !ok {
+		t.Fatal("expected other key to survive delete")
+	}
+}
+
+func TestContextGetOr(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, New())
+
+	if got := authUserKey.GetOr(ctx, -1); got != -1 {
+		t.Fatalf("expected fallback, got %d", got)
+	}
+	authUserKey.Set(ctx, 0)
+	if got := authUserKey.GetOr(ctx, -1); got != 0 {
+		t.Fatalf("expected stored zero value, got %d", got)
+	}
+
+	if got := ctx.GetOr("theme", "light"); got != "light" {
+		t.Fatalf("expected fallback, got %v", got)
+	}
+	ctx.Set("theme", "dark")
+	if got := ctx.GetOr("theme", "light"); got != "dark" {
+		t.Fatalf("expected stored value, got %v", got)
+	}
+}
+
+func TestBuiltinKeysIgnoreStringKeys(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, New())
+	ctx.Set("bebo.principal", &Principal{ID: "spoofed"})
+	ctx.Set("bebo.locale", "fr")
+
+	if _, ok := PrincipalFromContext(ctx); ok {
+		t.Fatal("expected string key not to satisfy the principal key")
+	}
+	if ctx.Locale() != "" {
+		t.Fatalf("expected empty locale, got %q", ctx.Locale())
+	}
+}
+
+func TestContextPrincipal(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, New())
+	if ctx.Principal() != nil {
+		t.Fatal("expected nil principal for unauthenticated request")
+	}
+	SetPrincipal(ctx, &Principal{ID: "user-1"})
+	if principal := ctx.Principal(); principal == nil || principal.ID != "user-1" {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+}
@@ -0,0 +1,50 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devmarvs/bebo/render"
+)
+
+func TestHTMLSelectsLocaleTemplate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"home.html":    "hello",
+		"home.fr.html": "bonjour",
+		"about.html":   "about",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	engine, err := render.NewEngineWithOptions(dir, render.Options{})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	app := New(WithRenderer(engine))
+	app.GET("/:page", func(ctx *Context) error {
+		ctx.SetLocale(ctx.Query("lang"))
+		return ctx.HTML(http.StatusOK, ctx.Param("page")+".html", nil)
+	})
+
+	cases := map[string]string{
+		"/home?lang=fr":    "bonjour",
+		"/home?lang=fr-CA": "bonjour",
+		"/home?lang=de":    "hello",
+		"/home":            "hello",
+		"/about?lang=fr":   "about",
+	}
+	for target, want := range cases {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Body.String() != want {
+			t.Fatalf("%s: expected %q, got %q", target, want, rec.Body.String())
+		}
+	}
+}
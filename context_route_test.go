@@ -0,0 +1,51 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextRoutePattern(t *testing.T) {
+	app := New()
+	var seenByMiddleware string
+	app.Use(func(next Handler) Handler {
+		return func(ctx *Context) error {
+			seenByMiddleware = ctx.RoutePattern()
+			return next(ctx)
+		}
+	})
+
+	var pattern, name string
+	app.Route(http.MethodGet, "/users/:id", func(ctx *Context) error {
+		pattern = ctx.RoutePattern()
+		name = ctx.RouteName()
+		return ctx.Text(http.StatusOK, "ok")
+	}, WithName("users.show"))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if seenByMiddleware != "/users/:id" {
+		t.Fatalf("expected pattern in middleware, got %q", seenByMiddleware)
+	}
+	if pattern != "/users/:id" || name != "users.show" {
+		t.Fatalf("unexpected route %q %q", pattern, name)
+	}
+}
+
+func TestContextRouteUnmatched(t *testing.T) {
+	app := New()
+	matched := true
+	app.Use(func(next Handler) Handler {
+		return func(ctx *Context) error {
+			_, matched = ctx.Route()
+			return next(ctx)
+		}
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if matched {
+		t.Fatalf("expected no route for unmatched request")
+	}
+}
@@ -0,0 +1,34 @@
+package bebo
+
+import (
+	"encoding/csv"
+	"mime"
+)
+
+// CSV streams a CSV response. A non-empty filename is sent as an attachment
+// Content-Disposition. The header row is written first, then rows calls
+// write once per record; quoting and escaping follow encoding/csv. Records
+// stream to the client as the writer's buffer fills.
+func (c *Context) CSV(status int, filename string, header []string, rows func(write func([]string) error) error) error {
+	w := c.ResponseWriter
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	if filename != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	w.WriteHeader(status)
+
+	writer := csv.NewWriter(w)
+	if len(header) > 0 {
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+	if rows != nil {
+		if err := rows(writer.Write); err != nil {
+			writer.Flush()
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
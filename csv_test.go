@@ -0,0 +1,57 @@
+package bebo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextCSV(t *testing.T) {
+	app := New()
+	app.GET("/export", func(ctx *Context) error {
+		return ctx.CSV(http.StatusOK, "report 2024.csv", []string{"id", "note"}, func(write func([]string) error) error {
+			if err := write([]string{"1", `plain`}); err != nil {
+				return err
+			}
+			return write([]string{"2", `has, comma and "quotes"`})
+		})
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="report 2024.csv"` {
+		t.Fatalf("unexpected content disposition %q", cd)
+	}
+	want := "id,note\n1,plain\n2,\"has, comma and \"\"quotes\"\"\"\n"
+	if rec.Body.String() != want {
+		t.Fatalf("expected body %q, got %q", want, rec.Body.String())
+	}
+}
+
+func TestContextCSVWithoutFilename(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := NewContext(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil, nil)
+
+	failure := errors.New("query failed")
+	err := ctx.CSV(http.StatusOK, "", []string{"id"}, func(write func([]string) error) error {
+		_ = write([]string{"1"})
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected row error, got %v", err)
+	}
+	if rec.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("expected no attachment without a filename")
+	}
+	if rec.Body.String() != "id\n1\n" {
+		t.Fatalf("expected rows written before the error, got %q", rec.Body.String())
+	}
+}
@@ -3,9 +3,19 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 )
 
+var (
+	// ErrRowsAffected indicates the driver could not report rows affected.
+	ErrRowsAffected = errors.New("rows affected not supported by driver")
+	// ErrLastInsertID indicates the driver could not report a last insert id,
+	// as with PostgreSQL drivers; use INSERT ... RETURNING id there instead.
+	ErrLastInsertID = errors.New("last insert id not supported by driver")
+)
+
 // Execer runs exec statements with context.
 type Execer interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
@@ -21,24 +31,76 @@ type QueryRower interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
-// Helper wraps query helpers with a default timeout.
+// QueryObserver receives a query, its arguments, how long it took and its
+// error once it returns.
+type QueryObserver func(query string, args []any, duration time.Duration, err error)
+
+// Helper wraps query helpers with a default timeout and optional query
+// observers.
 type Helper struct {
 	Timeout time.Duration
+	// OnQuery, when set, is called after every query the helper runs.
+	OnQuery QueryObserver
+	// SlowThreshold escalates queries taking at least this long to
+	// OnSlowQuery. Zero disables slow query reporting.
+	SlowThreshold time.Duration
+	// OnSlowQuery is called, after OnQuery, for queries reaching
+	// SlowThreshold.
+	OnSlowQuery QueryObserver
 }
 
 // Exec runs an exec statement with timeout.
 func (h Helper) Exec(ctx context.Context, db Execer, query string, args ...any) (sql.Result, error) {
-	return Exec(ctx, h.Timeout, db, query, args...)
+	start := time.Now()
+	result, err := Exec(ctx, h.Timeout, db, query, args...)
+	h.observe(query, args, time.Since(start), err)
+	return result, err
+}
+
+// ExecAffected runs an exec statement with timeout and returns rows affected.
+func (h Helper) ExecAffected(ctx context.Context, db Execer, query string, args ...any) (int64, error) {
+	start := time.Now()
+	affected, err := ExecAffected(ctx, h.Timeout, db, query, args...)
+	h.observe(query, args, time.Since(start), err)
+	return affected, err
+}
+
+// ExecInsertID runs an exec statement with timeout and returns the last insert id.
+func (h Helper) ExecInsertID(ctx context.Context, db Execer, query string, args ...any) (int64, error) {
+	start := time.Now()
+	id, err := ExecInsertID(ctx, h.Timeout, db, query, args...)
+	h.observe(query, args, time.Since(start), err)
+	return id, err
 }
 
 // Query runs a query with timeout.
 func (h Helper) Query(ctx context.Context, db Queryer, query string, args ...any) (*sql.Rows, error) {
-	return Query(ctx, h.Timeout, db, query, args...)
+	start := time.Now()
+	rows, err := Query(ctx, h.Timeout, db, query, args...)
+	h.observe(query, args, time.Since(start), err)
+	return rows, err
 }
 
-// QueryRow runs a row query with timeout.
+// QueryRow runs a row query with timeout. Observers receive the query error
+// from row.Err; sql.ErrNoRows only surfaces later, on Scan.
 func (h Helper) QueryRow(ctx context.Context, db QueryRower, query string, args ...any) (*sql.Row, context.CancelFunc) {
-	return QueryRow(ctx, h.Timeout, db, query, args...)
+	start := time.Now()
+	row, cancel := QueryRow(ctx, h.Timeout, db, query, args...)
+	var err error
+	if row != nil {
+		err = row.Err()
+	}
+	h.observe(query, args, time.Since(start), err)
+	return row, cancel
+}
+
+func (h Helper) observe(query string, args []any, duration time.Duration, err error) {
+	if h.OnQuery != nil {
+		h.OnQuery(query, args, duration, err)
+	}
+	if h.OnSlowQuery != nil && h.SlowThreshold > 0 && duration >= h.SlowThreshold {
+		h.OnSlowQuery(query, args, duration, err)
+	}
 }
 
 // Exec runs an exec statement with timeout.
@@ -48,6 +110,35 @@ func Exec(ctx context.Context, timeout time.Duration, db Execer, query string, a
 	return db.ExecContext(ctx, query, args...)
 }
 
+// ExecAffected runs an exec statement with timeout and returns rows affected.
+// Driver failures to report the count wrap ErrRowsAffected.
+func ExecAffected(ctx context.Context, timeout time.Duration, db Execer, query string, args ...any) (int64, error) {
+	result, err := Exec(ctx, timeout, db, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrRowsAffected, err)
+	}
+	return affected, nil
+}
+
+// ExecInsertID runs an exec statement with timeout and returns the last
+// insert id. Drivers without insert ids produce an error wrapping
+// ErrLastInsertID.
+func ExecInsertID(ctx context.Context, timeout time.Duration, db Execer, query string, args ...any) (int64, error) {
+	result, err := Exec(ctx, timeout, db, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrLastInsertID, err)
+	}
+	return id, nil
+}
+
 // Query runs a query with timeout.
 func Query(ctx context.Context, timeout time.Duration, db Queryer, query string, args ...any) (*sql.Rows, error) {
 	ctx, cancel := WithTimeout(ctx, timeout)
@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"testing"
 	"time"
 )
@@ -43,3 +45,151 @@ func TestExecWithTimeout(t *testing.T) {
 		t.Fatalf("expected deadline")
 	}
 }
+
+type resultExecer struct {
+	result sql.Result
+	err    error
+}
+
+func (r resultExecer) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return r.result, r.err
+}
+
+type insertResult struct {
+	id       int64
+	affected int64
+}
+
+func (r insertResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r insertResult) RowsAffected() (int64, error) { return r.affected, nil }
+
+func TestHelperExecAffected(t *testing.T) {
+	helper := Helper{Timeout: time.Second}
+	affected, err := helper.ExecAffected(context.Background(), resultExecer{result: driver.RowsAffected(3)}, "UPDATE users SET active = true")
+	if err != nil {
+		t.Fatalf("exec affected: %v", err)
+	}
+	if affected != 3 {
+		t.Fatalf("expected 3 rows affected, got %d", affected)
+	}
+
+	execErr := errors.New("connection reset")
+	if _, err := helper.ExecAffected(context.Background(), resultExecer{err: execErr}, "UPDATE users"); !errors.Is(err, execErr) {
+		t.Fatalf("expected exec error, got %v", err)
+	}
+}
+
+func TestHelperExecInsertID(t *testing.T) {
+	helper := Helper{}
+	id, err := helper.ExecInsertID(context.Background(), resultExecer{result: insertResult{id: 42, affected: 1}}, "INSERT INTO users (name) VALUES (?)", "ada")
+	if err != nil {
+		t.Fatalf("exec insert id: %v", err)
+	}
+	if id != 42 {
+		t.Fatalf("expected id 42, got %d", id)
+	}
+
+	// driver.RowsAffected reports no insert id, like PostgreSQL drivers.
+	_, err = helper.ExecInsertID(context.Background(), resultExecer{result: driver.RowsAffected(1)}, "INSERT INTO users (name) VALUES ($1)", "ada")
+	if !errors.Is(err, ErrLastInsertID) {
+		t.Fatalf("expected ErrLastInsertID, got %v", err)
+	}
+}
+
+type slowExecer struct {
+	delay time.Duration
+}
+
+func (s slowExecer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	time.Sleep(s.delay)
+	return stubResult{}, nil
+}
+
+func TestHelperOnQuery(t *testing.T) {
+	var gotQuery string
+	var gotArgs []any
+	var gotErr error
+	calls := 0
+	helper := Helper{OnQuery: func(query string, args []any, duration time.Duration, err error) {
+		calls++
+		gotQuery, gotArgs, gotErr = query, args, err
+	}}
+
+	if _, err := helper.Exec(context.Background(), &stubExecer{}, "DELETE FROM users WHERE id = ?", 7); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if calls != 1 || gotQuery != "DELETE FROM users WHERE id = ?" {
+		t.Fatalf("unexpected observed query %q after %d calls", gotQuery, calls)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != 7 {
+		t.Fatalf("unexpected observed args %v", gotArgs)
+	}
+
+	queryErr := errors.New("boom")
+	_, _ = helper.Query(context.Background(), &stubQueryDB{queryErr: queryErr}, "SELECT * FROM users")
+	if calls != 2 || gotQuery != "SELECT * FROM users" || !errors.Is(gotErr, queryErr) {
+		t.Fatalf("expected query error to be observed, got %q %v", gotQuery, gotErr)
+	}
+
+	_, cancel := helper.QueryRow(context.Background(), &stubQueryDB{}, "SELECT 1")
+	cancel()
+	if calls != 3 || gotQuery != "SELECT 1" || gotErr != nil {
+		t.Fatalf("expected row query to be observed, got %q %v", gotQuery, gotErr)
+	}
+}
+
+func TestHelperSlowQuery(t *testing.T) {
+	var queries, slow []string
+	helper := Helper{
+		OnQuery: func(query string, args []any, duration time.Duration, err error) {
+			queries = append(queries, query)
+		},
+		SlowThreshold: 20 * time.Millisecond,
+		OnSlowQuery: func(query string, args []any, duration time.Duration, err error) {
+			if duration < 20*time.Millisecond {
+				t.Fatalf("slow query reported with duration %s", duration)
+			}
+			slow = append(slow, query)
+		},
+	}
+
+	if _, err := helper.Exec(context.Background(), slowExecer{}, "SELECT fast"); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if _, err := helper.Exec(context.Background(), slowExecer{delay: 30 * time.Millisecond}, "SELECT slow"); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected both queries observed, got %v", queries)
+	}
+	if len(slow) != 1 || slow[0] != "SELECT slow" {
+		t.Fatalf("expected only the slow query escalated, got %v", slow)
+	}
+}
+
+type failingConnector struct {
+	err error
+}
+
+func (c failingConnector) Connect(context.Context) (driver.Conn, error) { return nil, c.err }
+func (c failingConnector) Driver() driver.Driver                        { return nil }
+
+func TestHelperQueryRowReportsError(t *testing.T) {
+	connErr := errors.New("connection refused")
+	dbConn := sql.OpenDB(failingConnector{err: connErr})
+	defer dbConn.Close()
+
+	var gotErr error
+	helper := Helper{OnQuery: func(query string, args []any, duration time.Duration, err error) {
+		gotErr = err
+	}}
+	row, cancel := helper.QueryRow(context.Background(), dbConn, "SELECT 1")
+	defer cancel()
+
+	if !errors.Is(gotErr, connErr) {
+		t.Fatalf("expected observed row error, got %v", gotErr)
+	}
+	if err := row.Scan(new(int)); !errors.Is(err, connErr) {
+		t.Fatalf("expected scan to return row error, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -15,13 +16,14 @@ import (
 	"github.com/devmarvs/bebo/config"
 	"github.com/devmarvs/bebo/flash"
 	"github.com/devmarvs/bebo/health"
+	"github.com/devmarvs/bebo/mail"
 	"github.com/devmarvs/bebo/middleware"
 	"github.com/devmarvs/bebo/session"
 	"github.com/devmarvs/bebo/validate"
 	"github.com/devmarvs/bebo/web"
 )
 
-const userKey = "currentUser"
+var userKey = bebo.NewContextKey[*User]("currentUser")
 
 type AppConfig struct {
 	App           config.Config
@@ -29,12 +31,16 @@ type AppConfig struct {
 	SessionKey    []byte
 	SecureCookies bool
 	AutoMigrate   bool
+	Mailer        mail.Mailer
+	MailFrom      string
 }
 
 type Server struct {
 	store    *Store
 	sessions session.Store
 	flash    flash.Store
+	mailer   mail.Mailer
+	mailFrom string
 }
 
 type User struct {
@@ -114,6 +120,8 @@ func NewApp(dbConn *sql.DB, cfg AppConfig) *bebo.App {
 		store:    NewStore(dbConn),
 		sessions: cookieStore,
 		flash:    flash.New(cookieStore),
+		mailer:   cfg.Mailer,
+		mailFrom: cfg.MailFrom,
 	}
 
 	registry := health.New(health.WithTimeout(2 * time.Second))
@@ -124,14 +132,8 @@ func NewApp(dbConn *sql.DB, cfg AppConfig) *bebo.App {
 		return dbConn.PingContext(ctx)
 	})
 
-	app.GET("/health", func(ctx *bebo.Context) error {
-		registry.Handler().ServeHTTP(ctx.ResponseWriter, ctx.Request)
-		return nil
-	})
-	app.GET("/ready", func(ctx *bebo.Context) error {
-		registry.ReadyHandler().ServeHTTP(ctx.ResponseWriter, ctx.Request)
-		return nil
-	})
+	app.MountMethods([]string{http.MethodGet}, "/health", registry.Handler())
+	app.MountMethods([]string{http.MethodGet}, "/ready", registry.ReadyHandler())
 
 	app.GET("/", server.home)
 	app.GET("/signup", server.signupForm)
@@ -167,12 +169,7 @@ func templatesDir() string {
 }
 
 func userFromContext(ctx *bebo.Context) (*User, bool) {
-	value, ok := ctx.Get(userKey)
-	if !ok {
-		return nil, false
-	}
-	user, ok := value.(*User)
-	return user, ok
+	return userKey.Get(ctx)
 }
 
 func mustUser(ctx *bebo.Context) (*User, error) {
@@ -213,7 +210,7 @@ func (s *Server) signIn(ctx *bebo.Context, user *User) error {
 		return err
 	}
 	sess.Set("user_id", strconv.FormatInt(user.ID, 10))
-	return sess.Save(ctx.ResponseWriter)
+	return sess.Regenerate(ctx.ResponseWriter)
 }
 
 func (s *Server) requireUser(redirectToLogin bool) bebo.Middleware {
@@ -233,7 +230,7 @@ func (s *Server) requireUser(redirectToLogin bool) bebo.Middleware {
 				}
 				return apperr.Unauthorized("login required", nil)
 			}
-			ctx.Set(userKey, user)
+			userKey.Set(ctx, user)
 			return next(ctx)
 		}
 	}
@@ -300,6 +297,7 @@ func (s *Server) signup(ctx *bebo.Context) error {
 	if err := s.signIn(ctx, user); err != nil {
 		return err
 	}
+	s.sendWelcome(ctx, user)
 	if err := s.flash.Add(ctx.ResponseWriter, ctx.Request, flash.Message{
 		Type: "success",
 		Text: "Account created.",
@@ -309,6 +307,22 @@ func (s *Server) signup(ctx *bebo.Context) error {
 	return redirect(ctx, "/notes")
 }
 
+// sendWelcome queues a confirmation email; a mail failure does not fail signup.
+func (s *Server) sendWelcome(ctx *bebo.Context, user *User) {
+	if s.mailer == nil {
+		return
+	}
+	err := s.mailer.Send(ctx.Request.Context(), mail.Message{
+		From:    s.mailFrom,
+		To:      []string{user.Email},
+		Subject: "Welcome to Bebo Notes",
+		Text:    "Your account is ready. Sign in any time to manage your notes.",
+	})
+	if err != nil {
+		ctx.Logger().Error("welcome email failed", slog.String("error", err.Error()))
+	}
+}
+
 func (s *Server) loginForm(ctx *bebo.Context) error {
 	if user, err := s.currentUser(ctx); err != nil {
 		return err
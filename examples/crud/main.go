@@ -10,9 +10,12 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/devmarvs/bebo"
 	"github.com/devmarvs/bebo/config"
 	"github.com/devmarvs/bebo/db"
+	"github.com/devmarvs/bebo/mail"
 	"github.com/devmarvs/bebo/migrate"
+	"github.com/devmarvs/bebo/tasks"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -34,12 +37,46 @@ func main() {
 		}
 	}
 
+	mailer, err := newMailer()
+	if err != nil {
+		log.Fatalf("mail: %v", err)
+	}
+	runner := tasks.New(tasks.DefaultOptions())
+	runner.Start(context.Background())
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = runner.Shutdown(ctx)
+	}()
+	cfg.Mailer = mail.Async(runner, mailer)
+
 	app := NewApp(dbConn, cfg)
 	if err := app.RunWithSignals(); err != nil {
 		log.Fatalf("run: %v", err)
 	}
 }
 
+// newMailer uses SMTP when BEBO_SMTP_ADDR is set and logs messages otherwise.
+func newMailer() (mail.Mailer, error) {
+	addr := envString("BEBO_SMTP_ADDR", "")
+	if addr == "" {
+		return logMailer{}, nil
+	}
+	return mail.NewSMTP(mail.SMTPOptions{
+		Addr:     addr,
+		Username: envString("BEBO_SMTP_USERNAME", ""),
+		Password: envString("BEBO_SMTP_PASSWORD", ""),
+	})
+}
+
+// logMailer prints messages instead of sending them during development.
+type logMailer struct{}
+
+func (logMailer) Send(_ context.Context, msg mail.Message) error {
+	log.Printf("mail to=%v subject=%q\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
+
 func loadConfig() AppConfig {
 	appCfg := config.LoadFromEnv("BEBO_", config.Default())
 	databaseURL := envString("BEBO_DATABASE_URL", "")
@@ -53,7 +90,10 @@ func loadConfig() AppConfig {
 	sessionKey := envString("BEBO_SESSION_KEY", "")
 	if sessionKey == "" {
 		sessionKey = "dev-session-key-change-me"
-		log.Print("warning: using default session key")
+	}
+	production := envString("BEBO_ENV", "") == "production"
+	if err := bebo.CheckKeys(map[string][]byte{"BEBO_SESSION_KEY": []byte(sessionKey)}, bebo.KeyCheckOptions{Production: production}); err != nil {
+		log.Fatalf("config: %v", err)
 	}
 
 	secureCookies := envBool("BEBO_SECURE_COOKIES", false)
@@ -65,6 +105,7 @@ func loadConfig() AppConfig {
 		SessionKey:    []byte(sessionKey),
 		SecureCookies: secureCookies,
 		AutoMigrate:   autoMigrate,
+		MailFrom:      envString("BEBO_MAIL_FROM", "Bebo Notes <no-reply@localhost>"),
 	}
 }
 
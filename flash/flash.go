@@ -12,10 +12,8 @@ import (
 var ErrStoreMissing = errors.New("flash store missing")
 
 // Message represents a flash message.
-type Message struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
-}
+// It shares its encoding with session.Session.Flash, so both APIs read the same messages.
+type Message = session.FlashMessage
 
 // Store persists flash messages in a session store.
 type Store struct {
@@ -25,7 +23,7 @@ type Store struct {
 
 // New creates a flash store with default key.
 func New(store session.Store) Store {
-	return Store{Store: store, Key: "bebo_flash"}
+	return Store{Store: store, Key: session.FlashKey}
 }
 
 // Add appends a flash message and saves the session.
@@ -76,7 +74,7 @@ func (s Store) getSession(r *http.Request) (*session.Session, error) {
 		return nil, ErrStoreMissing
 	}
 	if s.Key == "" {
-		s.Key = "bebo_flash"
+		s.Key = session.FlashKey
 	}
 	sess, err := s.Store.Get(r)
 	if err != nil {
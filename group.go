@@ -4,9 +4,11 @@ import "strings"
 
 // Group defines a route group with a common prefix and middleware.
 type Group struct {
-	app        *App
-	prefix     string
-	middleware []Middleware
+	app          *App
+	prefix       string
+	namePrefix   string
+	middleware   []Middleware
+	errorHandler ErrorHandler
 }
 
 // Group creates a new route group.
@@ -25,7 +27,22 @@ func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
 	joined := joinPaths(g.prefix, prefix)
 	combined := append([]Middleware{}, g.middleware...)
 	combined = append(combined, middleware...)
-	return &Group{app: g.app, prefix: joined, middleware: combined}
+	return &Group{app: g.app, prefix: joined, namePrefix: g.namePrefix, middleware: combined, errorHandler: g.errorHandler}
+}
+
+// OnError sets the error handler for routes registered on the group afterwards.
+// Nested groups inherit it; routes without one fall back to the app handler.
+func (g *Group) OnError(handler ErrorHandler) {
+	g.errorHandler = handler
+}
+
+// WithNamePrefix returns a copy of the group that prefixes route names, so
+// WithName("index") registers as "<prefix>.index". Nested groups inherit and
+// extend the prefix.
+func (g *Group) WithNamePrefix(prefix string) *Group {
+	clone := *g
+	clone.namePrefix = joinNames(g.namePrefix, prefix)
+	return &clone
 }
 
 // Route registers a route with options in the group.
@@ -71,9 +88,37 @@ func (g *Group) handle(method, path string, handler Handler, middleware []Middle
 	fullPath := joinPaths(g.prefix, path)
 	combined := append([]Middleware{}, g.middleware...)
 	combined = append(combined, middleware...)
+	if g.namePrefix != "" {
+		prefix := g.namePrefix
+		options = append(append([]RouteOption{}, options...), func(cfg *routeConfig) {
+			if cfg.name != "" {
+				cfg.name = joinNames(prefix, cfg.name)
+			}
+		})
+	}
+	if g.errorHandler != nil {
+		errorHandler := g.errorHandler
+		options = append(append([]RouteOption{}, options...), func(cfg *routeConfig) {
+			if cfg.errorHandler == nil {
+				cfg.errorHandler = errorHandler
+			}
+		})
+	}
 	g.app.handleWithOptions(method, fullPath, handler, combined, options...)
 }
 
+func joinNames(prefix, name string) string {
+	prefix = strings.Trim(prefix, ".")
+	name = strings.Trim(name, ".")
+	if prefix == "" {
+		return name
+	}
+	if name == "" {
+		return prefix
+	}
+	return prefix + "." + name
+}
+
 func joinPaths(base, path string) string {
 	if base == "" {
 		return cleanPrefix(path)
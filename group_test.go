@@ -1,6 +1,13 @@
 package bebo
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo/apperr"
+)
 
 func TestJoinPaths(t *testing.T) {
 	cases := []struct {
@@ -22,3 +29,92 @@ func TestJoinPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestGroupNamePrefix(t *testing.T) {
+	app := New()
+	notes := app.Group("/notes").WithNamePrefix("notes")
+	notes.Route(http.MethodGet, "", func(ctx *Context) error { return nil }, WithName("index"))
+	notes.Route(http.MethodGet, "/:id", func(ctx *Context) error { return nil }, WithName("show"))
+
+	admin := app.Group("/admin").WithNamePrefix("admin")
+	users := admin.Group("/users").WithNamePrefix("users")
+	users.Route(http.MethodGet, "/:id", func(ctx *Context) error { return nil }, WithName("show"))
+
+	if info, ok := app.RouteInfo("notes.index"); !ok || info.Pattern != "/notes" {
+		t.Fatalf("expected notes.index route, got %+v %v", info, ok)
+	}
+	if path, ok := app.Path("notes.show", map[string]string{"id": "7"}); !ok || path != "/notes/7" {
+		t.Fatalf("expected notes.show path, got %q %v", path, ok)
+	}
+	if path, ok := app.Path("admin.users.show", map[string]string{"id": "3"}); !ok || path != "/admin/users/3" {
+		t.Fatalf("expected nested group path, got %q %v", path, ok)
+	}
+	if _, ok := app.RouteInfo("show"); ok {
+		t.Fatalf("expected unprefixed name to be unregistered")
+	}
+}
+
+func TestGroupMiddlewareOrder(t *testing.T) {
+	app := New()
+	var order []string
+	mark := func(label string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx *Context) error {
+				order = append(order, label)
+				return next(ctx)
+			}
+		}
+	}
+
+	api := app.Group("/api", mark("api"))
+	v1 := api.Group("/v1", mark("v1"))
+	v1.GET("/items", func(ctx *Context) error {
+		order = append(order, "handler")
+		return ctx.Text(http.StatusOK, "ok")
+	}, mark("route"))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+
+	want := []string{"api", "v1", "route", "handler"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+}
+
+func TestGroupOnError(t *testing.T) {
+	app := New(WithErrorHandler(func(ctx *Context, err error) {
+		ctx.ResponseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+		ctx.ResponseWriter.WriteHeader(http.StatusNotFound)
+		_, _ = ctx.ResponseWriter.Write([]byte("<h1>" + err.Error() + "</h1>"))
+	}))
+	api := app.Group("/api")
+	api.OnError(func(ctx *Context, err error) {
+		_ = ctx.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	})
+	fail := func(ctx *Context) error {
+		return apperr.NotFound("missing", nil)
+	}
+	api.GET("/items", fail)
+	api.Group("/v1").GET("/items", fail)
+	app.GET("/items", fail)
+
+	for _, path := range []string{"/api/items", "/api/v1/items"} {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+			t.Fatalf("%s: expected json error, got %q", path, rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(rec.Body.String(), `"error":"not_found: missing"`) {
+			t.Fatalf("%s: unexpected body %q", path, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("expected html error, got %q", rec.Header().Get("Content-Type"))
+	}
+	if rec.Body.String() != "<h1>not_found: missing</h1>" {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+}
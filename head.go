@@ -0,0 +1,69 @@
+package bebo
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/devmarvs/bebo/internal/httpx"
+)
+
+// headResponseWriter discards the body of GET handlers serving HEAD requests.
+// The header is delayed until the handler finishes so Content-Length can be
+// derived from the discarded body.
+type headResponseWriter struct {
+	writer      http.ResponseWriter
+	status      httpx.WriteHeaderOnce
+	bytes       int
+	wroteHeader bool
+}
+
+func newHeadResponseWriter(w http.ResponseWriter) *headResponseWriter {
+	return &headResponseWriter{writer: w}
+}
+
+func (h *headResponseWriter) Header() http.Header {
+	return h.writer.Header()
+}
+
+func (h *headResponseWriter) WriteHeader(status int) {
+	if h.wroteHeader || !h.status.Record(status) {
+		return
+	}
+	if status < http.StatusOK {
+		h.writer.WriteHeader(status)
+	}
+}
+
+func (h *headResponseWriter) Write(p []byte) (int, error) {
+	h.status.Record(http.StatusOK)
+	h.bytes += len(p)
+	return len(p), nil
+}
+
+func (h *headResponseWriter) Flush() {
+	h.finish()
+	if flusher, ok := h.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (h *headResponseWriter) Unwrap() http.ResponseWriter {
+	return h.writer
+}
+
+func (h *headResponseWriter) finish() {
+	if h.wroteHeader {
+		return
+	}
+	h.wroteHeader = true
+	status := h.status.Status()
+	header := h.writer.Header()
+	if header.Get("Content-Length") == "" && h.bytes > 0 && bodyAllowed(status) {
+		header.Set("Content-Length", strconv.Itoa(h.bytes))
+	}
+	h.writer.WriteHeader(status)
+}
+
+func bodyAllowed(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified && status >= http.StatusOK
+}
@@ -0,0 +1,39 @@
+package bebo
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ErrHijackNotSupported indicates the response writer cannot be hijacked,
+// e.g. on HTTP/2 or behind a buffering timeout writer.
+var ErrHijackNotSupported = errors.New("response writer does not support hijacking")
+
+// Hijack takes over the underlying connection, unwrapping middleware writers
+// as needed. Afterwards the caller owns the connection and must close it; the
+// context counts as aborted, so writes through ctx.ResponseWriter are dropped
+// and a returned error is not rendered.
+func (c *Context) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if c.hijacked {
+		return nil, nil, http.ErrHijacked
+	}
+	conn, rw, err := http.NewResponseController(c.ResponseWriter).Hijack()
+	if err != nil {
+		if errors.Is(err, http.ErrNotSupported) {
+			return nil, nil, ErrHijackNotSupported
+		}
+		return nil, nil, err
+	}
+
+	c.hijacked = true
+	c.aborted = true
+	c.ResponseWriter = &abortedWriter{header: make(http.Header)}
+	return conn, rw, nil
+}
+
+// IsHijacked reports whether Hijack took over the connection.
+func (c *Context) IsHijacked() bool {
+	return c.hijacked
+}
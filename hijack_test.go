@@ -0,0 +1,99 @@
+package bebo
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.conn, bufio.NewReadWriter(bufio.NewReader(h.conn), bufio.NewWriter(h.conn)), nil
+}
+
+func TestContextHijackSuppressesFrameworkWrites(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	app := New()
+	app.GET("/raw", func(ctx *Context) error {
+		conn, rw, err := ctx.Hijack()
+		if err != nil {
+			return err
+		}
+		if !ctx.IsHijacked() || !ctx.IsAborted() {
+			t.Error("expected context to be marked hijacked")
+		}
+		_, _ = rw.WriteString("HTTP/1.1 200 OK\r\nConnection: close\r\n\r\nraw")
+		_ = rw.Flush()
+		_ = conn.Close()
+
+		if _, _, err := ctx.Hijack(); !errors.Is(err, http.ErrHijacked) {
+			t.Errorf("expected second hijack to fail, got %v", err)
+		}
+		_ = ctx.Text(http.StatusTeapot, "ignored")
+		return apperr.Internal("after hijack", nil)
+	})
+
+	received := make(chan string, 1)
+	go func() {
+		data, _ := io.ReadAll(client)
+		received <- string(data)
+	}()
+
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/raw", nil))
+
+	if got := <-received; !strings.HasSuffix(got, "\r\n\r\nraw") {
+		t.Fatalf("expected raw response on conn, got %q", got)
+	}
+	if rec.Body.Len() != 0 || rec.Code != http.StatusOK || len(rec.Result().Header) != 0 {
+		t.Fatalf("expected framework writes suppressed, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestContextHijackUnsupported(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, New())
+	if _, _, err := ctx.Hijack(); !errors.Is(err, ErrHijackNotSupported) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+	if ctx.IsHijacked() || ctx.IsAborted() {
+		t.Fatal("expected context unchanged after failed hijack")
+	}
+}
+
+func TestContextHijackOverServer(t *testing.T) {
+	app := New()
+	app.GET("/raw", func(ctx *Context) error {
+		conn, rw, err := ctx.Hijack()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 202 Accepted\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+		return rw.Flush()
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/raw")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusAccepted || string(body) != "ok" {
+		t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,264 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ErrCassetteMiss indicates no recorded response matches a request in replay mode.
+var ErrCassetteMiss = errors.New("no recorded response for request")
+
+// CassetteMode controls whether a cassette records or replays responses.
+type CassetteMode int
+
+const (
+	// CassetteReplay serves recorded responses and never calls the network.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends requests through Base and records the responses.
+	CassetteRecord
+	// CassetteReplayOrRecord replays when a recording exists and records otherwise.
+	CassetteReplayOrRecord
+)
+
+// DefaultCassetteRedactHeaders lists the credential headers a cassette redacts by default.
+func DefaultCassetteRedactHeaders() []string {
+	return []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+}
+
+// CassetteRedacted replaces redacted header values in recorded interactions.
+const CassetteRedacted = "[REDACTED]"
+
+// CassetteRoundTripper records responses to a fixtures file and replays them
+// for deterministic tests. Interactions are keyed by method, URL, and a hash
+// of the request body.
+//
+// Before an interaction is saved, the values of RedactHeaders (default
+// DefaultCassetteRedactHeaders unless DisableDefaults is set) are replaced with
+// CassetteRedacted, then Redact, when set, may edit the recorded header and
+// body further. The response returned while recording is left untouched.
+type CassetteRoundTripper struct {
+	Base            http.RoundTripper
+	Path            string
+	Mode            CassetteMode
+	DisableDefaults bool
+	RedactHeaders   []string
+	Redact          func(header http.Header, body []byte) []byte
+
+	mu           sync.Mutex
+	loaded       bool
+	interactions map[string]cassetteInteraction
+}
+
+type cassetteFile struct {
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Key      string      `json:"key"`
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	BodyHash string      `json:"body_hash"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header,omitempty"`
+	Body     []byte      `json:"body,omitempty"`
+}
+
+// RoundTrip replays or records the request depending on Mode.
+func (c *CassetteRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	body, outbound, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := cassetteKey(req, body)
+
+	c.mu.Lock()
+	if err := c.load(); err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	interaction, ok := c.interactions[key]
+	c.mu.Unlock()
+
+	if c.Mode != CassetteRecord && ok {
+		return interaction.response(req), nil
+	}
+	if c.Mode == CassetteReplay {
+		return nil, fmt.Errorf("%w: %s %s", ErrCassetteMiss, req.Method, req.URL.String())
+	}
+	return c.record(outbound, key, body)
+}
+
+func (c *CassetteRoundTripper) record(req *http.Request, key string, body []byte) (*http.Response, error) {
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header, recordedBody := c.redact(resp.Header.Clone(), bytes.Clone(respBody))
+	interaction := cassetteInteraction{
+		Key:      key,
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		BodyHash: hashBody(body),
+		Status:   resp.StatusCode,
+		Header:   header,
+		Body:     recordedBody,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions[key] = interaction
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// redact scrubs credentials from a recorded header and body.
+func (c *CassetteRoundTripper) redact(header http.Header, body []byte) (http.Header, []byte) {
+	names := c.RedactHeaders
+	if len(names) == 0 && !c.DisableDefaults {
+		names = DefaultCassetteRedactHeaders()
+	}
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		redacted := make([]string, len(values))
+		for i := range redacted {
+			redacted[i] = CassetteRedacted
+		}
+		header[http.CanonicalHeaderKey(name)] = redacted
+	}
+	if c.Redact != nil {
+		body = c.Redact(header, body)
+	}
+	return header, body
+}
+
+// load reads the cassette file once; a missing file starts an empty cassette.
+func (c *CassetteRoundTripper) load() error {
+	if c.loaded {
+		return nil
+	}
+	c.interactions = make(map[string]cassetteInteraction)
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			c.loaded = true
+			return nil
+		}
+		return err
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("cassette %s: %w", c.Path, err)
+	}
+	for _, interaction := range file.Interactions {
+		c.interactions[interaction.Key] = interaction
+	}
+	c.loaded = true
+	return nil
+}
+
+func (c *CassetteRoundTripper) save() error {
+	file := cassetteFile{Interactions: make([]cassetteInteraction, 0, len(c.interactions))}
+	for _, interaction := range c.interactions {
+		file.Interactions = append(file.Interactions, interaction)
+	}
+	sort.Slice(file.Interactions, func(i, j int) bool {
+		return file.Interactions[i].Key < file.Interactions[j].Key
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := c.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.Path)
+}
+
+func (i cassetteInteraction) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        i.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(i.Body)),
+		ContentLength: int64(len(i.Body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody returns the request body and the request to send upstream.
+// The caller's request is never modified: it is reused when there is no body,
+// and otherwise cloned with a fresh copy of the body.
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	source := req.Body
+	if req.GetBody != nil {
+		if fresh, err := req.GetBody(); err == nil {
+			req.Body.Close()
+			source = fresh
+		}
+	}
+	body, err := io.ReadAll(source)
+	source.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	outbound := req.Clone(req.Context())
+	outbound.Body = io.NopCloser(bytes.NewReader(body))
+	outbound.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, outbound, nil
+}
+
+func cassetteKey(req *http.Request, body []byte) string {
+	return req.Method + " " + req.URL.String() + " " + hashBody(body)
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,169 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network disabled")
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Upstream", "yes")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("echo:" + string(body)))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "cassette.json")
+
+	recorder := &http.Client{Transport: &CassetteRoundTripper{Path: path, Mode: CassetteRecord}}
+	resp, err := recorder.Post(server.URL+"/items", "text/plain", strings.NewReader("one"))
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	recorded, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(recorded) != "echo:one" {
+		t.Fatalf("unexpected recorded body %q", recorded)
+	}
+
+	replayer := &http.Client{Transport: &CassetteRoundTripper{Base: failingTransport{}, Path: path, Mode: CassetteReplay}}
+	resp, err = replayer.Post(server.URL+"/items", "text/plain", strings.NewReader("one"))
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	replayed, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated || string(replayed) != string(recorded) {
+		t.Fatalf("unexpected replay %d %q", resp.StatusCode, replayed)
+	}
+	if resp.Header.Get("X-Upstream") != "yes" {
+		t.Fatalf("expected recorded headers")
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected a single upstream hit, got %d", got)
+	}
+
+	_, err = replayer.Post(server.URL+"/items", "text/plain", strings.NewReader("two"))
+	if !errors.Is(err, ErrCassetteMiss) {
+		t.Fatalf("expected cassette miss for different body, got %v", err)
+	}
+}
+
+func TestCassetteReplayOrRecord(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &CassetteRoundTripper{Path: filepath.Join(t.TempDir(), "cassette.json"), Mode: CassetteReplayOrRecord}}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/status")
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected second request to replay, got %d hits", got)
+	}
+}
+
+func TestCassetteLeavesCallerRequestAlone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	transport := &CassetteRoundTripper{Path: filepath.Join(t.TempDir(), "cassette.json"), Mode: CassetteRecord}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/items", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	original := req.Body
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "payload" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if req.Body != original {
+		t.Fatalf("expected caller request body to be left in place")
+	}
+}
+
+func TestCassetteRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret-cookie"})
+		w.Header().Set("X-Api-Key", "secret-key")
+		_, _ = w.Write([]byte(`{"token":"secret-token"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := &CassetteRoundTripper{
+		Path:          path,
+		Mode:          CassetteRecord,
+		RedactHeaders: append(DefaultCassetteRedactHeaders(), "X-Api-Key"),
+		Redact: func(_ http.Header, body []byte) []byte {
+			return bytes.ReplaceAll(body, []byte("secret-token"), []byte("redacted-token"))
+		},
+	}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/login", nil)
+	req.Header.Set("Authorization", "Bearer secret-auth")
+	resp, err := recorder.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	live, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(resp.Header.Get("Set-Cookie"), "secret-cookie") || !strings.Contains(string(live), "secret-token") {
+		t.Fatalf("expected the live response to be untouched")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	for _, secret := range []string{"secret-cookie", "secret-key", "secret-token", "secret-auth"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("expected %q to be redacted from %s", secret, data)
+		}
+	}
+
+	replayer := &CassetteRoundTripper{Base: failingTransport{}, Path: path, Mode: CassetteReplay}
+	resp, err = replayer.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("Set-Cookie") != CassetteRedacted {
+		t.Fatalf("expected redacted Set-Cookie, got %q", resp.Header.Get("Set-Cookie"))
+	}
+}
@@ -0,0 +1,144 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HedgeOptions configures hedged requests.
+type HedgeOptions struct {
+	// Delay is how long to wait for a response before sending another attempt.
+	Delay time.Duration
+	// MaxAttempts is the total number of in-flight attempts allowed, including
+	// the first. Values below 2 disable hedging.
+	MaxAttempts int
+}
+
+// HedgeRoundTripper sends a duplicate request when the previous attempt has
+// not answered within Delay, and returns the first response. The remaining
+// attempts are canceled. Only idempotent requests whose body can be replayed
+// (no body, or GetBody set) are hedged; others pass straight through.
+type HedgeRoundTripper struct {
+	Base    http.RoundTripper
+	Options HedgeOptions
+}
+
+type hedgeResult struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// RoundTrip executes the request with hedging.
+func (h *HedgeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := h.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	opts := normalizeHedgeOptions(h.Options)
+	if opts.MaxAttempts < 2 || !hedgeable(req) {
+		return base.RoundTrip(req)
+	}
+
+	results := make(chan hedgeResult, opts.MaxAttempts)
+	cancels := make([]context.CancelFunc, 0, opts.MaxAttempts)
+	launch := func() error {
+		attemptCtx, cancel := context.WithCancel(req.Context())
+		attempt, err := cloneRequest(req, len(cancels))
+		if err != nil {
+			cancel()
+			return err
+		}
+		attempt = attempt.WithContext(attemptCtx)
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := base.RoundTrip(attempt)
+			results <- hedgeResult{index: index, resp: resp, err: err}
+		}()
+		return nil
+	}
+
+	if err := launch(); err != nil {
+		return nil, err
+	}
+	pending := 1
+	timer := time.NewTimer(opts.Delay)
+	defer timer.Stop()
+
+	var lastErr error
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				for i, cancel := range cancels {
+					if i != result.index {
+						cancel()
+					}
+				}
+				go discardHedges(results, pending)
+				if result.resp.Body == nil {
+					cancels[result.index]()
+				} else {
+					result.resp.Body = &cancelOnClose{ReadCloser: result.resp.Body, cancel: cancels[result.index]}
+				}
+				return result.resp, nil
+			}
+			cancels[result.index]()
+			lastErr = result.err
+			// Fail over at once instead of waiting for the timer.
+			if pending == 0 && len(cancels) < opts.MaxAttempts && req.Context().Err() == nil {
+				if err := launch(); err == nil {
+					pending++
+				}
+			}
+		case <-timer.C:
+			if len(cancels) < opts.MaxAttempts {
+				if err := launch(); err == nil {
+					pending++
+				}
+				timer.Reset(opts.Delay)
+			}
+		}
+	}
+	return nil, lastErr
+}
+
+func normalizeHedgeOptions(options HedgeOptions) HedgeOptions {
+	if options.Delay <= 0 {
+		options.Delay = 100 * time.Millisecond
+	}
+	return options
+}
+
+func hedgeable(req *http.Request) bool {
+	if req == nil || !isIdempotent(req.Method) {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// discardHedges closes the bodies of attempts that lose the race.
+func discardHedges(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		result := <-results
+		if result.resp != nil && result.resp.Body != nil {
+			_ = result.resp.Body.Close()
+		}
+	}
+}
+
+// cancelOnClose keeps the winning attempt's context alive until its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,173 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func textResponse(req *http.Request, body string) *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body)), Request: req}
+}
+
+func TestHedgeRoundTripperTakesFastestAndCancelsLoser(t *testing.T) {
+	var calls atomic.Int32
+	loserCanceled := make(chan struct{})
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			<-req.Context().Done()
+			close(loserCanceled)
+			return nil, req.Context().Err()
+		}
+		return textResponse(req, "second"), nil
+	})
+
+	hedge := &HedgeRoundTripper{Base: transport, Options: HedgeOptions{Delay: 10 * time.Millisecond, MaxAttempts: 2}}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := hedge.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("roundtrip: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "second" {
+		t.Fatalf("expected hedged response, got %q", body)
+	}
+
+	select {
+	case <-loserCanceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the slow attempt to be canceled")
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestHedgeRoundTripperSkipsHedgeWhenFast(t *testing.T) {
+	var calls atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return textResponse(req, "ok"), nil
+	})
+
+	hedge := &HedgeRoundTripper{Base: transport, Options: HedgeOptions{Delay: 50 * time.Millisecond, MaxAttempts: 3}}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := hedge.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("roundtrip: %v", err)
+	}
+	resp.Body.Close()
+	time.Sleep(80 * time.Millisecond)
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestHedgeRoundTripperOnlyHedgesReplayableIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		time.Sleep(30 * time.Millisecond)
+		return textResponse(req, "ok"), nil
+	})
+	hedge := &HedgeRoundTripper{Base: transport, Options: HedgeOptions{Delay: time.Millisecond, MaxAttempts: 2}}
+
+	post, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("payload"))
+	resp, err := hedge.RoundTrip(post)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Fatalf("expected POST not to be hedged, got %d attempts", calls.Load())
+	}
+
+	calls.Store(0)
+	put, _ := http.NewRequest(http.MethodPut, "http://example.com", io.NopCloser(strings.NewReader("payload")))
+	resp, err = hedge.RoundTrip(put)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Fatalf("expected PUT without GetBody not to be hedged, got %d attempts", calls.Load())
+	}
+}
+
+func TestHedgeRoundTripperReplaysBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	var calls atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		mu.Unlock()
+		if calls.Add(1) == 1 {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return textResponse(req, "ok"), nil
+	})
+
+	hedge := &HedgeRoundTripper{Base: transport, Options: HedgeOptions{Delay: 5 * time.Millisecond, MaxAttempts: 2}}
+	req, _ := http.NewRequest(http.MethodPut, "http://example.com", bytes.NewReader([]byte("doc")))
+	resp, err := hedge.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("roundtrip: %v", err)
+	}
+	resp.Body.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 || bodies[0] != "doc" || bodies[1] != "doc" {
+		t.Fatalf("expected both attempts to send the body, got %q", bodies)
+	}
+}
+
+func TestHedgeRoundTripperFailsOverOnError(t *testing.T) {
+	var calls atomic.Int32
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return textResponse(req, "ok"), nil
+	})
+
+	hedge := &HedgeRoundTripper{Base: transport, Options: HedgeOptions{Delay: time.Hour, MaxAttempts: 2}}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := hedge.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected failover to second attempt, got %v", err)
+	}
+	resp.Body.Close()
+
+	allFail := &HedgeRoundTripper{Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("down")
+	}), Options: HedgeOptions{Delay: time.Hour, MaxAttempts: 2}}
+	if _, err := allFail.RoundTrip(req); err == nil || err.Error() != "down" {
+		t.Fatalf("expected last error, got %v", err)
+	}
+}
+
+func TestNewClientWithHedge(t *testing.T) {
+	client := NewClient(ClientOptions{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) { return textResponse(req, "ok"), nil }),
+		Hedge:     HedgeOptions{Delay: 10 * time.Millisecond, MaxAttempts: 2},
+	})
+	if _, ok := client.Transport.(*HedgeRoundTripper); !ok {
+		t.Fatalf("expected hedge transport, got %T", client.Transport)
+	}
+	resp, err := client.Get("http://example.com")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+}
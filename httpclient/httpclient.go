@@ -3,9 +3,15 @@ package httpclient
 import (
 	"context"
 	"errors"
+	"math"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/devmarvs/bebo/internal/backoff"
 )
 
 // ErrCircuitOpen indicates the circuit breaker is open.
@@ -26,6 +32,9 @@ type RetryOptions struct {
 	Backoff    BackoffFunc
 	RetryIf    RetryDecider
 	OnRetry    func(attempt int, err error, resp *http.Response)
+	// MaxRetryAfter caps how long a Retry-After response header may delay the
+	// next attempt (default 30s). Negative ignores Retry-After.
+	MaxRetryAfter time.Duration
 }
 
 // RetryRoundTripper retries requests based on RetryOptions.
@@ -69,6 +78,7 @@ func (r *RetryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 			return resp, err
 		}
 
+		retryAfter := parseRetryAfter(resp, time.Now())
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
 		}
@@ -79,6 +89,10 @@ func (r *RetryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		}
 
 		wait := opts.Backoff(attempt)
+		if opts.MaxRetryAfter >= 0 {
+			// Retry-After may lengthen the backoff, never shorten it.
+			wait = max(wait, min(retryAfter, opts.MaxRetryAfter))
+		}
 		if wait > 0 {
 			if err := sleepWithContext(req.Context(), wait, sleep); err != nil {
 				return nil, err
@@ -87,11 +101,11 @@ func (r *RetryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 }
 
-// DefaultRetryOptions returns a retry configuration with exponential backoff.
+// DefaultRetryOptions returns a retry configuration with jittered exponential backoff.
 func DefaultRetryOptions() RetryOptions {
 	return RetryOptions{
 		MaxRetries: 2,
-		Backoff:    ExponentialBackoff(100*time.Millisecond, 2*time.Second),
+		Backoff:    ExponentialBackoffJitter(100*time.Millisecond, 2*time.Second),
 		RetryIf:    DefaultRetryDecider,
 	}
 }
@@ -116,6 +130,25 @@ func ExponentialBackoff(base, max time.Duration) BackoffFunc {
 	}
 }
 
+// ExponentialBackoffJitter returns an exponential backoff with full jitter:
+// each delay is random in [0, base*2^(attempt-1)], capped at max, so many
+// clients retrying at once spread out instead of retrying in lockstep.
+func ExponentialBackoffJitter(base, max time.Duration) BackoffFunc {
+	return ExponentialBackoffJitterSource(base, max, nil)
+}
+
+// ExponentialBackoffJitterSource is ExponentialBackoffJitter drawing from
+// source, e.g. a seeded generator for deterministic tests.
+func ExponentialBackoffJitterSource(base, max time.Duration, source *rand.Rand) BackoffFunc {
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 2 * time.Second
+	}
+	return BackoffFunc(backoff.FullJitter(base, max, source))
+}
+
 // DefaultRetryDecider retries idempotent methods on network errors or 5xx/429 responses.
 func DefaultRetryDecider(req *http.Request, resp *http.Response, err error) bool {
 	if req == nil {
@@ -311,12 +344,19 @@ func DefaultBreakerDecider(req *http.Request, resp *http.Response, err error) bo
 
 // ClientOptions configures a default HTTP client.
 type ClientOptions struct {
-	Timeout           time.Duration
-	Transport         http.RoundTripper
+	Timeout   time.Duration
+	Transport http.RoundTripper
+	// Pool tunes the transport built when Transport is nil.
+	Pool PoolOptions
+	// ConnStats, when set, counts new and reused connections.
+	ConnStats         *ConnStats
 	Retry             RetryOptions
+	Hedge             HedgeOptions
 	Breaker           *CircuitBreaker
 	ShouldTrip        BreakerDecider
 	PropagateMetadata bool
+	// MetadataHeaders selects the propagated headers (see MetadataRoundTripper.Headers).
+	MetadataHeaders []string
 }
 
 // DefaultClientOptions returns a baseline client configuration.
@@ -324,13 +364,24 @@ func DefaultClientOptions() ClientOptions {
 	return ClientOptions{Timeout: 30 * time.Second}
 }
 
-// NewClient builds an http.Client with retries and breaker support.
+// NewClient builds an http.Client with hedging, retries and breaker support.
+// Hedging wraps the base transport, so each retry attempt is hedged.
 func NewClient(options ClientOptions) *http.Client {
 	transport := options.Transport
 	if transport == nil {
 		transport = http.DefaultTransport
+		if !options.Pool.isZero() {
+			transport = NewTransport(options.Pool)
+		}
 	}
 
+	if options.ConnStats != nil {
+		transport = &ConnTrackingRoundTripper{Base: transport, Stats: options.ConnStats}
+	}
+
+	if options.Hedge.MaxAttempts > 1 {
+		transport = &HedgeRoundTripper{Base: transport, Options: options.Hedge}
+	}
 	if options.Retry.MaxRetries > 0 {
 		transport = &RetryRoundTripper{Base: transport, Options: options.Retry}
 	}
@@ -338,7 +389,7 @@ func NewClient(options ClientOptions) *http.Client {
 		transport = &BreakerRoundTripper{Base: transport, Breaker: options.Breaker, ShouldTrip: options.ShouldTrip}
 	}
 	if options.PropagateMetadata {
-		transport = &MetadataRoundTripper{Base: transport}
+		transport = &MetadataRoundTripper{Base: transport, Headers: options.MetadataHeaders}
 	}
 
 	return &http.Client{
@@ -354,9 +405,39 @@ func normalizeRetryOptions(options RetryOptions) RetryOptions {
 	if options.RetryIf == nil {
 		options.RetryIf = DefaultRetryDecider
 	}
+	if options.MaxRetryAfter == 0 {
+		options.MaxRetryAfter = 30 * time.Second
+	}
 	return options
 }
 
+// parseRetryAfter reads a Retry-After header in delta-seconds or HTTP-date
+// form, returning 0 when it is absent, invalid or in the past.
+func parseRetryAfter(resp *http.Response, now time.Time) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		if seconds > int64(math.MaxInt64/time.Second) {
+			return time.Duration(math.MaxInt64)
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
 func cloneRequest(req *http.Request, attempt int) (*http.Request, error) {
 	if attempt == 0 {
 		return req, nil
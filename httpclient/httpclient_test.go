@@ -1,8 +1,10 @@
 package httpclient
 
 import (
+	"context"
 	"errors"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"strings"
 	"testing"
@@ -104,3 +106,122 @@ func TestCircuitBreaker(t *testing.T) {
 		t.Fatalf("expected 200, got %d", resp.StatusCode)
 	}
 }
+
+func TestExponentialBackoffJitter(t *testing.T) {
+	backoff := ExponentialBackoffJitterSource(50*time.Millisecond, 400*time.Millisecond, rand.New(rand.NewPCG(1, 1)))
+	replay := ExponentialBackoffJitterSource(50*time.Millisecond, 400*time.Millisecond, rand.New(rand.NewPCG(1, 1)))
+
+	seen := map[time.Duration]struct{}{}
+	for attempt := 1; attempt <= 30; attempt++ {
+		delay := backoff(attempt)
+		if delay != replay(attempt) {
+			t.Fatalf("attempt %d: expected seeded source to be deterministic", attempt)
+		}
+		if delay < 0 || delay > 400*time.Millisecond {
+			t.Fatalf("attempt %d: delay %v out of range", attempt, delay)
+		}
+		seen[delay] = struct{}{}
+	}
+	if len(seen) < 5 {
+		t.Fatalf("expected jittered delays, got %d distinct values", len(seen))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 15, 4, 5, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":      0,
+		"3":     3 * time.Second,
+		" 120 ": 2 * time.Minute,
+		"-1":    0,
+		"soon":  0,
+		now.Add(90 * time.Second).Format(http.TimeFormat): 90 * time.Second,
+		now.Add(-time.Minute).Format(http.TimeFormat):     0,
+	}
+	for value, want := range cases {
+		resp := &http.Response{Header: http.Header{}}
+		if value != "" {
+			resp.Header.Set("Retry-After", value)
+		}
+		if got := parseRetryAfter(resp, now); got != want {
+			t.Fatalf("%q: expected %v, got %v", value, want, got)
+		}
+	}
+	if parseRetryAfter(nil, now) != 0 {
+		t.Fatal("expected zero for nil response")
+	}
+}
+
+type retryAfterTransport struct {
+	retryAfter string
+	calls      int
+}
+
+func (s *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.calls++
+	resp := &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader("ok")), Request: req}
+	if s.calls == 1 {
+		resp.StatusCode = http.StatusTooManyRequests
+		resp.Header.Set("Retry-After", s.retryAfter)
+	}
+	return resp, nil
+}
+
+func TestRetryRoundTripperHonorsRetryAfter(t *testing.T) {
+	noBackoff := func(int) time.Duration { return 0 }
+
+	// The server asks for 1s; a shorter request deadline interrupts the wait.
+	transport := &retryAfterTransport{retryAfter: "1"}
+	retry := RetryRoundTripper{Base: transport, Options: RetryOptions{MaxRetries: 1, Backoff: noBackoff}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	start := time.Now()
+	if _, err := retry.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded during Retry-After wait, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Fatalf("unexpected wait: %v", elapsed)
+	}
+	if transport.calls != 1 {
+		t.Fatalf("expected no second attempt, got %d calls", transport.calls)
+	}
+
+	// MaxRetryAfter caps a long Retry-After.
+	transport = &retryAfterTransport{retryAfter: "3600"}
+	retry = RetryRoundTripper{Base: transport, Options: RetryOptions{MaxRetries: 1, Backoff: noBackoff, MaxRetryAfter: 20 * time.Millisecond}}
+	req, _ = http.NewRequest(http.MethodGet, "http://example.com", nil)
+	start = time.Now()
+	resp, err := retry.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected retry to succeed, got %v %v", resp, err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("expected capped wait, got %v", elapsed)
+	}
+
+	// A cap below the backoff does not shorten the backoff.
+	transport = &retryAfterTransport{retryAfter: "1"}
+	fixedBackoff := func(int) time.Duration { return 60 * time.Millisecond }
+	retry = RetryRoundTripper{Base: transport, Options: RetryOptions{MaxRetries: 1, Backoff: fixedBackoff, MaxRetryAfter: 10 * time.Millisecond}}
+	req, _ = http.NewRequest(http.MethodGet, "http://example.com", nil)
+	start = time.Now()
+	if _, err := retry.RoundTrip(req); err != nil {
+		t.Fatalf("roundtrip: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Fatalf("expected the backoff to be kept, waited %v", elapsed)
+	}
+
+	// A negative MaxRetryAfter ignores the header.
+	transport = &retryAfterTransport{retryAfter: "3600"}
+	retry = RetryRoundTripper{Base: transport, Options: RetryOptions{MaxRetries: 1, Backoff: noBackoff, MaxRetryAfter: -1}}
+	req, _ = http.NewRequest(http.MethodGet, "http://example.com", nil)
+	start = time.Now()
+	if _, err := retry.RoundTrip(req); err != nil {
+		t.Fatalf("roundtrip: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected Retry-After to be ignored, waited %v", elapsed)
+	}
+}
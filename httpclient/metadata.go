@@ -7,9 +7,22 @@ import (
 	"github.com/devmarvs/bebo"
 )
 
+// DefaultMetadataHeaders returns the built-in metadata headers: the bebo
+// request id and the W3C trace context.
+func DefaultMetadataHeaders() []string {
+	return []string{bebo.RequestIDHeader, bebo.TraceparentHeader, bebo.TracestateHeader}
+}
+
 // MetadataRoundTripper injects request metadata headers into outgoing requests.
+// Metadata comes from the request context (see bebo.RequestMetadataFromContext),
+// so requests built with the inbound context carry the inbound ids. Headers
+// already set on the outgoing request are left untouched.
 type MetadataRoundTripper struct {
 	Base http.RoundTripper
+	// Headers limits propagation to these headers (default: all metadata,
+	// DefaultMetadataHeaders plus RequestMetadata.Headers). Names other than
+	// the built-in metadata headers are looked up in RequestMetadata.Headers.
+	Headers []string
 }
 
 // RoundTrip executes the request with metadata propagation.
@@ -22,7 +35,53 @@ func (m *MetadataRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 		return nil, errors.New("request is nil")
 	}
 
-	metadata := bebo.RequestMetadataFromRequest(req)
-	bebo.InjectRequestMetadataIfMissing(req, metadata)
+	metadata := filterMetadata(bebo.RequestMetadataFromRequest(req), m.Headers)
+	if needsMetadata(req, metadata) {
+		// RoundTrippers must not modify the caller's request.
+		req = req.Clone(req.Context())
+		bebo.InjectRequestMetadataIfMissing(req, metadata)
+	}
 	return base.RoundTrip(req)
 }
+
+func filterMetadata(metadata bebo.RequestMetadata, headers []string) bebo.RequestMetadata {
+	if headers == nil {
+		return metadata
+	}
+	var filtered bebo.RequestMetadata
+	for _, header := range headers {
+		switch http.CanonicalHeaderKey(header) {
+		case http.CanonicalHeaderKey(bebo.RequestIDHeader):
+			filtered.RequestID = metadata.RequestID
+		case http.CanonicalHeaderKey(bebo.TraceparentHeader):
+			filtered.Traceparent = metadata.Traceparent
+		case http.CanonicalHeaderKey(bebo.TracestateHeader):
+			filtered.Tracestate = metadata.Tracestate
+		default:
+			if value := metadata.Headers.Get(header); value != "" {
+				if filtered.Headers == nil {
+					filtered.Headers = http.Header{}
+				}
+				filtered.Headers.Set(header, value)
+			}
+		}
+	}
+	return filtered
+}
+
+func needsMetadata(req *http.Request, metadata bebo.RequestMetadata) bool {
+	missing := func(value, header string) bool {
+		return value != "" && req.Header.Get(header) == ""
+	}
+	if missing(metadata.RequestID, bebo.RequestIDHeader) ||
+		missing(metadata.Traceparent, bebo.TraceparentHeader) ||
+		missing(metadata.Tracestate, bebo.TracestateHeader) {
+		return true
+	}
+	for name := range metadata.Headers {
+		if missing(metadata.Headers.Get(name), name) {
+			return true
+		}
+	}
+	return false
+}
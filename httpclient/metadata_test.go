@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/middleware"
 )
 
 type roundTripperFunc func(*http.Request) (*http.Response, error)
@@ -73,3 +74,114 @@ func TestMetadataRoundTripperRespectsExistingHeaders(t *testing.T) {
 	}
 	_ = resp.Body.Close()
 }
+
+func TestMetadataRoundTripperCustomHeaders(t *testing.T) {
+	metadata := bebo.RequestMetadata{
+		RequestID:   "req-1",
+		Traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	ctx := bebo.WithRequestMetadata(context.Background(), metadata)
+	request := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+
+	rt := &MetadataRoundTripper{
+		Headers: []string{"Traceparent"},
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if got := req.Header.Get(bebo.RequestIDHeader); got != "" {
+				t.Fatalf("expected request id to be skipped, got %q", got)
+			}
+			if got := req.Header.Get(bebo.TraceparentHeader); got != metadata.Traceparent {
+				t.Fatalf("expected traceparent header")
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("ok")),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+
+	resp, err := rt.RoundTrip(request)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	_ = resp.Body.Close()
+	if request.Header.Get(bebo.TraceparentHeader) != "" {
+		t.Fatal("expected caller request to be left unmodified")
+	}
+}
+
+func TestClientPropagatesInboundRequestID(t *testing.T) {
+	var downstreamID string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstreamID = r.Header.Get(bebo.RequestIDHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer downstream.Close()
+
+	client := NewClient(ClientOptions{PropagateMetadata: true})
+	app := bebo.New()
+	app.Use(middleware.RequestID())
+	app.GET("/proxy", func(ctx *bebo.Context) error {
+		req, err := http.NewRequestWithContext(ctx.Request.Context(), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+
+	inbound := rec.Header().Get(bebo.RequestIDHeader)
+	if inbound == "" || downstreamID != inbound {
+		t.Fatalf("expected downstream request id %q, got %q", inbound, downstreamID)
+	}
+}
+
+func TestClientPropagatesCustomRequestIDHeader(t *testing.T) {
+	var correlationID, requestID string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID = r.Header.Get("X-Correlation-Id")
+		requestID = r.Header.Get(bebo.RequestIDHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer downstream.Close()
+
+	client := NewClient(ClientOptions{PropagateMetadata: true, MetadataHeaders: []string{"x-correlation-id"}})
+	app := bebo.New()
+	app.Use(middleware.RequestIDWithOptions(middleware.RequestIDOptions{Header: "X-Correlation-Id"}))
+	app.GET("/proxy", func(ctx *bebo.Context) error {
+		req, err := http.NewRequestWithContext(ctx.Request.Context(), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/proxy", nil)
+	req.Header.Set("X-Correlation-Id", "corr-1")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	if correlationID != "corr-1" {
+		t.Fatalf("expected downstream correlation id, got %q", correlationID)
+	}
+	if requestID != "" {
+		t.Fatalf("expected unselected request id header to be skipped, got %q", requestID)
+	}
+}
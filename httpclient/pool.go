@@ -0,0 +1,131 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/devmarvs/bebo/metrics"
+)
+
+// PoolOptions tunes the connection pool of the transport built by NewClient.
+// Zero fields keep the http.DefaultTransport settings.
+type PoolOptions struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits dialing, active and idle connections per host.
+	MaxConnsPerHost int
+	IdleConnTimeout time.Duration
+}
+
+func (o PoolOptions) isZero() bool {
+	return o == PoolOptions{}
+}
+
+// NewTransport clones http.DefaultTransport and applies the pool options.
+func NewTransport(options PoolOptions) *http.Transport {
+	var transport *http.Transport
+	if base, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = base.Clone()
+	} else {
+		transport = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+
+	if options.MaxIdleConns > 0 {
+		transport.MaxIdleConns = options.MaxIdleConns
+	}
+	if options.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	}
+	if options.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = options.MaxConnsPerHost
+	}
+	if options.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = options.IdleConnTimeout
+	}
+	return transport
+}
+
+// ConnStats counts connections obtained by a ConnTrackingRoundTripper.
+type ConnStats struct {
+	newConns    atomic.Int64
+	reusedConns atomic.Int64
+	idleConns   atomic.Int64
+}
+
+// ConnStatsSnapshot is a point-in-time copy of ConnStats.
+type ConnStatsSnapshot struct {
+	// New counts freshly dialed connections.
+	New int64
+	// Reused counts connections taken from the pool.
+	Reused int64
+	// WasIdle counts reused connections that had been idle in the pool.
+	WasIdle int64
+}
+
+// Snapshot returns the current counters.
+func (s *ConnStats) Snapshot() ConnStatsSnapshot {
+	return ConnStatsSnapshot{
+		New:     s.newConns.Load(),
+		Reused:  s.reusedConns.Load(),
+		WasIdle: s.idleConns.Load(),
+	}
+}
+
+func (s *ConnStats) gotConn(info httptrace.GotConnInfo) {
+	if !info.Reused {
+		s.newConns.Add(1)
+		return
+	}
+	s.reusedConns.Add(1)
+	if info.WasIdle {
+		s.idleConns.Add(1)
+	}
+}
+
+// RegisterMetrics exposes the counters on registry as bebo_httpclient_*
+// samples, labeled client=name when name is set.
+func (s *ConnStats) RegisterMetrics(registry *metrics.Registry, name string) {
+	if registry == nil {
+		return
+	}
+	var labels map[string]string
+	if name != "" {
+		labels = map[string]string{"client": name}
+	}
+
+	registry.AddCollector(func() []metrics.Sample {
+		snap := s.Snapshot()
+		return []metrics.Sample{
+			{Name: "bebo_httpclient_connections_new_total", Help: "Outgoing connections dialed", Type: metrics.CounterType, Labels: labels, Value: float64(snap.New)},
+			{Name: "bebo_httpclient_connections_reused_total", Help: "Outgoing requests served by a pooled connection", Type: metrics.CounterType, Labels: labels, Value: float64(snap.Reused)},
+		}
+	})
+}
+
+// ConnTrackingRoundTripper records new and reused connections via httptrace.
+// Existing client traces on the request context still fire.
+type ConnTrackingRoundTripper struct {
+	Base  http.RoundTripper
+	Stats *ConnStats
+}
+
+// RoundTrip executes the request with connection tracking.
+func (c *ConnTrackingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+	if c.Stats == nil {
+		return base.RoundTrip(req)
+	}
+
+	trace := &httptrace.ClientTrace{GotConn: c.Stats.gotConn}
+	ctx := httptrace.WithClientTrace(req.Context(), trace)
+	return base.RoundTrip(req.WithContext(ctx))
+}
@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo/metrics"
+)
+
+func TestNewTransportAppliesPoolOptions(t *testing.T) {
+	transport := NewTransport(PoolOptions{MaxIdleConnsPerHost: 7, MaxConnsPerHost: 9, IdleConnTimeout: time.Second})
+	if transport.MaxIdleConnsPerHost != 7 || transport.MaxConnsPerHost != 9 || transport.IdleConnTimeout != time.Second {
+		t.Fatalf("unexpected transport settings: %+v", transport)
+	}
+	if transport.MaxIdleConns != http.DefaultTransport.(*http.Transport).MaxIdleConns {
+		t.Fatal("expected unset fields to keep defaults")
+	}
+	if transport == http.DefaultTransport {
+		t.Fatal("expected a cloned transport")
+	}
+}
+
+func TestNewClientBuildsPooledTransport(t *testing.T) {
+	client := NewClient(ClientOptions{Pool: PoolOptions{MaxConnsPerHost: 2}})
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok || transport.MaxConnsPerHost != 2 {
+		t.Fatalf("expected tuned transport, got %T", client.Transport)
+	}
+
+	if NewClient(ClientOptions{}).Transport != http.DefaultTransport {
+		t.Fatal("expected default transport without pool options")
+	}
+}
+
+func TestConnStatsCountsReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var callerTraced int
+	stats := &ConnStats{}
+	client := NewClient(ClientOptions{Pool: PoolOptions{MaxIdleConnsPerHost: 1}, ConnStats: stats})
+	defer client.CloseIdleConnections()
+
+	for i := 0; i < 3; i++ {
+		trace := &httptrace.ClientTrace{GotConn: func(httptrace.GotConnInfo) { callerTraced++ }}
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	snap := stats.Snapshot()
+	if snap.New != 1 || snap.Reused != 2 || snap.WasIdle != 2 {
+		t.Fatalf("unexpected stats: %+v", snap)
+	}
+	if callerTraced != 3 {
+		t.Fatalf("expected caller trace to fire, got %d", callerTraced)
+	}
+
+	registry := metrics.New()
+	stats.RegisterMetrics(registry, "backend")
+	rec := httptest.NewRecorder()
+	metrics.PrometheusHandler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `bebo_httpclient_connections_reused_total{client="backend"} 2`) {
+		t.Fatalf("missing reuse metric:\n%s", rec.Body.String())
+	}
+}
@@ -2,11 +2,13 @@ package otel
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/devmarvs/bebo"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -28,9 +30,19 @@ func (t *Tracer) Start(ctx *bebo.Context) (context.Context, func(status int, err
 	if t == nil || ctx == nil {
 		return context.Background(), nil
 	}
+	return t.StartSpan(ctx, ctx.Request.Method+" "+ctx.Request.URL.Path)
+}
+
+// StartSpan starts a named OpenTelemetry span for the request, continuing any
+// incoming trace context. middleware.Trace calls it with the route-based name.
+func (t *Tracer) StartSpan(ctx *bebo.Context, name string) (context.Context, func(status int, err error)) {
+	if t == nil || ctx == nil {
+		return context.Background(), nil
+	}
 
 	req := ctx.Request
-	spanCtx, span := t.tracer.Start(req.Context(), req.Method+" "+req.URL.Path)
+	parent := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	spanCtx, span := t.tracer.Start(parent, name, trace.WithSpanKind(trace.SpanKindServer))
 
 	attrs := []attribute.KeyValue{
 		attribute.String("http.method", req.Method),
@@ -57,3 +69,8 @@ func (t *Tracer) Start(ctx *bebo.Context) (context.Context, func(status int, err
 		span.End()
 	}
 }
+
+// Inject writes the span context from ctx into header using the global propagator.
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
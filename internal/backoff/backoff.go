@@ -0,0 +1,40 @@
+package backoff
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Exponential returns base doubled per attempt (attempt 1 = base), capped at max.
+func Exponential(base, max time.Duration, attempt int) time.Duration {
+	if attempt <= 1 {
+		return min(base, max)
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		if delay >= max/2 {
+			return max
+		}
+		delay *= 2
+	}
+	return min(delay, max)
+}
+
+// FullJitter returns a func picking a uniform delay in [0, Exponential]
+// (the "full jitter" strategy). A nil source uses the global generator.
+func FullJitter(base, max time.Duration, source *rand.Rand) func(attempt int) time.Duration {
+	var mu sync.Mutex
+	return func(attempt int) time.Duration {
+		ceiling := Exponential(base, max, attempt)
+		if ceiling <= 0 {
+			return 0
+		}
+		if source == nil {
+			return rand.N(ceiling + 1)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return time.Duration(source.Int64N(int64(ceiling) + 1))
+	}
+}
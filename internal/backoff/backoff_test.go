@@ -0,0 +1,41 @@
+package backoff
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestExponentialCapsWithoutOverflow(t *testing.T) {
+	cases := map[int]time.Duration{0: 100 * time.Millisecond, 1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 5: time.Second, 200: time.Second}
+	for attempt, want := range cases {
+		if got := Exponential(100*time.Millisecond, time.Second, attempt); got != want {
+			t.Fatalf("attempt %d: expected %v, got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestFullJitterIsBoundedAndSeeded(t *testing.T) {
+	first := FullJitter(100*time.Millisecond, time.Second, rand.New(rand.NewPCG(1, 2)))
+	second := FullJitter(100*time.Millisecond, time.Second, rand.New(rand.NewPCG(1, 2)))
+
+	distinct := map[time.Duration]struct{}{}
+	for attempt := 1; attempt <= 50; attempt++ {
+		a, b := first(attempt), second(attempt)
+		if a != b {
+			t.Fatalf("attempt %d: expected seeded sources to agree, got %v and %v", attempt, a, b)
+		}
+		if limit := Exponential(100*time.Millisecond, time.Second, attempt); a < 0 || a > limit {
+			t.Fatalf("attempt %d: %v outside [0, %v]", attempt, a, limit)
+		}
+		distinct[a] = struct{}{}
+	}
+	if len(distinct) < 10 {
+		t.Fatalf("expected spread-out delays, got %d distinct values", len(distinct))
+	}
+
+	global := FullJitter(time.Millisecond, time.Second, nil)
+	if got := global(3); got < 0 || got > 4*time.Millisecond {
+		t.Fatalf("unexpected global jitter: %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+// Package httpx holds helpers shared by the response writer wrappers in bebo
+// and its middleware.
+package httpx
+
+import "net/http"
+
+// WriteHeaderOnce tracks the status of a wrapped response so that stacked
+// writers send at most one final WriteHeader. The zero value is ready to use.
+type WriteHeaderOnce struct {
+	status int
+}
+
+// Record notes status and reports whether the call should reach the
+// underlying writer. Informational (1xx) statuses always pass and are not
+// recorded; only the first final status passes, later ones are dropped.
+func (o *WriteHeaderOnce) Record(status int) bool {
+	if status < http.StatusOK {
+		return true
+	}
+	if o.status != 0 {
+		return false
+	}
+	o.status = status
+	return true
+}
+
+// WriteHeader forwards status to w when Record lets it through.
+func (o *WriteHeaderOnce) WriteHeader(w http.ResponseWriter, status int) {
+	if o.Record(status) {
+		w.WriteHeader(status)
+	}
+}
+
+// Written reports whether a final status has been recorded.
+func (o *WriteHeaderOnce) Written() bool {
+	return o.status != 0
+}
+
+// Status returns the recorded final status, or 200 when none was recorded.
+func (o *WriteHeaderOnce) Status() int {
+	if o.status == 0 {
+		return http.StatusOK
+	}
+	return o.status
+}
@@ -0,0 +1,36 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type countingWriter struct {
+	*httptest.ResponseRecorder
+	calls []int
+}
+
+func (c *countingWriter) WriteHeader(status int) {
+	c.calls = append(c.calls, status)
+	c.ResponseRecorder.WriteHeader(status)
+}
+
+func TestWriteHeaderOnce(t *testing.T) {
+	w := &countingWriter{ResponseRecorder: httptest.NewRecorder()}
+	var once WriteHeaderOnce
+	if once.Written() || once.Status() != http.StatusOK {
+		t.Fatalf("expected zero value to be unwritten with default status")
+	}
+
+	once.WriteHeader(w, http.StatusEarlyHints)
+	once.WriteHeader(w, http.StatusCreated)
+	once.WriteHeader(w, http.StatusInternalServerError)
+
+	if len(w.calls) != 2 || w.calls[0] != http.StatusEarlyHints || w.calls[1] != http.StatusCreated {
+		t.Fatalf("unexpected WriteHeader calls: %v", w.calls)
+	}
+	if !once.Written() || once.Status() != http.StatusCreated {
+		t.Fatalf("expected recorded status 201, got %d", once.Status())
+	}
+}
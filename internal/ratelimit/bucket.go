@@ -0,0 +1,35 @@
+// Package ratelimit holds the token bucket shared by the rate limit
+// middleware and the websocket message guard.
+package ratelimit
+
+import "time"
+
+// Bucket is a token bucket refilled at Rate tokens per second up to Burst.
+// It is not safe for concurrent use.
+type Bucket struct {
+	Rate   float64
+	Burst  float64
+	Tokens float64
+	Last   time.Time
+}
+
+// New returns a full bucket.
+func New(rate, burst float64, now time.Time) *Bucket {
+	return &Bucket{Rate: rate, Burst: burst, Tokens: burst, Last: now}
+}
+
+// Take refills the bucket for the time elapsed since the last call and
+// consumes a token when one is available.
+func (b *Bucket) Take(now time.Time) bool {
+	b.Tokens += now.Sub(b.Last).Seconds() * b.Rate
+	if b.Tokens > b.Burst {
+		b.Tokens = b.Burst
+	}
+	b.Last = now
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return true
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketTakeAndRefill(t *testing.T) {
+	start := time.Unix(0, 0)
+	b := New(2, 2, start)
+
+	if !b.Take(start) || !b.Take(start) {
+		t.Fatal("expected burst to be available")
+	}
+	if b.Take(start) {
+		t.Fatal("expected empty bucket")
+	}
+	if !b.Take(start.Add(500 * time.Millisecond)) {
+		t.Fatal("expected a token after refill")
+	}
+	if b.Take(start.Add(500 * time.Millisecond)) {
+		t.Fatal("expected bucket to be empty again")
+	}
+
+	b.Take(start.Add(time.Hour))
+	if b.Tokens != 1 {
+		t.Fatalf("expected refill capped at burst, got %v tokens", b.Tokens)
+	}
+}
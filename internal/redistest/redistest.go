@@ -10,18 +10,63 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
+// Options configures a test server.
+type Options struct {
+	// Now reports the time used for key expiry. Defaults to time.Now; pass
+	// a Clock's Now to control expiry from tests.
+	Now func() time.Time
+}
+
+// Clock is a manually advanced clock for Options.Now.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock starting at the current time.
+func NewClock() *Clock {
+	return &Clock{now: time.Now()}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
 // Start launches a lightweight Redis-compatible server for tests.
 func Start(t *testing.T) (string, func()) {
+	return StartWithOptions(t, Options{})
+}
+
+// StartWithOptions launches a test server with custom options.
+func StartWithOptions(t *testing.T, options Options) (string, func()) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		t.Fatalf("listen: %v", err)
 	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
 
 	server := &Server{
+		now:     options.Now,
 		values:  make(map[string]string),
+		hashes:  make(map[string]map[string]string),
+		expires: make(map[string]time.Time),
 		buckets: make(map[string]*bucket),
+		windows: make(map[string][]int64),
 	}
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -47,8 +92,12 @@ func Start(t *testing.T) (string, func()) {
 // Server implements a subset of Redis commands for tests.
 type Server struct {
 	mu      sync.Mutex
+	now     func() time.Time
 	values  map[string]string
+	hashes  map[string]map[string]string
+	expires map[string]time.Time
 	buckets map[string]*bucket
+	windows map[string][]int64
 }
 
 type bucket struct {
@@ -61,6 +110,9 @@ func (s *Server) handle(conn net.Conn) {
 	reader := bufio.NewReader(conn)
 	writer := bufio.NewWriter(conn)
 
+	var queued [][]string
+	inMulti := false
+	aborted := false
 	for {
 		args, err := readRESPArray(reader)
 		if err != nil {
@@ -71,79 +123,310 @@ func (s *Server) handle(conn net.Conn) {
 			continue
 		}
 		cmd := strings.ToUpper(args[0])
-		switch cmd {
-		case "AUTH":
+		switch {
+		case cmd == "MULTI":
+			inMulti, aborted, queued = true, false, nil
 			_ = writeSimpleString(writer, "OK")
-		case "SELECT":
+		case cmd == "DISCARD":
+			inMulti, aborted, queued = false, false, nil
 			_ = writeSimpleString(writer, "OK")
-		case "SET":
-			if len(args) >= 3 {
-				s.mu.Lock()
-				s.values[args[1]] = args[2]
-				s.mu.Unlock()
+		case cmd == "EXEC":
+			if aborted {
+				_ = writeError(writer, "EXECABORT Transaction discarded because of previous errors.")
+			} else {
+				_, _ = writer.WriteString("*" + strconv.Itoa(len(queued)) + "\r\n")
+				for _, queuedArgs := range queued {
+					s.dispatch(writer, queuedArgs)
+				}
+				_ = writer.Flush()
 			}
-			_ = writeSimpleString(writer, "OK")
-		case "GET":
-			if len(args) < 2 {
-				_ = writeBulkString(writer, nil)
+			inMulti, aborted, queued = false, false, nil
+		case inMulti:
+			if !knownCommands[cmd] {
+				aborted = true
+				_ = writeError(writer, "ERR unknown command")
 				continue
 			}
-			s.mu.Lock()
-			value, ok := s.values[args[1]]
-			s.mu.Unlock()
-			if !ok {
-				_ = writeBulkString(writer, nil)
-				continue
+			queued = append(queued, args)
+			_ = writeSimpleString(writer, "QUEUED")
+		default:
+			s.dispatch(writer, args)
+		}
+	}
+}
+
+var knownCommands = map[string]bool{
+	"AUTH": true, "SELECT": true, "SET": true, "GET": true, "DEL": true, "INCR": true, "EVAL": true,
+	"EXISTS": true, "EXPIRE": true, "PEXPIRE": true, "TTL": true, "HMSET": true, "HMGET": true,
+}
+
+const wrongType = "WRONGTYPE Operation against a key holding the wrong kind of value"
+
+// dispatch executes a single command and writes its reply.
+func (s *Server) dispatch(writer *bufio.Writer, args []string) {
+	cmd := strings.ToUpper(args[0])
+	switch cmd {
+	case "AUTH":
+		_ = writeSimpleString(writer, "OK")
+	case "SELECT":
+		_ = writeSimpleString(writer, "OK")
+	case "SET":
+		if len(args) < 3 {
+			_ = writeError(writer, "ERR wrong number of arguments")
+			return
+		}
+		var ttl time.Duration
+		for i := 3; i < len(args); i++ {
+			option := strings.ToUpper(args[i])
+			if (option != "EX" && option != "PX") || i+1 >= len(args) {
+				_ = writeError(writer, "ERR syntax error")
+				return
 			}
-			_ = writeBulkString(writer, []byte(value))
-		case "DEL":
-			removed := 0
-			s.mu.Lock()
-			for _, key := range args[1:] {
-				if _, ok := s.values[key]; ok {
-					delete(s.values, key)
-					removed++
-				}
-				delete(s.buckets, key)
+			amount, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || amount <= 0 {
+				_ = writeError(writer, "ERR invalid expire time in 'set' command")
+				return
 			}
-			s.mu.Unlock()
-			_ = writeInteger(writer, removed)
-		case "EVAL":
-			allowed, err := s.evalTokenBucket(args)
-			if err != nil {
-				_ = writeError(writer, err.Error())
-				continue
+			ttl = time.Duration(amount) * time.Millisecond
+			if option == "EX" {
+				ttl = time.Duration(amount) * time.Second
+			}
+			i++
+		}
+		s.mu.Lock()
+		s.deleteLocked(args[1])
+		s.values[args[1]] = args[2]
+		if ttl > 0 {
+			s.expires[args[1]] = s.now().Add(ttl)
+		}
+		s.mu.Unlock()
+		_ = writeSimpleString(writer, "OK")
+	case "GET":
+		if len(args) < 2 {
+			_ = writeBulkString(writer, nil)
+			return
+		}
+		s.mu.Lock()
+		s.expireLocked(args[1])
+		value, ok := s.values[args[1]]
+		_, isHash := s.hashes[args[1]]
+		s.mu.Unlock()
+		if isHash {
+			_ = writeError(writer, wrongType)
+			return
+		}
+		if !ok {
+			_ = writeBulkString(writer, nil)
+			return
+		}
+		_ = writeBulkString(writer, []byte(value))
+	case "DEL":
+		removed := 0
+		s.mu.Lock()
+		for _, key := range args[1:] {
+			s.expireLocked(key)
+			if s.existsLocked(key) {
+				removed++
+			}
+			s.deleteLocked(key)
+			delete(s.buckets, key)
+			delete(s.windows, key)
+		}
+		s.mu.Unlock()
+		_ = writeInteger(writer, removed)
+	case "EXISTS":
+		if len(args) < 2 {
+			_ = writeError(writer, "ERR wrong number of arguments")
+			return
+		}
+		count := 0
+		s.mu.Lock()
+		for _, key := range args[1:] {
+			s.expireLocked(key)
+			if s.existsLocked(key) {
+				count++
 			}
-			_ = writeInteger(writer, allowed)
+		}
+		s.mu.Unlock()
+		_ = writeInteger(writer, count)
+	case "EXPIRE", "PEXPIRE":
+		if len(args) != 3 {
+			_ = writeError(writer, "ERR wrong number of arguments")
+			return
+		}
+		amount, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			_ = writeError(writer, "ERR value is not an integer or out of range")
+			return
+		}
+		ttl := time.Duration(amount) * time.Millisecond
+		if cmd == "EXPIRE" {
+			ttl = time.Duration(amount) * time.Second
+		}
+		s.mu.Lock()
+		s.expireLocked(args[1])
+		if !s.existsLocked(args[1]) {
+			s.mu.Unlock()
+			_ = writeInteger(writer, 0)
+			return
+		}
+		if ttl <= 0 {
+			s.deleteLocked(args[1])
+		} else {
+			s.expires[args[1]] = s.now().Add(ttl)
+		}
+		s.mu.Unlock()
+		_ = writeInteger(writer, 1)
+	case "TTL":
+		if len(args) != 2 {
+			_ = writeError(writer, "ERR wrong number of arguments")
+			return
+		}
+		s.mu.Lock()
+		s.expireLocked(args[1])
+		exists := s.existsLocked(args[1])
+		deadline, hasTTL := s.expires[args[1]]
+		now := s.now()
+		s.mu.Unlock()
+		switch {
+		case !exists:
+			_ = writeInteger(writer, -2)
+		case !hasTTL:
+			_ = writeInteger(writer, -1)
 		default:
-			_ = writeError(writer, "unknown command")
+			// Redis rounds the remaining milliseconds to the nearest second.
+			_ = writeInteger(writer, int((deadline.Sub(now)+500*time.Millisecond)/time.Second))
+		}
+	case "HMSET":
+		if len(args) < 4 || len(args)%2 != 0 {
+			_ = writeError(writer, "ERR wrong number of arguments")
+			return
+		}
+		s.mu.Lock()
+		s.expireLocked(args[1])
+		if _, ok := s.values[args[1]]; ok {
+			s.mu.Unlock()
+			_ = writeError(writer, wrongType)
+			return
+		}
+		hash := s.hashes[args[1]]
+		if hash == nil {
+			hash = make(map[string]string)
+			s.hashes[args[1]] = hash
+		}
+		for i := 2; i < len(args); i += 2 {
+			hash[args[i]] = args[i+1]
+		}
+		s.mu.Unlock()
+		_ = writeSimpleString(writer, "OK")
+	case "HMGET":
+		if len(args) < 3 {
+			_ = writeError(writer, "ERR wrong number of arguments")
+			return
 		}
+		s.mu.Lock()
+		s.expireLocked(args[1])
+		if _, ok := s.values[args[1]]; ok {
+			s.mu.Unlock()
+			_ = writeError(writer, wrongType)
+			return
+		}
+		hash := s.hashes[args[1]]
+		fields := make([][]byte, len(args)-2)
+		for i, field := range args[2:] {
+			if value, ok := hash[field]; ok {
+				fields[i] = append([]byte{}, value...)
+			}
+		}
+		s.mu.Unlock()
+		_ = writeBulkStrings(writer, fields)
+	case "INCR":
+		if len(args) < 2 {
+			_ = writeError(writer, "ERR wrong number of arguments")
+			return
+		}
+		s.mu.Lock()
+		s.expireLocked(args[1])
+		if _, ok := s.hashes[args[1]]; ok {
+			s.mu.Unlock()
+			_ = writeError(writer, wrongType)
+			return
+		}
+		current, err := strconv.Atoi(s.values[args[1]])
+		if err != nil && s.values[args[1]] != "" {
+			s.mu.Unlock()
+			_ = writeError(writer, "ERR value is not an integer")
+			return
+		}
+		current++
+		s.values[args[1]] = strconv.Itoa(current)
+		s.mu.Unlock()
+		_ = writeInteger(writer, current)
+	case "EVAL":
+		var reply []int64
+		var err error
+		if len(args) > 1 && strings.Contains(args[1], "ZREMRANGEBYSCORE") {
+			reply, err = s.evalSlidingWindow(args)
+		} else {
+			reply, err = s.evalTokenBucket(args)
+		}
+		if err != nil {
+			_ = writeError(writer, err.Error())
+			return
+		}
+		_ = writeIntegers(writer, reply)
+	default:
+		_ = writeError(writer, "unknown command")
+	}
+}
+
+// expireLocked removes key if its TTL has passed. Keys expire lazily, on
+// the next command that touches them.
+func (s *Server) expireLocked(key string) {
+	if deadline, ok := s.expires[key]; ok && !s.now().Before(deadline) {
+		s.deleteLocked(key)
+	}
+}
+
+func (s *Server) existsLocked(key string) bool {
+	if _, ok := s.values[key]; ok {
+		return true
 	}
+	_, ok := s.hashes[key]
+	return ok
+}
+
+func (s *Server) deleteLocked(key string) {
+	delete(s.values, key)
+	delete(s.hashes, key)
+	delete(s.expires, key)
 }
 
-func (s *Server) evalTokenBucket(args []string) (int, error) {
+// evalTokenBucket mirrors the token bucket script, replying with
+// allowed, remaining tokens, reset (ms), and retry-after (ms).
+func (s *Server) evalTokenBucket(args []string) ([]int64, error) {
 	if len(args) < 7 {
-		return 0, errors.New("invalid eval args")
+		return nil, errors.New("invalid eval args")
 	}
 	numKeys, err := strconv.Atoi(args[2])
 	if err != nil || numKeys < 1 {
-		return 0, errors.New("invalid eval key count")
+		return nil, errors.New("invalid eval key count")
 	}
 	if len(args) < 3+numKeys+4 {
-		return 0, errors.New("invalid eval args")
+		return nil, errors.New("invalid eval args")
 	}
 	key := args[3]
 	rate, err := strconv.ParseFloat(args[3+numKeys], 64)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	burst, err := strconv.ParseFloat(args[4+numKeys], 64)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	nowMS, err := strconv.ParseInt(args[5+numKeys], 10, 64)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	_, _ = strconv.ParseInt(args[6+numKeys], 10, 64)
 
@@ -163,11 +446,63 @@ func (s *Server) evalTokenBucket(args []string) (int, error) {
 	state.tokens = math.Min(burst, state.tokens+(float64(delta)*rate/1000))
 	state.lastMS = nowMS
 
-	if state.tokens < 1 {
-		return 0, nil
+	var allowed, retry, reset int64
+	if state.tokens >= 1 {
+		state.tokens -= 1
+		allowed = 1
+	} else if rate > 0 {
+		retry = int64(math.Ceil((1 - state.tokens) * 1000 / rate))
+	}
+	if rate > 0 {
+		reset = int64(math.Ceil((burst - state.tokens) * 1000 / rate))
 	}
-	state.tokens -= 1
-	return 1, nil
+	return []int64{allowed, int64(math.Floor(state.tokens)), reset, retry}, nil
+}
+
+// evalSlidingWindow mirrors the sliding window script, replying with
+// allowed, remaining, reset (ms), and retry-after (ms).
+func (s *Server) evalSlidingWindow(args []string) ([]int64, error) {
+	if len(args) < 8 {
+		return nil, errors.New("invalid eval args")
+	}
+	key := args[3]
+	limit, err := strconv.ParseFloat(args[4], 64)
+	if err != nil {
+		return nil, err
+	}
+	window, err := strconv.ParseInt(args[5], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	nowMS, err := strconv.ParseInt(args[6], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hits := s.windows[key]
+	kept := hits[:0]
+	for _, hit := range hits {
+		if hit > nowMS-window {
+			kept = append(kept, hit)
+		}
+	}
+
+	var allowed, retry, reset int64
+	if len(kept) < int(limit) {
+		kept = append(kept, nowMS)
+		allowed = 1
+	} else if len(kept) > 0 {
+		retry = kept[0] + window - nowMS
+	}
+	if len(kept) > 0 {
+		reset = kept[len(kept)-1] + window - nowMS
+	}
+	s.windows[key] = kept
+
+	return []int64{allowed, int64(limit) - int64(len(kept)), reset, retry}, nil
 }
 
 func readRESPArray(reader *bufio.Reader) ([]string, error) {
@@ -240,6 +575,42 @@ func writeBulkString(writer *bufio.Writer, payload []byte) error {
 	return writer.Flush()
 }
 
+func writeBulkStrings(writer *bufio.Writer, values [][]byte) error {
+	if _, err := writer.WriteString("*" + strconv.Itoa(len(values)) + "\r\n"); err != nil {
+		return err
+	}
+	for _, value := range values {
+		if value == nil {
+			if _, err := writer.WriteString("$-1\r\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := writer.WriteString("$" + strconv.Itoa(len(value)) + "\r\n"); err != nil {
+			return err
+		}
+		if _, err := writer.Write(value); err != nil {
+			return err
+		}
+		if _, err := writer.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func writeIntegers(writer *bufio.Writer, values []int64) error {
+	if _, err := writer.WriteString("*" + strconv.Itoa(len(values)) + "\r\n"); err != nil {
+		return err
+	}
+	for _, value := range values {
+		if _, err := writer.WriteString(":" + strconv.FormatInt(value, 10) + "\r\n"); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
 func writeInteger(writer *bufio.Writer, value int) error {
 	if _, err := writer.WriteString(":" + strconv.Itoa(value) + "\r\n"); err != nil {
 		return err
@@ -0,0 +1,103 @@
+package redistest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo/redis"
+)
+
+func TestExpiryFollowsClock(t *testing.T) {
+	clock := NewClock()
+	addr, shutdown := StartWithOptions(t, Options{Now: clock.Now})
+	defer shutdown()
+	client := redis.New(redis.Options{Address: addr})
+	defer client.Close()
+
+	mustDo(t, client, "SET", "session", "data", "PX", "1500")
+	mustDo(t, client, "SET", "plain", "value")
+	if ttl := mustDo(t, client, "TTL", "session"); ttl != int64(2) {
+		t.Fatalf("expected ttl 2, got %v", ttl)
+	}
+	if ttl := mustDo(t, client, "TTL", "plain"); ttl != int64(-1) {
+		t.Fatalf("expected ttl -1 without expiry, got %v", ttl)
+	}
+
+	clock.Advance(time.Second)
+	if exists := mustDo(t, client, "EXISTS", "session", "plain", "missing"); exists != int64(2) {
+		t.Fatalf("expected 2 existing keys, got %v", exists)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if _, err := client.Do("GET", "session"); !errors.Is(err, redis.ErrNil) {
+		t.Fatalf("expected expired key, got %v", err)
+	}
+	if ttl := mustDo(t, client, "TTL", "session"); ttl != int64(-2) {
+		t.Fatalf("expected ttl -2 for missing key, got %v", ttl)
+	}
+
+	if set := mustDo(t, client, "EXPIRE", "plain", "10"); set != int64(1) {
+		t.Fatalf("expected expire to apply, got %v", set)
+	}
+	if set := mustDo(t, client, "PEXPIRE", "missing", "10"); set != int64(0) {
+		t.Fatalf("expected expire on missing key to report 0, got %v", set)
+	}
+	clock.Advance(10 * time.Second)
+	if exists := mustDo(t, client, "EXISTS", "plain"); exists != int64(0) {
+		t.Fatalf("expected key to expire, got %v", exists)
+	}
+}
+
+func TestIncrKeepsTTL(t *testing.T) {
+	clock := NewClock()
+	addr, shutdown := StartWithOptions(t, Options{Now: clock.Now})
+	defer shutdown()
+	client := redis.New(redis.Options{Address: addr})
+	defer client.Close()
+
+	mustDo(t, client, "INCR", "hits")
+	mustDo(t, client, "EXPIRE", "hits", "60")
+	if count := mustDo(t, client, "INCR", "hits"); count != int64(2) {
+		t.Fatalf("expected 2, got %v", count)
+	}
+	if ttl := mustDo(t, client, "TTL", "hits"); ttl != int64(60) {
+		t.Fatalf("expected INCR to keep the ttl, got %v", ttl)
+	}
+	clock.Advance(time.Minute)
+	if count := mustDo(t, client, "INCR", "hits"); count != int64(1) {
+		t.Fatalf("expected counter to restart after expiry, got %v", count)
+	}
+}
+
+func TestHashes(t *testing.T) {
+	addr, shutdown := Start(t)
+	defer shutdown()
+	client := redis.New(redis.Options{Address: addr})
+	defer client.Close()
+
+	mustDo(t, client, "HMSET", "user:1", "name", "Ada", "role", "")
+	values, ok := mustDo(t, client, "HMGET", "user:1", "name", "role", "email").([]any)
+	if !ok || len(values) != 3 {
+		t.Fatalf("unexpected HMGET reply: %v", values)
+	}
+	if string(values[0].([]byte)) != "Ada" || string(values[1].([]byte)) != "" || values[2] != nil {
+		t.Fatalf("unexpected HMGET values: %v", values)
+	}
+
+	if _, err := client.Do("GET", "user:1"); err == nil {
+		t.Fatalf("expected WRONGTYPE for GET on a hash")
+	}
+	if removed := mustDo(t, client, "DEL", "user:1"); removed != int64(1) {
+		t.Fatalf("expected hash to be deleted, got %v", removed)
+	}
+}
+
+func mustDo(t *testing.T, client *redis.Client, args ...string) any {
+	t.Helper()
+	value, err := client.Do(args...)
+	if err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package bebo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+// JSONCached responds with JSON carrying an ETag. An empty etag is derived
+// from a hash of the encoded body; an unquoted one is quoted. When a GET or
+// HEAD request's If-None-Match matches, it responds 304 with no body. Non-2xx
+// statuses are sent as plain JSON.
+func (c *Context) JSONCached(status int, payload any, etag string) error {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(c.jsonPayload(payload)); err != nil {
+		return apperr.Internal("json encode failed", err)
+	}
+
+	w := c.ResponseWriter
+	if status >= http.StatusOK && status < http.StatusMultipleChoices {
+		if etag == "" {
+			sum := sha256.Sum256(body.Bytes())
+			etag = hex.EncodeToString(sum[:16])
+		}
+		etag = quoteETag(etag)
+		w.Header().Set("ETag", etag)
+
+		method := c.Request.Method
+		if (method == http.MethodGet || method == http.MethodHead) && matchETag(c.Request.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+// quoteETag wraps a bare tag in quotes, leaving quoted and weak tags as is.
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
@@ -0,0 +1,86 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newJSONCachedApp() *App {
+	app := New()
+	app.GET("/items", func(ctx *Context) error {
+		return ctx.JSONCached(http.StatusOK, map[string]any{"items": []string{"a", "b"}}, "")
+	})
+	app.GET("/versioned", func(ctx *Context) error {
+		return ctx.JSONCached(http.StatusOK, map[string]int{"version": 7}, "v7")
+	})
+	return app
+}
+
+func TestJSONCachedNonMatching(t *testing.T) {
+	app := newJSONCachedApp()
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); body != "{\"items\":[\"a\",\"b\"]}\n" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	etag := rec.Header().Get("ETag")
+	if len(etag) < 3 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		t.Fatalf("expected quoted etag, got %q", etag)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	// The derived tag is stable across requests for the same payload.
+	again := httptest.NewRecorder()
+	app.ServeHTTP(again, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if again.Header().Get("ETag") != etag {
+		t.Fatalf("expected stable etag, got %q and %q", etag, again.Header().Get("ETag"))
+	}
+}
+
+func TestJSONCachedMatching(t *testing.T) {
+	app := newJSONCachedApp()
+
+	first := httptest.NewRecorder()
+	app.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/items", nil))
+	etag := first.Header().Get("ETag")
+
+	for _, header := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("If-None-Match", header)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotModified {
+			t.Fatalf("If-None-Match %q: expected 304, got %d", header, rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Fatalf("If-None-Match %q: expected empty body, got %q", header, rec.Body.String())
+		}
+		if rec.Header().Get("ETag") != etag {
+			t.Fatalf("If-None-Match %q: expected etag on 304", header)
+		}
+	}
+}
+
+func TestJSONCachedExplicitETag(t *testing.T) {
+	app := newJSONCachedApp()
+
+	req := httptest.NewRequest(http.MethodGet, "/versioned", nil)
+	req.Header.Set("If-None-Match", `"v7"`)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotModified || rec.Header().Get("ETag") != `"v7"` {
+		t.Fatalf("expected 304 with quoted etag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
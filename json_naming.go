@@ -0,0 +1,283 @@
+package bebo
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// JSONNaming maps a Go field name to a JSON key. It applies only to fields
+// without a name in their `json` tag.
+type JSONNaming func(field string) string
+
+var (
+	// JSONSnakeCase names fields like "user_id" for UserID.
+	JSONSnakeCase JSONNaming = snakeCase
+	// JSONCamelCase names fields like "userID" for UserID.
+	JSONCamelCase JSONNaming = camelCase
+)
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// WithJSONNaming renames untagged struct fields in ctx.JSON and
+// ctx.JSONCached responses. Explicit `json` tag names are kept, and types
+// implementing json.Marshaler or encoding.TextMarshaler encode themselves.
+func WithJSONNaming(naming JSONNaming) Option {
+	return func(app *App) {
+		app.jsonNaming = naming
+	}
+}
+
+// jsonPayload applies the app's naming strategy to payload.
+func (c *Context) jsonPayload(payload any) any {
+	if c.app == nil || c.app.jsonNaming == nil || payload == nil {
+		return payload
+	}
+	return renameJSON(reflect.ValueOf(payload), c.app.jsonNaming)
+}
+
+// jsonObject is a struct converted to ordered key/value pairs.
+type jsonObject struct {
+	keys   []string
+	values []any
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		encodedValue, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func renameJSON(value reflect.Value, naming JSONNaming) any {
+	if !value.IsValid() {
+		return nil
+	}
+	if implementsMarshaler(value.Type()) {
+		return value.Interface()
+	}
+	if value.CanAddr() && implementsMarshaler(reflect.PointerTo(value.Type())) {
+		return value.Addr().Interface()
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return renameJSON(value.Elem(), naming)
+	case reflect.Struct:
+		object := &jsonObject{}
+		appendJSONFields(object, value, naming)
+		return object
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		out := make(map[string]any, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key, ok := jsonMapKey(iter.Key())
+			if !ok {
+				return value.Interface()
+			}
+			out[key] = renameJSON(iter.Value(), naming)
+		}
+		return out
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		out := make([]any, value.Len())
+		for i := range out {
+			out[i] = renameJSON(value.Index(i), naming)
+		}
+		return out
+	}
+	return value.Interface()
+}
+
+func appendJSONFields(object *jsonObject, value reflect.Value, naming JSONNaming) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fieldValue := value.Field(i)
+
+		if field.Anonymous && name == "" {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && !implementsMarshaler(embedded.Type()) {
+				appendJSONFields(object, embedded, naming)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if hasJSONOption(options, "omitempty") && isEmptyJSONValue(fieldValue) {
+			continue
+		}
+		if name == "" {
+			name = naming(field.Name)
+		}
+
+		object.keys = append(object.keys, name)
+		if hasJSONOption(options, "string") && isQuotableJSONKind(fieldValue.Kind()) {
+			// Like encoding/json, ",string" wraps the encoded scalar in a string.
+			encoded, err := json.Marshal(fieldValue.Interface())
+			if err == nil {
+				object.values = append(object.values, string(encoded))
+				continue
+			}
+		}
+		object.values = append(object.values, renameJSON(fieldValue, naming))
+	}
+}
+
+// jsonMapKey formats a map key the way encoding/json does.
+func jsonMapKey(key reflect.Value) (string, bool) {
+	if key.Kind() == reflect.String {
+		return key.String(), true
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err == nil
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), true
+	}
+	return "", false
+}
+
+func implementsMarshaler(valueType reflect.Type) bool {
+	return valueType.Implements(jsonMarshalerType) || valueType.Implements(textMarshalerType)
+}
+
+func hasJSONOption(options, option string) bool {
+	for options != "" {
+		var current string
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+	return false
+}
+
+func isQuotableJSONKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isEmptyJSONValue mirrors encoding/json's omitempty rules.
+func isEmptyJSONValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return value.IsZero()
+	}
+	return false
+}
+
+func snakeCase(name string) string {
+	return joinWords(splitWords(name), "_", false)
+}
+
+func camelCase(name string) string {
+	return joinWords(splitWords(name), "", true)
+}
+
+// splitWords splits a Go identifier at case changes, keeping initialisms
+// together: "HTTPServerID" becomes "HTTP", "Server", "ID".
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, current := runes[i-1], runes[i]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		switch {
+		case current == '_':
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case unicode.IsUpper(current) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			words = append(words, string(runes[start:i]))
+			start = i
+		case unicode.IsUpper(current) && unicode.IsUpper(prev) && nextLower:
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func joinWords(words []string, separator string, camel bool) string {
+	for i, word := range words {
+		switch {
+		case !camel || i == 0:
+			words[i] = strings.ToLower(word)
+		case strings.ToUpper(word) != word:
+			runes := []rune(strings.ToLower(word))
+			runes[0] = unicode.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+	}
+	return strings.Join(words, separator)
+}
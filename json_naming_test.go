@@ -0,0 +1,107 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type namingAudit struct {
+	CreatedAt time.Time
+	UpdatedBy string
+}
+
+type namingUser struct {
+	namingAudit
+	UserID     int64
+	HTTPStatus int
+	FullName   string `json:"name"`
+	Nickname   string `json:",omitempty"`
+	Secret     string `json:"-"`
+	Count      int    `json:"count,string"`
+	Tags       []namingTag
+	Meta       map[string]namingTag
+	Avatar     *namingTag
+	Raw        []byte
+}
+
+type namingTag struct {
+	TagName string
+}
+
+func TestJSONNamingSnakeCase(t *testing.T) {
+	app := New(WithJSONNaming(JSONSnakeCase))
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	app.GET("/user", func(ctx *Context) error {
+		return ctx.JSON(http.StatusOK, namingUser{
+			namingAudit: namingAudit{CreatedAt: created, UpdatedBy: "admin"},
+			UserID:      7,
+			HTTPStatus:  200,
+			FullName:    "Ada",
+			Secret:      "hidden",
+			Count:       3,
+			Tags:        []namingTag{{TagName: "go"}},
+			Meta:        map[string]namingTag{"MainTag": {TagName: "web"}},
+			Raw:         []byte("hi"),
+		})
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/user", nil))
+
+	want := `{"created_at":"2024-01-02T03:04:05Z","updated_by":"admin","user_id":7,"http_status":200,"name":"Ada","count":"3",` +
+		`"tags":[{"tag_name":"go"}],"meta":{"MainTag":{"tag_name":"web"}},"avatar":null,"raw":"aGk="}`
+	if got := strings.TrimSpace(rec.Body.String()); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestJSONNamingCamelCase(t *testing.T) {
+	app := New(WithJSONNaming(JSONCamelCase))
+	app.GET("/tag", func(ctx *Context) error {
+		return ctx.JSONCached(http.StatusOK, &namingUser{UserID: 1, FullName: "Ada"}, "v1")
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tag", nil))
+
+	body := rec.Body.String()
+	for _, key := range []string{`"userID":1`, `"httpStatus":0`, `"name":"Ada"`, `"createdAt":`} {
+		if !strings.Contains(body, key) {
+			t.Fatalf("expected %s in %s", key, body)
+		}
+	}
+}
+
+func TestJSONNamingDefaultKeepsGoNames(t *testing.T) {
+	app := New()
+	app.GET("/tag", func(ctx *Context) error {
+		return ctx.JSON(http.StatusOK, namingTag{TagName: "go"})
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tag", nil))
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"TagName":"go"}` {
+		t.Fatalf("expected Go field names, got %s", got)
+	}
+}
+
+func TestJSONNamingWords(t *testing.T) {
+	cases := map[string][2]string{
+		"UserID":       {"user_id", "userID"},
+		"HTTPServerID": {"http_server_id", "httpServerID"},
+		"Name":         {"name", "name"},
+		"OAuth2Token":  {"o_auth2_token", "oAuth2Token"},
+		"ID":           {"id", "id"},
+	}
+	for name, want := range cases {
+		if got := snakeCase(name); got != want[0] {
+			t.Errorf("snakeCase(%q) = %q, want %q", name, got, want[0])
+		}
+		if got := camelCase(name); got != want[1] {
+			t.Errorf("camelCase(%q) = %q, want %q", name, got, want[1])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package bebo
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// MinKeyLength is the minimum signing key length accepted by CheckKey.
+const MinKeyLength = 32
+
+// ErrWeakKey indicates a signing key that is too short or a known default.
+var ErrWeakKey = errors.New("weak key")
+
+// weakKeys lists placeholder values that must never be used as secrets.
+var weakKeys = []string{
+	"dev-session-key-change-me",
+	"change-me",
+	"changeme",
+	"secret",
+	"password",
+	"default",
+	"test",
+	"dev",
+}
+
+// GenerateKey returns size random bytes and their base64 (URL-safe, unpadded)
+// encoding, suitable for session and CSRF signing keys. A size below
+// MinKeyLength uses MinKeyLength.
+func GenerateKey(size int) ([]byte, string) {
+	if size < MinKeyLength {
+		size = MinKeyLength
+	}
+	key := make([]byte, size)
+	_, _ = rand.Read(key)
+	return key, base64.RawURLEncoding.EncodeToString(key)
+}
+
+// CheckKey reports an ErrWeakKey error when key is shorter than MinKeyLength,
+// matches a known placeholder, or repeats a single byte.
+func CheckKey(key []byte) error {
+	if isPlaceholderKey(key) {
+		return fmt.Errorf("%w: known placeholder value", ErrWeakKey)
+	}
+	if len(key) < MinKeyLength {
+		return fmt.Errorf("%w: %d bytes, need at least %d", ErrWeakKey, len(key), MinKeyLength)
+	}
+	if strings.Count(string(key), string(key[:1])) == len(key) {
+		return fmt.Errorf("%w: repeated byte", ErrWeakKey)
+	}
+	return nil
+}
+
+func isPlaceholderKey(key []byte) bool {
+	value := strings.ToLower(strings.TrimSpace(string(key)))
+	if strings.Contains(value, "change-me") || strings.Contains(value, "changeme") {
+		return true
+	}
+	for _, weak := range weakKeys {
+		if value == weak {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyCheckOptions configures CheckKeys.
+// In Production weak keys are returned as an error; otherwise they are logged
+// as warnings to Logger (slog.Default when nil).
+type KeyCheckOptions struct {
+	Production bool
+	Logger     *slog.Logger
+}
+
+// CheckKeys validates named signing keys at startup, e.g.
+// map[string][]byte{"session": sessionKey, "csrf": csrfKey}.
+func CheckKeys(keys map[string][]byte, options KeyCheckOptions) error {
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		err := CheckKey(keys[name])
+		if err == nil {
+			continue
+		}
+		if options.Production {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		logger.Warn("weak signing key", slog.String("key", name), slog.String("error", err.Error()))
+	}
+	return errors.Join(errs...)
+}
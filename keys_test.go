@@ -0,0 +1,65 @@
+package bebo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestGenerateKey(t *testing.T) {
+	key, encoded := GenerateKey(48)
+	if len(key) != 48 {
+		t.Fatalf("expected 48 bytes, got %d", len(key))
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || !bytes.Equal(decoded, key) {
+		t.Fatalf("expected encoded key to round trip")
+	}
+	if short, _ := GenerateKey(8); len(short) != MinKeyLength {
+		t.Fatalf("expected minimum length %d, got %d", MinKeyLength, len(short))
+	}
+	other, _ := GenerateKey(48)
+	if bytes.Equal(key, other) {
+		t.Fatalf("expected random keys to differ")
+	}
+	if err := CheckKey(key); err != nil {
+		t.Fatalf("expected generated key to pass: %v", err)
+	}
+}
+
+func TestCheckKeyDetectsWeakKeys(t *testing.T) {
+	cases := map[string][]byte{
+		"default":  []byte("dev-session-key-change-me"),
+		"padded":   []byte("please-change-me-before-deploying-this-app"),
+		"short":    []byte("too-short"),
+		"repeated": bytes.Repeat([]byte("a"), 40),
+		"empty":    nil,
+	}
+	for name, key := range cases {
+		if err := CheckKey(key); !errors.Is(err, ErrWeakKey) {
+			t.Fatalf("%s: expected ErrWeakKey, got %v", name, err)
+		}
+	}
+}
+
+func TestCheckKeysWarnsOrErrors(t *testing.T) {
+	strong, _ := GenerateKey(32)
+	keys := map[string][]byte{"session": []byte("dev-session-key-change-me"), "csrf": strong}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	if err := CheckKeys(keys, KeyCheckOptions{Logger: logger}); err != nil {
+		t.Fatalf("expected warning only, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "key=session") {
+		t.Fatalf("expected warning for session key, got %q", logs.String())
+	}
+
+	err := CheckKeys(keys, KeyCheckOptions{Production: true})
+	if !errors.Is(err, ErrWeakKey) || !strings.Contains(err.Error(), "session") {
+		t.Fatalf("expected production error for session key, got %v", err)
+	}
+}
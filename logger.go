@@ -16,22 +16,30 @@ type Logger struct {
 
 // Info logs an info message.
 func (l Logger) Info(msg string, attrs ...slog.Attr) {
-	l.logger.Info(msg, l.appendContextFields(attrs)...)
+	l.base().Info(msg, l.appendContextFields(attrs)...)
 }
 
 // Warn logs a warning message.
 func (l Logger) Warn(msg string, attrs ...slog.Attr) {
-	l.logger.Warn(msg, l.appendContextFields(attrs)...)
+	l.base().Warn(msg, l.appendContextFields(attrs)...)
 }
 
 // Error logs an error message.
 func (l Logger) Error(msg string, attrs ...slog.Attr) {
-	l.logger.Error(msg, l.appendContextFields(attrs)...)
+	l.base().Error(msg, l.appendContextFields(attrs)...)
 }
 
 // Debug logs a debug message.
 func (l Logger) Debug(msg string, attrs ...slog.Attr) {
-	l.logger.Debug(msg, l.appendContextFields(attrs)...)
+	l.base().Debug(msg, l.appendContextFields(attrs)...)
+}
+
+// base returns the wrapped logger, falling back to slog.Default for a zero Logger.
+func (l Logger) base() *slog.Logger {
+	if l.logger == nil {
+		return slog.Default()
+	}
+	return l.logger
 }
 
 func (l Logger) appendContextFields(attrs []slog.Attr) []any {
@@ -63,6 +71,7 @@ func (l Logger) appendContextFields(attrs []slog.Attr) []any {
 }
 
 // LoggerFromContext builds a logger that includes request metadata.
+// A nil logger falls back to slog.Default.
 func LoggerFromContext(ctx context.Context, logger *slog.Logger) Logger {
 	metadata := RequestMetadataFromContext(ctx)
 	traceID, spanID, _ := TraceIDs(metadata.Traceparent)
@@ -70,6 +79,7 @@ func LoggerFromContext(ctx context.Context, logger *slog.Logger) Logger {
 }
 
 // LoggerFromRequest builds a logger using request metadata.
+// A nil logger falls back to slog.Default.
 func LoggerFromRequest(r *http.Request, logger *slog.Logger) Logger {
 	metadata := RequestMetadataFromRequest(r)
 	traceID, spanID, _ := TraceIDs(metadata.Traceparent)
@@ -0,0 +1,35 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithNilLoggerUsesDefault(t *testing.T) {
+	app := New(WithLogger(nil))
+	if app.logger == nil {
+		t.Fatalf("expected default logger")
+	}
+
+	app.GET("/", func(ctx *Context) error {
+		ctx.Logger().Info("handled")
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestContextLoggerWithoutApp(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, nil)
+	ctx.Logger().Info("no app")
+
+	ctx = &Context{}
+	ctx.Logger().Warn("zero context")
+
+	var logger Logger
+	logger.Error("zero logger")
+}
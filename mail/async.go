@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"context"
+
+	"github.com/devmarvs/bebo/tasks"
+)
+
+// AsyncMailer hands messages to a task runner so requests do not wait on
+// delivery. Failed sends follow the runner's retry and dead-letter policy.
+type AsyncMailer struct {
+	runner *tasks.Runner
+	mailer Mailer
+}
+
+// Async wraps mailer so Send enqueues a "mail.send" job on runner.
+func Async(runner *tasks.Runner, mailer Mailer) *AsyncMailer {
+	return &AsyncMailer{runner: runner, mailer: mailer}
+}
+
+// Send validates msg and enqueues it. The job runs with the runner context,
+// not ctx, so delivery outlives the request that triggered it.
+func (a *AsyncMailer) Send(ctx context.Context, msg Message) error {
+	if _, err := msg.Recipients(); err != nil {
+		return err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return a.runner.EnqueueContext(ctx, tasks.Job{
+		Name: "mail.send",
+		Handler: func(jobCtx context.Context) error {
+			return a.mailer.Send(jobCtx, msg)
+		},
+	})
+}
@@ -0,0 +1,189 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoRecipients indicates a message has no To, Cc or Bcc addresses.
+var ErrNoRecipients = errors.New("mail message has no recipients")
+
+// ErrNoSender indicates a message has no From address.
+var ErrNoSender = errors.New("mail message has no sender")
+
+// Mailer sends email messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Message is an email. When both Text and HTML are set the message is sent
+// as multipart/alternative.
+type Message struct {
+	From    string
+	To      []string
+	Cc      []string
+	Bcc     []string
+	ReplyTo string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string
+}
+
+// Recipients returns every envelope recipient address, Bcc included.
+func (m Message) Recipients() ([]string, error) {
+	var out []string
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		for _, value := range list {
+			addr, err := mail.ParseAddress(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recipient %q: %w", value, err)
+			}
+			out = append(out, addr.Address)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrNoRecipients
+	}
+	return out, nil
+}
+
+// Build renders msg as an RFC 5322 message with MIME bodies. Bcc addresses
+// are never written to the headers.
+func Build(msg Message) ([]byte, error) {
+	if msg.From == "" {
+		return nil, ErrNoSender
+	}
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", msg.From, err)
+	}
+	if _, err := msg.Recipients(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	header := func(name, value string) error {
+		if strings.ContainsAny(name, "\r\n:") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header %q", name)
+		}
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+		return nil
+	}
+
+	if err := header("From", from.String()); err != nil {
+		return nil, err
+	}
+	if len(msg.To) > 0 {
+		_ = header("To", formatAddresses(msg.To))
+	}
+	if len(msg.Cc) > 0 {
+		_ = header("Cc", formatAddresses(msg.Cc))
+	}
+	if msg.ReplyTo != "" {
+		replyTo, err := mail.ParseAddress(msg.ReplyTo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reply-to %q: %w", msg.ReplyTo, err)
+		}
+		if err := header("Reply-To", replyTo.String()); err != nil {
+			return nil, err
+		}
+	}
+	if err := header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject)); err != nil {
+		return nil, err
+	}
+	_ = header("Date", time.Now().Format(time.RFC1123Z))
+	_ = header("Message-ID", messageID(from.Address))
+	_ = header("MIME-Version", "1.0")
+
+	names := make([]string, 0, len(msg.Headers))
+	for name := range msg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := header(textproto.CanonicalMIMEHeaderKey(name), msg.Headers[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case msg.Text != "" && msg.HTML != "":
+		writer := multipart.NewWriter(&buf)
+		_ = header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": writer.Boundary()}))
+		buf.WriteString("\r\n")
+		for _, body := range []struct{ contentType, content string }{
+			{"text/plain; charset=utf-8", msg.Text},
+			{"text/html; charset=utf-8", msg.HTML},
+		} {
+			part, err := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {body.contentType},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := writeQuotedPrintable(part, body.content); err != nil {
+				return nil, err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		contentType := "text/plain; charset=utf-8"
+		content := msg.Text
+		if msg.HTML != "" {
+			contentType = "text/html; charset=utf-8"
+			content = msg.HTML
+		}
+		_ = header("Content-Type", contentType)
+		_ = header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, content); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func formatAddresses(list []string) string {
+	formatted := make([]string, 0, len(list))
+	for _, value := range list {
+		if addr, err := mail.ParseAddress(value); err == nil {
+			formatted = append(formatted, addr.String())
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qp, content); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">"
+}
@@ -0,0 +1,175 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo/tasks"
+)
+
+func TestBuildMultipartAlternative(t *testing.T) {
+	data, err := Build(Message{
+		From:    "Bebo <no-reply@example.com>",
+		To:      []string{"kim@example.com", "Lee <lee@example.com>"},
+		Bcc:     []string{"audit@example.com"},
+		Subject: "Welcome, café",
+		Text:    "Hello " + strings.Repeat("x", 100),
+		HTML:    "<p>Hello</p>",
+		Headers: map[string]string{"x-campaign": "signup"},
+	})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	if got := msg.Header.Get("To"); got != `<kim@example.com>, "Lee" <lee@example.com>` {
+		t.Fatalf("unexpected To: %q", got)
+	}
+	if msg.Header.Get("Bcc") != "" || bytes.Contains(data, []byte("audit@example.com")) {
+		t.Fatal("expected Bcc to be omitted from headers")
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Welcome, café" {
+		t.Fatalf("unexpected subject: %q (%v)", subject, err)
+	}
+	if msg.Header.Get("X-Campaign") != "signup" || msg.Header.Get("MIME-Version") != "1.0" {
+		t.Fatalf("missing headers: %v", msg.Header)
+	}
+	if msg.Header.Get("Date") == "" || !strings.HasSuffix(msg.Header.Get("Message-ID"), "@example.com>") {
+		t.Fatalf("expected Date and Message-ID headers: %v", msg.Header)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("unexpected content type: %s (%v)", mediaType, err)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		// NextPart decodes quoted-printable transparently.
+		body, _ := io.ReadAll(part)
+		types = append(types, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+	if len(bodies) != 2 || types[0] != "text/plain; charset=utf-8" || types[1] != "text/html; charset=utf-8" {
+		t.Fatalf("unexpected parts: %v", types)
+	}
+	if bodies[0] != "Hello "+strings.Repeat("x", 100) || bodies[1] != "<p>Hello</p>" {
+		t.Fatalf("unexpected bodies: %q", bodies)
+	}
+}
+
+func TestBuildSinglePart(t *testing.T) {
+	data, err := Build(Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "Hi", Text: "line one=\r\nline two"})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	if msg.Header.Get("Content-Type") != "text/plain; charset=utf-8" || msg.Header.Get("Content-Transfer-Encoding") != "quoted-printable" {
+		t.Fatalf("unexpected headers: %v", msg.Header)
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if string(body) != "line one=\r\nline two" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+}
+
+func TestBuildRejectsInvalidMessages(t *testing.T) {
+	if _, err := Build(Message{To: []string{"b@example.com"}}); !errors.Is(err, ErrNoSender) {
+		t.Fatalf("expected ErrNoSender, got %v", err)
+	}
+	if _, err := Build(Message{From: "a@example.com"}); !errors.Is(err, ErrNoRecipients) {
+		t.Fatalf("expected ErrNoRecipients, got %v", err)
+	}
+	if _, err := Build(Message{From: "a@example.com", To: []string{"not an address"}}); err == nil {
+		t.Fatal("expected invalid recipient error")
+	}
+	if _, err := Build(Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "x\r\nBcc: evil@example.com"}); err != nil {
+		t.Fatalf("subject should be encoded, got %v", err)
+	}
+	if _, err := Build(Message{From: "a@example.com", To: []string{"b@example.com"}, Headers: map[string]string{"X-Test": "a\r\nBcc: evil@example.com"}}); err == nil {
+		t.Fatal("expected header injection to be rejected")
+	}
+}
+
+func TestMemoryMailerRecords(t *testing.T) {
+	mailer := NewMemory()
+	if err := mailer.Send(context.Background(), Message{From: "a@example.com"}); !errors.Is(err, ErrNoRecipients) {
+		t.Fatalf("expected ErrNoRecipients, got %v", err)
+	}
+	msg := Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "Confirm"}
+	if err := mailer.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	last, ok := mailer.Last()
+	if !ok || last.Subject != "Confirm" || len(mailer.Messages()) != 1 {
+		t.Fatalf("unexpected recorded messages: %+v", mailer.Messages())
+	}
+	mailer.Reset()
+	if _, ok := mailer.Last(); ok {
+		t.Fatal("expected no messages after reset")
+	}
+}
+
+func TestAsyncMailerSendsViaRunner(t *testing.T) {
+	runner := tasks.New(tasks.Options{QueueSize: 1})
+	runner.Start(context.Background())
+
+	memory := NewMemory()
+	mailer := Async(runner, memory)
+
+	requestCtx, cancel := context.WithCancel(context.Background())
+	if err := mailer.Send(requestCtx, Message{From: "a@example.com", To: []string{"b@example.com"}}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	cancel()
+
+	shutdownCtx, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	if err := runner.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if len(memory.Messages()) != 1 {
+		t.Fatalf("expected message delivered after request ended, got %d", len(memory.Messages()))
+	}
+	if err := mailer.Send(context.Background(), Message{From: "a@example.com", To: []string{"b@example.com"}}); !errors.Is(err, tasks.ErrRunnerClosed) {
+		t.Fatalf("expected ErrRunnerClosed, got %v", err)
+	}
+}
+
+func TestNewSMTPRequiresHostPort(t *testing.T) {
+	if _, err := NewSMTP(SMTPOptions{Addr: "smtp.example.com"}); err == nil {
+		t.Fatal("expected missing port error")
+	}
+	mailer, err := NewSMTP(SMTPOptions{Addr: "smtp.example.com:587"})
+	if err != nil {
+		t.Fatalf("new smtp: %v", err)
+	}
+	if err := mailer.Send(context.Background(), Message{To: []string{"b@example.com"}}); !errors.Is(err, ErrNoSender) {
+		t.Fatalf("expected ErrNoSender before dialing, got %v", err)
+	}
+}
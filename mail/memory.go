@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer records messages instead of sending them. It is meant for
+// tests and local development.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+// NewMemory creates an in-memory mailer.
+func NewMemory() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send validates and records msg.
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	if _, err := msg.Recipients(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Messages returns the recorded messages in send order.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Last returns the most recently recorded message.
+func (m *MemoryMailer) Last() (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		return Message{}, false
+	}
+	return m.sent[len(m.sent)-1], true
+}
+
+// Reset discards recorded messages.
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+}
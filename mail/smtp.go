@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPOptions configures an SMTP mailer.
+type SMTPOptions struct {
+	// Addr is the server host:port, e.g. "smtp.example.com:587".
+	Addr     string
+	Username string
+	Password string
+	// From is used when a message has no From address.
+	From string
+	// ImplicitTLS dials with TLS (port 465) instead of upgrading via STARTTLS.
+	ImplicitTLS bool
+	TLSConfig   *tls.Config
+	Timeout     time.Duration
+}
+
+// SMTPMailer sends messages through an SMTP server. STARTTLS is used when the
+// server offers it, and PLAIN auth when a username is configured.
+type SMTPMailer struct {
+	opts SMTPOptions
+	host string
+}
+
+// NewSMTP creates an SMTP mailer.
+func NewSMTP(options SMTPOptions) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(options.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	return &SMTPMailer{opts: options, host: host}, nil
+}
+
+// Send delivers msg, aborting when ctx is done.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if msg.From == "" {
+		msg.From = m.opts.From
+	}
+	data, err := Build(msg)
+	if err != nil {
+		return err
+	}
+	recipients, err := msg.Recipients()
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: m.opts.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", m.opts.Addr)
+	if err != nil {
+		return err
+	}
+	if m.opts.ImplicitTLS {
+		conn = tls.Client(conn, m.tlsConfig())
+	}
+	_ = conn.SetDeadline(time.Now().Add(m.opts.Timeout))
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := m.deliver(client, from.Address, recipients, data); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.Join(ctxErr, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *SMTPMailer) deliver(client *smtp.Client, from string, recipients []string, data []byte) error {
+	if !m.opts.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(m.tlsConfig()); err != nil {
+				return err
+			}
+		}
+	}
+	if m.opts.Username != "" {
+		auth := smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (m *SMTPMailer) tlsConfig() *tls.Config {
+	if m.opts.TLSConfig != nil {
+		return m.opts.TLSConfig
+	}
+	return &tls.Config{ServerName: m.host, MinVersion: tls.VersionTLS12}
+}
@@ -14,8 +14,27 @@ type Snapshot struct {
 	InFlight int64           `json:"in_flight"`
 	Latency  LatencySnapshot `json:"latency"`
 	Statuses map[int]int64   `json:"statuses"`
+	Custom   []Sample        `json:"custom,omitempty"`
 }
 
+// Sample is a value reported by a Collector.
+type Sample struct {
+	Name   string            `json:"name"`
+	Help   string            `json:"help,omitempty"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// Sample types understood by the Prometheus handler.
+const (
+	CounterType = "counter"
+	GaugeType   = "gauge"
+)
+
+// Collector returns samples gathered when a snapshot is taken.
+type Collector func() []Sample
+
 // LatencySnapshot captures latency statistics.
 type LatencySnapshot struct {
 	Count   int64           `json:"count"`
@@ -41,6 +60,8 @@ type Registry struct {
 	statuses map[int]int64
 	buckets  []time.Duration
 	counts   []int64
+
+	collectors []Collector
 }
 
 // New creates a new registry with default buckets.
@@ -102,8 +123,30 @@ func (r *Registry) End(start time.Time, status int, err error) {
 	}
 }
 
+// AddCollector registers a collector whose samples are included in every snapshot.
+func (r *Registry) AddCollector(collector Collector) {
+	if collector == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collector)
+}
+
 // Snapshot returns a copy of metrics data.
 func (r *Registry) Snapshot() Snapshot {
+	snap := r.snapshot()
+
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+	for _, collector := range collectors {
+		snap.Custom = append(snap.Custom, collector()...)
+	}
+	return snap
+}
+
+func (r *Registry) snapshot() Snapshot {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
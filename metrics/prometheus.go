@@ -2,8 +2,10 @@ package metrics
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
+	"strings"
 )
 
 // PrometheusHandler exposes metrics in Prometheus text format.
@@ -53,5 +55,48 @@ func PrometheusHandler(registry *Registry) http.Handler {
 				fmt.Fprintf(w, "bebo_statuses_total{code=\"%d\"} %d\n", code, snap.Statuses[code])
 			}
 		}
+
+		writeSamples(w, snap.Custom)
 	})
 }
+
+// writeSamples groups collector samples by name so each metric family gets a
+// single HELP/TYPE header.
+func writeSamples(w io.Writer, samples []Sample) {
+	sorted := append([]Sample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	last := ""
+	for _, sample := range sorted {
+		if sample.Name != last {
+			last = sample.Name
+			if sample.Help != "" {
+				fmt.Fprintf(w, "# HELP %s %s\n", sample.Name, sample.Help)
+			}
+			sampleType := sample.Type
+			if sampleType == "" {
+				sampleType = "untyped"
+			}
+			fmt.Fprintf(w, "# TYPE %s %s\n", sample.Name, sampleType)
+		}
+		fmt.Fprintf(w, "%s%s %g\n", sample.Name, formatLabels(sample.Labels), sample.Value)
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s=\"%s\"", key, escape.Replace(labels[key]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
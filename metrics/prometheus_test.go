@@ -24,3 +24,28 @@ func TestPrometheusHandler(t *testing.T) {
 		t.Fatalf("expected latency count")
 	}
 }
+
+func TestPrometheusHandlerCollectors(t *testing.T) {
+	reg := New()
+	reg.AddCollector(func() []Sample {
+		return []Sample{
+			{Name: "app_jobs", Help: "Jobs", Type: GaugeType, Labels: map[string]string{"queue": "b"}, Value: 2},
+			{Name: "app_build_info", Labels: map[string]string{"version": `v1"x`}, Value: 1},
+			{Name: "app_jobs", Help: "Jobs", Type: GaugeType, Labels: map[string]string{"queue": "a"}, Value: 1},
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	if strings.Count(body, "# TYPE app_jobs gauge") != 1 {
+		t.Fatalf("expected one TYPE line per metric family:\n%s", body)
+	}
+	if !strings.Contains(body, "app_jobs{queue=\"b\"} 2\napp_jobs{queue=\"a\"} 1\n") {
+		t.Fatalf("expected grouped samples:\n%s", body)
+	}
+	if !strings.Contains(body, "# TYPE app_build_info untyped\napp_build_info{version=\"v1\\\"x\"} 1\n") {
+		t.Fatalf("expected escaped label value:\n%s", body)
+	}
+}
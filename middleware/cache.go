@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/internal/httpx"
 )
 
 // CacheControl sets a Cache-Control header on responses.
@@ -52,7 +53,7 @@ func ETag(options ETagOptions) bebo.Middleware {
 type etagWriter struct {
 	writer   http.ResponseWriter
 	header   http.Header
-	status   int
+	status   httpx.WriteHeaderOnce
 	buffer   []byte
 	maxSize  int64
 	overflow bool
@@ -68,8 +69,8 @@ func (e *etagWriter) Header() http.Header {
 }
 
 func (e *etagWriter) WriteHeader(status int) {
-	if e.status == 0 {
-		e.status = status
+	if e.status.Record(status) && status < http.StatusOK {
+		e.writer.WriteHeader(status)
 	}
 }
 
@@ -124,10 +125,7 @@ func (e *etagWriter) finalize(r *http.Request, options ETagOptions) {
 	if e.overflow || e.wrote {
 		return
 	}
-	status := e.status
-	if status == 0 {
-		status = http.StatusOK
-	}
+	status := e.status.Status()
 	if status < 200 || status >= 300 {
 		e.flushToUnderlying()
 		return
@@ -161,12 +159,8 @@ func (e *etagWriter) flushToUnderlying() {
 	if e.wrote {
 		return
 	}
-	status := e.status
-	if status == 0 {
-		status = http.StatusOK
-	}
 	copyHeaders(e.writer.Header(), e.header)
-	e.writer.WriteHeader(status)
+	e.writer.WriteHeader(e.status.Status())
 	if len(e.buffer) > 0 {
 		_, _ = e.writer.Write(e.buffer)
 	}
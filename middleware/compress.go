@@ -0,0 +1,336 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/internal/httpx"
+)
+
+// CompressOptions configures response compression.
+// When ContentTypes is set only matching types are compressed; otherwise every
+// type except ExcludedContentTypes is. Entries may use a "type/*" wildcard.
+// Responses smaller than MinSize bytes are sent uncompressed (0 compresses everything).
+type CompressOptions struct {
+	DisableDefaults      bool
+	Level                int
+	MinSize              int
+	ContentTypes         []string
+	ExcludedContentTypes []string
+}
+
+// DefaultExcludedContentTypes lists already-compressed types skipped by default.
+func DefaultExcludedContentTypes() []string {
+	return []string{
+		"image/*",
+		"video/*",
+		"audio/*",
+		"font/woff",
+		"font/woff2",
+		"application/zip",
+		"application/gzip",
+		"application/x-gzip",
+		"application/x-7z-compressed",
+		"application/x-rar-compressed",
+		"application/x-bzip2",
+		"application/zstd",
+	}
+}
+
+type compressConfig struct {
+	level   int
+	minSize int
+	allow   []string
+	exclude []string
+}
+
+// Compress gzip- or deflate-encodes responses based on Accept-Encoding.
+// Responses that already carry a Content-Encoding are left untouched.
+//
+// Register Compress before ETag (app.Use(Compress(...), ETag(...))) so ETags are
+// computed on the uncompressed body. In the reverse order ETag sees the
+// Content-Encoding header set by Compress and skips the response.
+func Compress(options CompressOptions) bebo.Middleware {
+	cfg := normalizeCompress(options)
+	return func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			if ctx.Request.Method == http.MethodHead {
+				return next(ctx)
+			}
+			encoding := negotiateEncoding(ctx.Request.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				return next(ctx)
+			}
+			if ctx.ResponseWriter.Header().Get("Content-Encoding") != "" {
+				return next(ctx)
+			}
+
+			original := ctx.ResponseWriter
+			writer := newCompressWriter(original, cfg, encoding)
+			ctx.ResponseWriter = writer
+
+			err := next(ctx)
+			if err != nil && !writer.wroteHeader {
+				ctx.ResponseWriter = original
+				return err
+			}
+			_ = writer.Close()
+			return err
+		}
+	}
+}
+
+func normalizeCompress(options CompressOptions) compressConfig {
+	cfg := compressConfig{level: options.Level, minSize: options.MinSize}
+	if cfg.level == 0 || cfg.level < gzip.HuffmanOnly || cfg.level > gzip.BestCompression {
+		cfg.level = gzip.DefaultCompression
+	}
+	if cfg.minSize < 0 {
+		cfg.minSize = 0
+	}
+	cfg.allow = normalizeContentTypes(options.ContentTypes)
+	excluded := options.ExcludedContentTypes
+	if len(excluded) == 0 && !options.DisableDefaults {
+		excluded = DefaultExcludedContentTypes()
+	}
+	cfg.exclude = normalizeContentTypes(excluded)
+	return cfg
+}
+
+func normalizeContentTypes(types []string) []string {
+	out := make([]string, 0, len(types))
+	for _, value := range types {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		out = append(out, value)
+	}
+	return out
+}
+
+func (c compressConfig) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+	if len(c.allow) > 0 {
+		return matchContentType(c.allow, mediaType)
+	}
+	return !matchContentType(c.exclude, mediaType)
+}
+
+func matchContentType(patterns []string, mediaType string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/*") {
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+			continue
+		}
+		if pattern == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+type compressEncoder interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
+type compressWriter struct {
+	writer      http.ResponseWriter
+	cfg         compressConfig
+	encoding    string
+	encoder     compressEncoder
+	buffer      []byte
+	status      httpx.WriteHeaderOnce
+	wroteHeader bool
+}
+
+func newCompressWriter(w http.ResponseWriter, cfg compressConfig, encoding string) *compressWriter {
+	return &compressWriter{writer: w, cfg: cfg, encoding: encoding}
+}
+
+func (c *compressWriter) Header() http.Header {
+	return c.writer.Header()
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.wroteHeader || !c.status.Record(status) {
+		return
+	}
+	if status < http.StatusOK {
+		c.writer.WriteHeader(status)
+		return
+	}
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		c.start(false)
+	}
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if c.wroteHeader {
+		if c.encoder != nil {
+			return c.encoder.Write(p)
+		}
+		return c.writer.Write(p)
+	}
+	c.buffer = append(c.buffer, p...)
+	if len(c.buffer) >= c.cfg.minSize {
+		if err := c.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// start decides whether to compress once the content type and size are known,
+// then writes the header and any buffered bytes.
+func (c *compressWriter) start(allowCompress bool) error {
+	c.wroteHeader = true
+	header := c.writer.Header()
+	if header.Get("Content-Type") == "" && len(c.buffer) > 0 {
+		header.Set("Content-Type", http.DetectContentType(c.buffer))
+	}
+	if header.Get("Content-Encoding") == "" && c.cfg.compressible(header.Get("Content-Type")) {
+		// Vary even when this body stays uncompressed (e.g. below MinSize), since
+		// the same resource may be compressed for other requests.
+		header.Add("Vary", "Accept-Encoding")
+		if allowCompress {
+			encoder, err := newCompressEncoder(c.writer, c.encoding, c.cfg.level)
+			if err == nil {
+				c.encoder = encoder
+				header.Set("Content-Encoding", c.encoding)
+				header.Del("Content-Length")
+			}
+		}
+	}
+	c.writer.WriteHeader(c.status.Status())
+
+	if len(c.buffer) == 0 {
+		return nil
+	}
+	buffered := c.buffer
+	c.buffer = nil
+	if c.encoder != nil {
+		_, err := c.encoder.Write(buffered)
+		return err
+	}
+	_, err := c.writer.Write(buffered)
+	return err
+}
+
+func (c *compressWriter) Close() error {
+	if !c.wroteHeader {
+		if !c.status.Written() && len(c.buffer) == 0 {
+			return nil
+		}
+		if err := c.start(len(c.buffer) > 0 && len(c.buffer) >= c.cfg.minSize); err != nil {
+			return err
+		}
+	}
+	if c.encoder == nil {
+		return nil
+	}
+	return c.encoder.Close()
+}
+
+func (c *compressWriter) Flush() {
+	if !c.wroteHeader {
+		_ = c.start(true)
+	}
+	if c.encoder != nil {
+		_ = c.encoder.Flush()
+	}
+	if flusher, ok := c.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (c *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := c.writer.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return hijacker.Hijack()
+}
+
+func (c *compressWriter) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := c.writer.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return pusher.Push(target, opts)
+}
+
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.writer
+}
+
+func newCompressEncoder(w io.Writer, encoding string, level int) (compressEncoder, error) {
+	if encoding == "deflate" {
+		return flate.NewWriter(w, level)
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// negotiateEncoding picks gzip or deflate from Accept-Encoding, honoring q-values.
+// A "*" entry applies only to encodings not listed explicitly, so "gzip;q=0, *"
+// still rejects gzip. Ties prefer gzip; an empty result means the client accepts neither.
+func negotiateEncoding(header string) string {
+	listed := make(map[string]float64, 2)
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, q := parseEncoding(part)
+		switch name {
+		case "*":
+			wildcard = q
+		case "gzip", "deflate":
+			listed[name] = q
+		}
+	}
+
+	best := ""
+	bestQ := 0.0
+	for _, name := range []string{"gzip", "deflate"} {
+		q, ok := listed[name]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best = name
+			bestQ = q
+		}
+	}
+	return best
+}
+
+func parseEncoding(part string) (string, float64) {
+	pieces := strings.Split(part, ";")
+	name := strings.ToLower(strings.TrimSpace(pieces[0]))
+	q := 1.0
+	for _, param := range pieces[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return name, 0
+		}
+		q = parsed
+	}
+	return name, q
+}
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+)
+
+func TestCompressSkipsImages(t *testing.T) {
+	app := bebo.New()
+	app.Use(Compress(CompressOptions{}))
+
+	app.GET("/logo.png", func(ctx *bebo.Context) error {
+		ctx.ResponseWriter.Header().Set("Content-Type", "image/png")
+		ctx.ResponseWriter.WriteHeader(http.StatusOK)
+		_, err := ctx.ResponseWriter.Write([]byte("png-bytes"))
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/logo.png", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected image to skip compression")
+	}
+	if rec.Body.String() != "png-bytes" {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestCompressJSON(t *testing.T) {
+	app := bebo.New()
+	app.Use(Compress(CompressOptions{ContentTypes: []string{"application/json"}}))
+
+	app.GET("/data", func(ctx *bebo.Context) error {
+		return ctx.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	})
+	app.GET("/text", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "plain")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/data", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected json to be compressed")
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	if !strings.Contains(string(body), `"status":"ok"`) {
+		t.Fatalf("unexpected body: %s", string(body))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/text", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected text outside allowlist to skip compression")
+	}
+}
+
+func TestCompressInvalidLevelFallsBack(t *testing.T) {
+	cfg := normalizeCompress(CompressOptions{Level: 42})
+	if cfg.level != gzip.DefaultCompression {
+		t.Fatalf("expected default level, got %d", cfg.level)
+	}
+	cfg = normalizeCompress(CompressOptions{Level: gzip.BestSpeed})
+	if cfg.level != gzip.BestSpeed {
+		t.Fatalf("expected best speed level, got %d", cfg.level)
+	}
+}
+
+func TestCompressNegotiatesDeflate(t *testing.T) {
+	app := bebo.New()
+	app.Use(Compress(CompressOptions{}))
+	app.GET("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "hello deflate")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0.5, deflate")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "deflate" {
+		t.Fatalf("expected deflate, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	body, err := io.ReadAll(flate.NewReader(rec.Body))
+	if err != nil {
+		t.Fatalf("read deflate body: %v", err)
+	}
+	if string(body) != "hello deflate" {
+		t.Fatalf("unexpected body: %s", string(body))
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                          "",
+		"br":                        "",
+		"gzip":                      "gzip",
+		"deflate, gzip":             "gzip",
+		"gzip;q=0, deflate":         "deflate",
+		"gzip;q=0.2, deflate;q=0.8": "deflate",
+		"*":                         "gzip",
+		"gzip;q=0, *":               "deflate",
+		"gzip;q=0, deflate;q=0, *":  "",
+		"*;q=0":                     "",
+		"deflate;q=0.5, *;q=0.8":    "gzip",
+	}
+	for header, want := range cases {
+		if got := negotiateEncoding(header); got != want {
+			t.Fatalf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompressMinSize(t *testing.T) {
+	app := bebo.New()
+	app.Use(Compress(CompressOptions{MinSize: 64}))
+	app.GET("/small", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "tiny")
+	})
+	app.GET("/large", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, strings.Repeat("a", 128))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/small", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != "tiny" {
+		t.Fatalf("expected small response uncompressed")
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary on small compressible response, got %q", rec.Header().Get("Vary"))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected large response compressed")
+	}
+}
+
+func TestCompressSkipsEncodedResponses(t *testing.T) {
+	app := bebo.New()
+	app.Use(Compress(CompressOptions{}))
+	app.GET("/", func(ctx *bebo.Context) error {
+		ctx.ResponseWriter.Header().Set("Content-Encoding", "br")
+		_, err := ctx.ResponseWriter.Write([]byte("already-encoded"))
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "br" || rec.Body.String() != "already-encoded" {
+		t.Fatalf("expected response to pass through untouched")
+	}
+}
+
+func TestCompressWithETag(t *testing.T) {
+	app := bebo.New()
+	app.Use(Compress(CompressOptions{}), ETag(ETagOptions{}))
+	app.GET("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "cached body")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" || rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected compressed response with etag")
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	body, _ := io.ReadAll(reader)
+	if string(body) != "cached body" {
+		t.Fatalf("unexpected body: %s", string(body))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+}
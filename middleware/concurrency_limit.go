@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/apperr"
+)
+
+// concurrencyLimiter counts in-flight requests per key.
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	inFlight map[string]int
+}
+
+func (l *concurrencyLimiter) acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[key] >= l.limit {
+		return false
+	}
+	l.inFlight[key]++
+	return true
+}
+
+func (l *concurrencyLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[key] <= 1 {
+		delete(l.inFlight, key)
+		return
+	}
+	l.inFlight[key]--
+}
+
+// ConcurrencyLimit caps simultaneous in-flight requests per key, rejecting
+// requests over the budget with 429. Slots are released when the handler
+// returns or panics. A nil keyFunc keys by client IP; requests with an empty
+// key are not limited.
+func ConcurrencyLimit(perKey int, keyFunc KeyFunc) bebo.Middleware {
+	if keyFunc == nil {
+		keyFunc = clientIPKey
+	}
+	limiter := &concurrencyLimiter{limit: perKey, inFlight: make(map[string]int)}
+
+	return func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			if perKey <= 0 {
+				return apperr.Internal("concurrency limit not configured", nil)
+			}
+			key := keyFunc(ctx)
+			if key == "" {
+				return next(ctx)
+			}
+			if !limiter.acquire(key) {
+				return apperr.RateLimited("too many concurrent requests", nil)
+			}
+			defer limiter.release(key)
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+)
+
+func TestConcurrencyLimitRejectsOverBudget(t *testing.T) {
+	const perKey = 2
+	entered := make(chan struct{}, perKey)
+	unblock := make(chan struct{})
+
+	app := bebo.New()
+	app.GET("/slow", func(ctx *bebo.Context) error {
+		entered <- struct{}{}
+		<-unblock
+		return ctx.Text(http.StatusOK, "ok")
+	}, ConcurrencyLimit(perKey, nil))
+
+	serve := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var wg sync.WaitGroup
+	codes := make([]int, perKey)
+	for i := 0; i < perKey; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = serve("10.0.0.1").Code
+		}(i)
+	}
+	for i := 0; i < perKey; i++ {
+		<-entered
+	}
+
+	if rec := serve("10.0.0.1"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d for request over budget, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+
+	other := make(chan int, 1)
+	go func() { other <- serve("10.0.0.2").Code }()
+	<-entered
+
+	close(unblock)
+	wg.Wait()
+	if code := <-other; code != http.StatusOK {
+		t.Fatalf("expected other key to proceed, got %d", code)
+	}
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("request %d: expected status %d, got %d", i, http.StatusOK, code)
+		}
+	}
+
+	if rec := serve("10.0.0.1"); rec.Code != http.StatusOK {
+		t.Fatalf("expected slots to be released, got %d", rec.Code)
+	}
+}
+
+func TestConcurrencyLimitReleasesOnPanic(t *testing.T) {
+	limit := ConcurrencyLimit(1, func(*bebo.Context) string { return "client" })
+	panicking := limit(func(*bebo.Context) error { panic("boom") })
+	ok := limit(func(*bebo.Context) error { return nil })
+
+	ctx := bebo.NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, nil)
+	func() {
+		defer func() { _ = recover() }()
+		_ = panicking(ctx)
+	}()
+
+	if err := ok(ctx); err != nil {
+		t.Fatalf("expected slot to be released after panic, got %v", err)
+	}
+}
@@ -10,8 +10,13 @@ import (
 )
 
 // CORSOptions configures CORS behavior.
+// AllowedOrigins entries may be "*", an exact origin, or a wildcard subdomain
+// such as "https://*.example.com". AllowOriginFunc, when set, is consulted for
+// origins not matched by AllowedOrigins. An AllowedHeaders entry of "*" reflects
+// the headers requested in a preflight.
 type CORSOptions struct {
 	AllowedOrigins   []string
+	AllowOriginFunc  func(origin string) bool
 	AllowedMethods   []string
 	AllowedHeaders   []string
 	ExposedHeaders   []string
@@ -20,29 +25,42 @@ type CORSOptions struct {
 }
 
 // CORS enables cross-origin requests.
+// Register it with app.Use so preflight requests are answered even for paths
+// without an OPTIONS route.
 func CORS(options CORSOptions) bebo.Middleware {
 	opts := normalizeCORS(options)
 	return func(next bebo.Handler) bebo.Handler {
 		return func(ctx *bebo.Context) error {
+			headers := ctx.ResponseWriter.Header()
 			origin := ctx.Request.Header.Get("Origin")
+			preflight := ctx.Request.Method == http.MethodOptions && ctx.Request.Header.Get("Access-Control-Request-Method") != ""
+
+			allowed := false
 			if origin != "" {
-				if allowedOrigin, ok := matchOrigin(opts.AllowedOrigins, origin, opts.AllowCredentials); ok {
-					ctx.ResponseWriter.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
-					ctx.ResponseWriter.Header().Add("Vary", "Origin")
+				headers.Add("Vary", "Origin")
+				if allowedOrigin, ok := resolveOrigin(opts, origin); ok {
+					allowed = true
+					headers.Set("Access-Control-Allow-Origin", allowedOrigin)
 					if opts.AllowCredentials {
-						ctx.ResponseWriter.Header().Set("Access-Control-Allow-Credentials", "true")
+						headers.Set("Access-Control-Allow-Credentials", "true")
 					}
-					if len(opts.ExposedHeaders) > 0 {
-						ctx.ResponseWriter.Header().Set("Access-Control-Expose-Headers", strings.Join(opts.ExposedHeaders, ", "))
+					if len(opts.ExposedHeaders) > 0 && !preflight {
+						headers.Set("Access-Control-Expose-Headers", strings.Join(opts.ExposedHeaders, ", "))
 					}
 				}
 			}
 
-			if ctx.Request.Method == http.MethodOptions && ctx.Request.Header.Get("Access-Control-Request-Method") != "" {
-				ctx.ResponseWriter.Header().Set("Access-Control-Allow-Methods", strings.Join(opts.AllowedMethods, ", "))
-				ctx.ResponseWriter.Header().Set("Access-Control-Allow-Headers", strings.Join(opts.AllowedHeaders, ", "))
-				if opts.MaxAge > 0 {
-					ctx.ResponseWriter.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			if preflight {
+				headers.Add("Vary", "Access-Control-Request-Method")
+				headers.Add("Vary", "Access-Control-Request-Headers")
+				if allowed {
+					headers.Set("Access-Control-Allow-Methods", strings.Join(opts.AllowedMethods, ", "))
+					if allowHeaders := preflightHeaders(opts.AllowedHeaders, ctx.Request); allowHeaders != "" {
+						headers.Set("Access-Control-Allow-Headers", allowHeaders)
+					}
+					if opts.MaxAge > 0 {
+						headers.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+					}
 				}
 				ctx.ResponseWriter.WriteHeader(http.StatusNoContent)
 				return nil
@@ -54,7 +72,7 @@ func CORS(options CORSOptions) bebo.Middleware {
 }
 
 func normalizeCORS(options CORSOptions) CORSOptions {
-	if len(options.AllowedOrigins) == 0 {
+	if len(options.AllowedOrigins) == 0 && options.AllowOriginFunc == nil {
 		options.AllowedOrigins = []string{"*"}
 	}
 	if len(options.AllowedMethods) == 0 {
@@ -66,6 +84,18 @@ func normalizeCORS(options CORSOptions) CORSOptions {
 	return options
 }
 
+func resolveOrigin(opts CORSOptions, origin string) (string, bool) {
+	if allowedOrigin, ok := matchOrigin(opts.AllowedOrigins, origin, opts.AllowCredentials); ok {
+		return allowedOrigin, true
+	}
+	if opts.AllowOriginFunc != nil && opts.AllowOriginFunc(origin) {
+		return origin, true
+	}
+	return "", false
+}
+
+// matchOrigin reports the Access-Control-Allow-Origin value for origin.
+// With credentials the origin is always reflected exactly, never "*".
 func matchOrigin(allowed []string, origin string, allowCredentials bool) (string, bool) {
 	for _, entry := range allowed {
 		if entry == "*" {
@@ -77,6 +107,31 @@ func matchOrigin(allowed []string, origin string, allowCredentials bool) (string
 		if strings.EqualFold(entry, origin) {
 			return origin, true
 		}
+		if strings.Contains(entry, "://*.") && matchWildcardOrigin(entry, origin) {
+			return origin, true
+		}
 	}
 	return "", false
 }
+
+func matchWildcardOrigin(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(strings.ToLower(pattern), "://*.")
+	if !ok {
+		return false
+	}
+	originScheme, originHost, ok := strings.Cut(strings.ToLower(origin), "://")
+	if !ok || originScheme != scheme {
+		return false
+	}
+	suffix := "." + host
+	return strings.HasSuffix(originHost, suffix) && len(originHost) > len(suffix)
+}
+
+func preflightHeaders(allowed []string, r *http.Request) string {
+	for _, header := range allowed {
+		if header == "*" {
+			return r.Header.Get("Access-Control-Request-Headers")
+		}
+	}
+	return strings.Join(allowed, ", ")
+}
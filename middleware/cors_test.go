@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+)
+
+func newCORSApp(options CORSOptions) *bebo.App {
+	app := bebo.New()
+	app.Use(CORS(options))
+	app.GET("/items", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	return app
+}
+
+func TestCORSPreflight(t *testing.T) {
+	app := newCORSApp(CORSOptions{AllowedOrigins: []string{"https://app.example.com"}})
+
+	req := httptest.NewRequest(http.MethodOptions, "/items", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Fatalf("unexpected allow origin: %q", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if !strings.Contains(rec.Header().Get("Access-Control-Allow-Methods"), http.MethodGet) {
+		t.Fatalf("expected allowed methods")
+	}
+}
+
+func TestCORSRejectsUnknownOrigin(t *testing.T) {
+	app := newCORSApp(CORSOptions{AllowedOrigins: []string{"https://app.example.com"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Origin", "https://evil.example.net")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected no allow origin header")
+	}
+	if rec.Header().Get("Vary") != "Origin" {
+		t.Fatalf("expected Vary: Origin, got %q", rec.Header().Get("Vary"))
+	}
+}
+
+func TestCORSCredentialsReflectOrigin(t *testing.T) {
+	app := newCORSApp(CORSOptions{AllowCredentials: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Fatalf("expected reflected origin, got %q", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatalf("expected credentials header")
+	}
+}
+
+func TestCORSWildcardSubdomain(t *testing.T) {
+	app := newCORSApp(CORSOptions{AllowedOrigins: []string{"https://*.example.com"}})
+
+	cases := map[string]bool{
+		"https://app.example.com":      true,
+		"https://a.b.example.com":      true,
+		"https://example.com":          false,
+		"http://app.example.com":       false,
+		"https://app.example.com.evil": false,
+	}
+	for origin, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		got := rec.Header().Get("Access-Control-Allow-Origin") == origin
+		if got != want {
+			t.Fatalf("origin %q: allowed=%v, want %v", origin, got, want)
+		}
+	}
+}
+
+func TestCORSAllowOriginFunc(t *testing.T) {
+	app := newCORSApp(CORSOptions{
+		AllowOriginFunc: func(origin string) bool {
+			return strings.HasSuffix(origin, ".internal")
+		},
+		AllowedHeaders: []string{"*"},
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/items", nil)
+	req.Header.Set("Origin", "https://tools.internal")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://tools.internal" {
+		t.Fatalf("expected matcher to allow origin")
+	}
+	if rec.Header().Get("Access-Control-Allow-Headers") != "X-Custom" {
+		t.Fatalf("expected reflected headers, got %q", rec.Header().Get("Access-Control-Allow-Headers"))
+	}
+}
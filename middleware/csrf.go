@@ -1,19 +1,36 @@
 package middleware
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"html"
+	"html/template"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/devmarvs/bebo"
 	"github.com/devmarvs/bebo/apperr"
+	"github.com/devmarvs/bebo/render"
 )
 
-const csrfKey = "bebo.csrf"
+var csrfKey = bebo.NewContextKey[string]("bebo.csrf")
 
 // CSRFOptions configures CSRF behavior.
+// Requests matching SkipPaths (exact or "prefix*") or Skip bypass token
+// verification but still receive the CSRF cookie.
+//
+// Setting SigningKey switches to signed per-form tokens: CSRFToken returns a fresh
+// token bound to the CSRF cookie (or to the session ID with BindSession) that
+// expires after TokenTTL (default 1h). BindSession requires Session middleware
+// to run before CSRF. New sessions are saved before a token is minted so the
+// ID the token is bound to persists; sessions without a server-side ID, such
+// as CookieStore sessions, bind to the CSRF cookie instead.
 type CSRFOptions struct {
 	DisableDefaults bool
 	CookieName      string
@@ -25,8 +42,18 @@ type CSRFOptions struct {
 	CookieSameSite  http.SameSite
 	TokenLength     int
 	Rotate          bool
+	SkipPaths       []string
+	Skip            func(*bebo.Context) bool
+	SigningKey      []byte
+	TokenTTL        time.Duration
+	BindSession     bool
 }
 
+var (
+	errCSRFInvalid = errors.New("csrf token invalid")
+	errCSRFExpired = errors.New("csrf token expired")
+)
+
 // CSRF protects against cross-site request forgery using a double-submit cookie.
 func CSRF(options CSRFOptions) bebo.Middleware {
 	cfg := normalizeCSRF(options)
@@ -42,16 +69,17 @@ func CSRF(options CSRFOptions) bebo.Middleware {
 				setCSRFCookie(ctx.ResponseWriter, token, cfg)
 			}
 
-			ctx.Set(csrfKey, token)
-
-			if isUnsafeMethod(ctx.Request.Method) {
+			if isUnsafeMethod(ctx.Request.Method) && !skipCSRF(ctx, cfg) {
 				submitted := ctx.Request.Header.Get(cfg.HeaderName)
 				if submitted == "" && isFormRequest(ctx.Request) {
 					_ = ctx.Request.ParseForm()
 					submitted = ctx.Request.Form.Get(cfg.FormField)
 				}
-				if submitted == "" || !secureCompare(submitted, token) {
-					return apperr.Forbidden("csrf token invalid", nil)
+				if err := verifyCSRF(ctx, cfg, token, submitted); err != nil {
+					if errors.Is(err, errCSRFExpired) {
+						return apperr.Forbidden("csrf token expired, reload the page and try again", err)
+					}
+					return apperr.Forbidden("csrf token invalid", err)
 				}
 			}
 
@@ -61,8 +89,22 @@ func CSRF(options CSRFOptions) bebo.Middleware {
 					return apperr.Internal("csrf token generation failed", err)
 				}
 				setCSRFCookie(ctx.ResponseWriter, newToken, cfg)
-				ctx.Set(csrfKey, newToken)
+				token = newToken
+			}
+
+			if len(cfg.SigningKey) > 0 {
+				if err := persistCSRFSession(ctx, cfg); err != nil {
+					return apperr.Internal("session save failed", err)
+				}
+				binding, _ := csrfBinding(ctx, cfg, token)
+				signed, err := signCSRFToken(cfg.SigningKey, binding, time.Now())
+				if err != nil {
+					return apperr.Internal("csrf token generation failed", err)
+				}
+				token = signed
 			}
+			csrfKey.Set(ctx, token)
+			ctx.AddTemplateFuncs(csrfFuncs(cfg.FormField, token))
 
 			return next(ctx)
 		}
@@ -71,14 +113,33 @@ func CSRF(options CSRFOptions) bebo.Middleware {
 
 // CSRFToken returns the request CSRF token.
 func CSRFToken(ctx *bebo.Context) string {
-	value, ok := ctx.Get(csrfKey)
-	if !ok {
-		return ""
-	}
-	if token, ok := value.(string); ok {
-		return token
+	return csrfKey.GetOr(ctx, "")
+}
+
+// CSRFTemplateFuncs returns csrf_token and csrf_field placeholders to
+// register with bebo.WithTemplateFuncs. While CSRF middleware runs, they are
+// replaced per request, so forms can use {{ csrf_field }} for the hidden input
+// and scripts {{ csrf_token }}; elsewhere they render empty.
+func CSRFTemplateFuncs() render.FuncMap {
+	return csrfFuncs("csrf_token", "")
+}
+
+func csrfFuncs(field, token string) render.FuncMap {
+	return render.FuncMap{
+		"csrf_token": func() string { return token },
+		"csrf_field": func() template.HTML {
+			if token == "" {
+				return ""
+			}
+			return template.HTML(`<input type="hidden" name="` + html.EscapeString(field) + `" value="` + html.EscapeString(token) + `">`)
+		},
 	}
-	return ""
+}
+
+// SetCSRFToken stores a CSRF token in context, e.g. for rendering templates in tests.
+func SetCSRFToken(ctx *bebo.Context, token string) {
+	csrfKey.Set(ctx, token)
+	ctx.AddTemplateFuncs(csrfFuncs("csrf_token", token))
 }
 
 func normalizeCSRF(options CSRFOptions) CSRFOptions {
@@ -104,10 +165,104 @@ func normalizeCSRF(options CSRFOptions) CSRFOptions {
 		if !options.CookieHTTPOnly {
 			options.CookieHTTPOnly = true
 		}
+		if len(options.SigningKey) > 0 && options.TokenTTL <= 0 {
+			options.TokenTTL = time.Hour
+		}
 	}
 	return options
 }
 
+func verifyCSRF(ctx *bebo.Context, options CSRFOptions, cookieToken, submitted string) error {
+	if submitted == "" {
+		return errCSRFInvalid
+	}
+	if len(options.SigningKey) == 0 {
+		if !secureCompare(submitted, cookieToken) {
+			return errCSRFInvalid
+		}
+		return nil
+	}
+	binding, ok := csrfBinding(ctx, options, cookieToken)
+	if !ok {
+		return errCSRFInvalid
+	}
+	return verifyCSRFToken(options.SigningKey, binding, submitted, options.TokenTTL, time.Now())
+}
+
+// csrfBinding returns the value signed tokens are bound to: the session ID in
+// session-bound mode, otherwise the CSRF cookie token. Sessions without an ID
+// fall back to the cookie token. It reports false when session binding is
+// enabled but no session is loaded.
+func csrfBinding(ctx *bebo.Context, options CSRFOptions, cookieToken string) (string, bool) {
+	if options.BindSession {
+		sess, ok := SessionFromContext(ctx)
+		if !ok || sess == nil {
+			return "session:", false
+		}
+		if sess.ID != "" {
+			return "session:" + sess.ID, true
+		}
+	}
+	return "cookie:" + cookieToken, true
+}
+
+// persistCSRFSession saves a new session before a token is bound to its ID;
+// otherwise the store would hand out a different ID on the next request.
+func persistCSRFSession(ctx *bebo.Context, options CSRFOptions) error {
+	if !options.BindSession {
+		return nil
+	}
+	sess, ok := SessionFromContext(ctx)
+	if !ok || sess == nil || sess.ID == "" || !sess.IsNew() {
+		return nil
+	}
+	return sess.Save(ctx.ResponseWriter)
+}
+
+func signCSRFToken(key []byte, binding string, issued time.Time) (string, error) {
+	payload := make([]byte, 8+16)
+	binary.BigEndian.PutUint64(payload, uint64(issued.Unix()))
+	if _, err := rand.Read(payload[8:]); err != nil {
+		return "", err
+	}
+	mac := csrfMAC(key, payload, binding)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac), nil
+}
+
+func verifyCSRFToken(key []byte, binding, token string, ttl time.Duration, now time.Time) error {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return errCSRFInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != 8+16 {
+		return errCSRFInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, csrfMAC(key, payload, binding)) {
+		return errCSRFInvalid
+	}
+	issued := time.Unix(int64(binary.BigEndian.Uint64(payload)), 0)
+	if ttl > 0 && now.Sub(issued) > ttl {
+		return errCSRFExpired
+	}
+	return nil
+}
+
+func csrfMAC(key, payload []byte, binding string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	mac.Write([]byte(binding))
+	return mac.Sum(nil)
+}
+
+func skipCSRF(ctx *bebo.Context, options CSRFOptions) bool {
+	if shouldSkipPath(ctx.Request.URL.Path, options.SkipPaths) {
+		return true
+	}
+	return options.Skip != nil && options.Skip(ctx)
+}
+
 func csrfFromCookie(r *http.Request, name string) (string, error) {
 	cookie, err := r.Cookie(name)
 	if err != nil {
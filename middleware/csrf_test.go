@@ -3,9 +3,15 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/render"
+	"github.com/devmarvs/bebo/session"
 )
 
 func TestCSRFDeniesMissingToken(t *testing.T) {
@@ -42,3 +48,219 @@ func TestCSRFSetsCookie(t *testing.T) {
 		t.Fatalf("expected csrf cookie")
 	}
 }
+
+func TestCSRFSkipsExemptRequests(t *testing.T) {
+	app := bebo.New()
+	app.Use(CSRF(CSRFOptions{
+		SkipPaths: []string{"/webhooks/*"},
+		Skip: func(ctx *bebo.Context) bool {
+			return strings.HasPrefix(ctx.Request.Header.Get("Authorization"), "Bearer ")
+		},
+	}))
+
+	handler := func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	}
+	app.POST("/webhooks/github", handler)
+	app.POST("/api/items", handler)
+	app.POST("/submit", handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected skipped path to pass, got %d", rec.Code)
+	}
+	if len(rec.Result().Cookies()) == 0 {
+		t.Fatalf("expected csrf cookie on skipped path")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/items", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected skip func to pass, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/submit", nil)
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected protected path to require a token, got %d", rec.Code)
+	}
+}
+
+func newSignedCSRFApp(sessionID string) *bebo.App {
+	app := bebo.New()
+	app.Use(func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			SetSession(ctx, &session.Session{ID: sessionID, Values: map[string]string{}})
+			return next(ctx)
+		}
+	})
+	app.Use(CSRF(CSRFOptions{
+		SigningKey:  []byte("test-signing-key"),
+		TokenTTL:    time.Hour,
+		BindSession: true,
+	}))
+	app.GET("/form", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, CSRFToken(ctx))
+	})
+	app.POST("/submit", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	return app
+}
+
+func submitCSRF(app *bebo.App, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", token)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCSRFSignedTokenAccepted(t *testing.T) {
+	app := newSignedCSRFApp("session-a")
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/form", nil))
+	token := rec.Body.String()
+	if token == "" {
+		t.Fatalf("expected signed token")
+	}
+
+	if rec := submitCSRF(app, token); rec.Code != http.StatusOK {
+		t.Fatalf("expected fresh token to be accepted, got %d", rec.Code)
+	}
+}
+
+func TestCSRFSignedTokenExpired(t *testing.T) {
+	app := newSignedCSRFApp("session-a")
+	token, err := signCSRFToken([]byte("test-signing-key"), "session:session-a", time.Now().Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	rec := submitCSRF(app, token)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected expired token to be rejected, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "reload") {
+		t.Fatalf("expected reload hint, got %q", rec.Body.String())
+	}
+}
+
+func TestCSRFSignedTokenOtherSession(t *testing.T) {
+	app := newSignedCSRFApp("session-a")
+	token, err := signCSRFToken([]byte("test-signing-key"), "session:session-b", time.Now())
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	if rec := submitCSRF(app, token); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected token from another session to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestCSRFSignedTokenFirstVisitRoundTrip(t *testing.T) {
+	stores := map[string]session.Store{
+		"memory": session.NewMemoryStore("sid", time.Hour),
+		"cookie": session.NewCookieStore("sid", []byte("session-key")),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			app := bebo.New()
+			app.Use(Session(store))
+			app.Use(CSRF(CSRFOptions{
+				SigningKey:  []byte("test-signing-key"),
+				BindSession: true,
+			}))
+			app.GET("/form", func(ctx *bebo.Context) error {
+				return ctx.Text(http.StatusOK, CSRFToken(ctx))
+			})
+			app.POST("/submit", func(ctx *bebo.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			})
+
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/form", nil))
+			token := rec.Body.String()
+			cookies := rec.Result().Cookies()
+
+			submit := func(cookies []*http.Cookie) int {
+				req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+				req.Header.Set("X-CSRF-Token", token)
+				for _, cookie := range cookies {
+					req.AddCookie(cookie)
+				}
+				rec := httptest.NewRecorder()
+				app.ServeHTTP(rec, req)
+				return rec.Code
+			}
+			if code := submit(cookies); code != http.StatusOK {
+				t.Fatalf("expected first-visit token to verify, got %d", code)
+			}
+
+			if name == "memory" {
+				var csrfOnly []*http.Cookie
+				for _, cookie := range cookies {
+					if cookie.Name == "bebo_csrf" {
+						csrfOnly = append(csrfOnly, cookie)
+					}
+				}
+				if code := submit(csrfOnly); code != http.StatusForbidden {
+					t.Fatalf("expected token to be rejected without its session, got %d", code)
+				}
+			}
+		})
+	}
+}
+
+func TestCSRFTemplateFuncs(t *testing.T) {
+	dir := t.TempDir()
+	page := `<form>{{ csrf_field }}</form><meta content="{{ csrf_token }}">`
+	if err := os.WriteFile(filepath.Join(dir, "form.html"), []byte(page), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	engine, err := render.NewEngineWithOptions(dir, render.Options{Funcs: CSRFTemplateFuncs()})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	app := bebo.New(bebo.WithRenderer(engine))
+	app.GET("/plain", func(ctx *bebo.Context) error {
+		return ctx.HTML(http.StatusOK, "form.html", nil)
+	})
+	protected := app.Group("/protected", CSRF(CSRFOptions{FormField: "_csrf"}))
+	protected.GET("/form", func(ctx *bebo.Context) error {
+		return ctx.HTML(http.StatusOK, "form.html", nil)
+	})
+
+	// Render without CSRF first: the placeholders must not break later renders.
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/plain", nil))
+	if body := rec.Body.String(); body != `<form></form><meta content="">` {
+		t.Fatalf("unexpected placeholder output %q", body)
+	}
+
+	for i := 0; i < 2; i++ {
+		rec = httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/protected/form", nil))
+		var token string
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == "bebo_csrf" {
+				token = cookie.Value
+			}
+		}
+		if token == "" {
+			t.Fatalf("expected csrf cookie")
+		}
+		want := `<form><input type="hidden" name="_csrf" value="` + token + `"></form><meta content="` + token + `">`
+		if body := rec.Body.String(); body != want {
+			t.Fatalf("unexpected csrf output %q, want %q", body, want)
+		}
+	}
+}
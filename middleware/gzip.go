@@ -8,7 +8,8 @@ import (
 	"github.com/devmarvs/bebo"
 )
 
-// Gzip compresses responses when supported by the client.
+// Gzip compresses every response with gzip when supported by the client.
+// Use Compress for deflate, a minimum size, and content-type rules.
 func Gzip(level int) bebo.Middleware {
 	return func(next bebo.Handler) bebo.Handler {
 		return func(ctx *bebo.Context) error {
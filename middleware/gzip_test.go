@@ -41,3 +41,30 @@ func TestGzipMiddleware(t *testing.T) {
 		t.Fatalf("expected body hello, got %s", string(body))
 	}
 }
+
+func TestGzipIsGzipOnly(t *testing.T) {
+	app := bebo.New()
+	app.Use(Gzip(0))
+
+	app.GET("/logo.png", func(ctx *bebo.Context) error {
+		ctx.ResponseWriter.Header().Set("Content-Type", "image/png")
+		_, err := ctx.ResponseWriter.Write([]byte("png-bytes"))
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/logo.png", nil)
+	req.Header.Set("Accept-Encoding", "deflate")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected deflate-only client to get identity, got %q", rec.Header().Get("Content-Encoding"))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/logo.png", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected Gzip to compress every content type")
+	}
+}
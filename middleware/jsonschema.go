@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/apperr"
+	"github.com/devmarvs/bebo/validate"
+)
+
+// JSONSchema validates request bodies against a JSON Schema before the handler
+// runs. Violations are returned as validation errors with field details; the
+// body is restored so handlers can still bind it. See validate.ParseSchema for
+// the supported keywords. An invalid schema fails every request with 500.
+func JSONSchema(schema []byte) bebo.Middleware {
+	compiled, schemaErr := validate.ParseSchema(schema)
+
+	return func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			if schemaErr != nil {
+				return apperr.Internal("invalid json schema", schemaErr)
+			}
+
+			var data []byte
+			if body := ctx.Request.Body; body != nil && body != http.NoBody {
+				var err error
+				data, err = io.ReadAll(body)
+				_ = body.Close()
+				if err != nil {
+					var maxErr *http.MaxBytesError
+					if errors.As(err, &maxErr) {
+						return apperr.PayloadTooLarge("request body too large", err)
+					}
+					return apperr.BadRequest("failed to read request body", err)
+				}
+			}
+			if len(bytes.TrimSpace(data)) == 0 {
+				return apperr.BadRequest("request body is required", nil)
+			}
+			if err := compiled.ValidateJSON(data); err != nil {
+				return err
+			}
+
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+			ctx.Request.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			}
+			ctx.Request.ContentLength = int64(len(data))
+			return next(ctx)
+		}
+	}
+}
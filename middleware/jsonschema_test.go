@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+)
+
+const signupSchema = `{
+  "type": "object",
+  "required": ["email", "age"],
+  "properties": {
+    "email": {"type": "string", "minLength": 3},
+    "age": {"type": "integer", "minimum": 13},
+    "plan": {"enum": ["free", "pro"]}
+  }
+}`
+
+type schemaErrorBody struct {
+	Error struct {
+		Code   string `json:"code"`
+		Fields []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"fields"`
+	} `json:"error"`
+}
+
+func newSchemaApp(t *testing.T, called *bool) *bebo.App {
+	t.Helper()
+	app := bebo.New()
+	app.POST("/signup", func(ctx *bebo.Context) error {
+		*called = true
+		var payload struct {
+			Email string `json:"email"`
+			Age   int    `json:"age"`
+			Plan  string `json:"plan"`
+		}
+		if err := ctx.BindJSON(&payload); err != nil {
+			return err
+		}
+		return ctx.Text(http.StatusCreated, payload.Email)
+	}, JSONSchema([]byte(signupSchema)))
+	return app
+}
+
+func postSchema(app *bebo.App, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeSchemaErrors(t *testing.T, rec *httptest.ResponseRecorder) schemaErrorBody {
+	t.Helper()
+	var body schemaErrorBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error body: %v (%s)", err, rec.Body.String())
+	}
+	return body
+}
+
+func TestJSONSchemaValidBody(t *testing.T) {
+	var called bool
+	app := newSchemaApp(t, &called)
+
+	rec := postSchema(app, `{"email":"a@b.c","age":21,"plan":"pro"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !called || rec.Body.String() != "a@b.c" {
+		t.Fatalf("expected handler to bind restored body, got %q", rec.Body.String())
+	}
+}
+
+func TestJSONSchemaMissingRequired(t *testing.T) {
+	var called bool
+	app := newSchemaApp(t, &called)
+
+	rec := postSchema(app, `{"email":"a@b.c"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if called {
+		t.Fatalf("handler should not run")
+	}
+	body := decodeSchemaErrors(t, rec)
+	if body.Error.Code != "validation" {
+		t.Fatalf("unexpected code %q", body.Error.Code)
+	}
+	if len(body.Error.Fields) != 1 || body.Error.Fields[0].Field != "age" || body.Error.Fields[0].Message != "age is required" {
+		t.Fatalf("unexpected fields: %+v", body.Error.Fields)
+	}
+}
+
+func TestJSONSchemaTypeMismatch(t *testing.T) {
+	var called bool
+	app := newSchemaApp(t, &called)
+
+	rec := postSchema(app, `{"email":42,"age":"old"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if called {
+		t.Fatalf("handler should not run")
+	}
+	body := decodeSchemaErrors(t, rec)
+	got := map[string]string{}
+	for _, field := range body.Error.Fields {
+		got[field.Field] = field.Message
+	}
+	if len(got) != 2 || got["age"] != "age must be an integer" || got["email"] != "email must be a string" {
+		t.Fatalf("unexpected fields: %+v", body.Error.Fields)
+	}
+}
+
+func TestJSONSchemaInvalidJSON(t *testing.T) {
+	var called bool
+	app := newSchemaApp(t, &called)
+
+	if rec := postSchema(app, `{"email":`); rec.Code != http.StatusBadRequest || called {
+		t.Fatalf("expected 400 without calling handler, got %d", rec.Code)
+	}
+}
@@ -1,17 +1,77 @@
 package middleware
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 
 	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/apperr"
 )
 
-// BodyLimit caps the request body size.
+// BodyLimit caps the request body size. Binders reject a declared
+// Content-Length above maxBytes with 413 before reading the body.
 func BodyLimit(maxBytes int64) bebo.Middleware {
 	return func(next bebo.Handler) bebo.Handler {
 		return func(ctx *bebo.Context) error {
 			ctx.Request.Body = http.MaxBytesReader(ctx.ResponseWriter, ctx.Request.Body, maxBytes)
+			bebo.SetBodyLimit(ctx, maxBytes)
 			return next(ctx)
 		}
 	}
 }
+
+// BufferBody reads the request body into memory so it can be consumed more than once.
+// The replacement body rewinds once a consumer reads it to EOF or closes it, and
+// Request.GetBody returns fresh copies for consumers that stop early. Bodies larger than maxBytes are rejected with 413; a
+// non-positive maxBytes disables the limit.
+func BufferBody(maxBytes int64) bebo.Middleware {
+	return func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			body := ctx.Request.Body
+			if body == nil || body == http.NoBody {
+				return next(ctx)
+			}
+			if maxBytes > 0 && ctx.Request.ContentLength > maxBytes {
+				return apperr.PayloadTooLarge("request body too large", nil)
+			}
+
+			reader := io.Reader(body)
+			if maxBytes > 0 {
+				reader = io.LimitReader(body, maxBytes+1)
+			}
+			data, err := io.ReadAll(reader)
+			_ = body.Close()
+			if err != nil {
+				return apperr.BadRequest("failed to read request body", err)
+			}
+			if maxBytes > 0 && int64(len(data)) > maxBytes {
+				return apperr.PayloadTooLarge("request body too large", nil)
+			}
+
+			ctx.Request.Body = &replayBody{reader: bytes.NewReader(data)}
+			ctx.Request.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			}
+			ctx.Request.ContentLength = int64(len(data))
+			return next(ctx)
+		}
+	}
+}
+
+type replayBody struct {
+	reader *bytes.Reader
+}
+
+func (b *replayBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	if err == io.EOF {
+		_, _ = b.reader.Seek(0, io.SeekStart)
+	}
+	return n, err
+}
+
+func (b *replayBody) Close() error {
+	_, err := b.reader.Seek(0, io.SeekStart)
+	return err
+}
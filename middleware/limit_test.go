@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+)
+
+func TestBufferBodyAllowsMultipleReads(t *testing.T) {
+	app := bebo.New()
+	var verified string
+	app.Use(BufferBody(1024), func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			data, err := io.ReadAll(ctx.Request.Body)
+			if err != nil {
+				return err
+			}
+			verified = string(data)
+			return next(ctx)
+		}
+	})
+
+	var copied string
+	app.POST("/hook", func(ctx *bebo.Context) error {
+		var payload struct {
+			Event string `json:"event"`
+		}
+		if err := ctx.BindJSON(&payload); err != nil {
+			return err
+		}
+		body, err := ctx.Request.GetBody()
+		if err != nil {
+			return err
+		}
+		data, _ := io.ReadAll(body)
+		copied = string(data)
+		return ctx.Text(http.StatusOK, payload.Event)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(`{"event":"push"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Body.String() != "push" {
+		t.Fatalf("expected bound event, got %q", rec.Body.String())
+	}
+	if verified != `{"event":"push"}` || copied != verified {
+		t.Fatalf("expected both consumers to read the body, got %q and %q", verified, copied)
+	}
+}
+
+func TestBufferBodyTooLarge(t *testing.T) {
+	app := bebo.New()
+	app.Use(BufferBody(4))
+	app.POST("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large"))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", rec.Code)
+	}
+}
+
+func TestBodyLimitRejectsDeclaredLength(t *testing.T) {
+	app := bebo.New()
+	app.Use(BodyLimit(8))
+	app.POST("/", func(ctx *bebo.Context) error {
+		var payload map[string]any
+		if err := ctx.BindJSON(&payload); err != nil {
+			return err
+		}
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"too large"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", rec.Code)
+	}
+}
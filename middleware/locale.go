@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/devmarvs/bebo"
+)
+
+// LocaleOptions configures locale negotiation.
+// Supported lists available locales; Default is used when nothing matches and
+// defaults to the first supported locale. QueryParam, when set (e.g. "lang"),
+// lets a query value override Accept-Language.
+type LocaleOptions struct {
+	Supported  []string
+	Default    string
+	QueryParam string
+}
+
+// Locale negotiates the request locale from Accept-Language and stores it with
+// ctx.SetLocale, so ctx.HTML prefers "<name>.<locale>.html" templates.
+func Locale(options LocaleOptions) bebo.Middleware {
+	opts := normalizeLocale(options)
+	return func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			locale := ""
+			if opts.QueryParam != "" {
+				locale, _ = NegotiateLocale(ctx.Request.URL.Query().Get(opts.QueryParam), opts.Supported)
+			}
+			if locale == "" {
+				locale, _ = NegotiateLocale(ctx.Request.Header.Get("Accept-Language"), opts.Supported)
+			}
+			if locale == "" {
+				locale = opts.Default
+			}
+			if locale != "" {
+				ctx.SetLocale(locale)
+				ctx.ResponseWriter.Header().Set("Content-Language", locale)
+			}
+			ctx.ResponseWriter.Header().Add("Vary", "Accept-Language")
+			return next(ctx)
+		}
+	}
+}
+
+func normalizeLocale(options LocaleOptions) LocaleOptions {
+	options.Default = strings.TrimSpace(options.Default)
+	if options.Default == "" && len(options.Supported) > 0 {
+		options.Default = options.Supported[0]
+	}
+	return options
+}
+
+// NegotiateLocale picks the best supported locale for an Accept-Language value.
+// Tags match exactly (case-insensitive) or by base language, so "fr-CA" selects "fr".
+func NegotiateLocale(header string, supported []string) (string, bool) {
+	type preference struct {
+		tag string
+		q   float64
+	}
+	prefs := make([]preference, 0, 4)
+	for _, part := range strings.Split(header, ",") {
+		tag, q := parseEncoding(part)
+		if tag == "" || q <= 0 {
+			continue
+		}
+		prefs = append(prefs, preference{tag: tag, q: q})
+	}
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	for _, pref := range prefs {
+		if pref.tag == "*" && len(supported) > 0 {
+			return supported[0], true
+		}
+		for _, locale := range supported {
+			if strings.EqualFold(locale, pref.tag) {
+				return locale, true
+			}
+		}
+		lang, _, _ := strings.Cut(pref.tag, "-")
+		for _, locale := range supported {
+			if strings.EqualFold(locale, lang) {
+				return locale, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	supported := []string{"en", "fr", "pt-BR"}
+	cases := map[string]string{
+		"fr-CA, en;q=0.8":    "fr",
+		"de, en;q=0.5":       "en",
+		"pt-br":              "pt-BR",
+		"en;q=0.2, fr;q=0.9": "fr",
+		"de":                 "",
+		"*":                  "en",
+		"fr;q=0, en;q=0.1":   "en",
+	}
+	for header, want := range cases {
+		got, _ := NegotiateLocale(header, supported)
+		if got != want {
+			t.Fatalf("%q: expected %q, got %q", header, want, got)
+		}
+	}
+}
+
+func TestLocaleMiddleware(t *testing.T) {
+	app := bebo.New()
+	app.Use(Locale(LocaleOptions{Supported: []string{"en", "fr"}, QueryParam: "lang"}))
+	app.GET("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, ctx.Locale())
+	})
+
+	cases := []struct {
+		target string
+		header string
+		want   string
+	}{
+		{target: "/", header: "fr-FR,fr;q=0.9", want: "fr"},
+		{target: "/", header: "de", want: "en"},
+		{target: "/?lang=fr", header: "en", want: "fr"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		req.Header.Set("Accept-Language", tc.header)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		if rec.Body.String() != tc.want {
+			t.Fatalf("%s %q: expected %q, got %q", tc.target, tc.header, tc.want, rec.Body.String())
+		}
+		if rec.Header().Get("Content-Language") != tc.want {
+			t.Fatalf("expected Content-Language %q, got %q", tc.want, rec.Header().Get("Content-Language"))
+		}
+	}
+}
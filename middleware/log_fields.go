@@ -17,6 +17,7 @@ func DefaultLogFields() []LogField {
 	return []LogField{
 		LogMethod(),
 		LogPath(),
+		LogRoute(),
 		LogRequestID(),
 		LogTraceID(),
 		LogSpanID(),
@@ -41,6 +42,13 @@ func LogPath() LogField {
 	}
 }
 
+// LogRoute logs the matched route pattern.
+func LogRoute() LogField {
+	return func(ctx *bebo.Context, _ *responseRecorder, _ time.Duration) slog.Attr {
+		return slog.String("route", ctx.RoutePattern())
+	}
+}
+
 // LogStatus logs the response status.
 func LogStatus() LogField {
 	return func(_ *bebo.Context, recorder *responseRecorder, _ time.Duration) slog.Attr {
@@ -122,3 +130,14 @@ func LogRequestID() LogField {
 		return slog.String("request_id", ctx.RequestID())
 	}
 }
+
+// LogPrincipalID logs the authenticated principal's id, or "" when the
+// request is unauthenticated.
+func LogPrincipalID() LogField {
+	return func(ctx *bebo.Context, _ *responseRecorder, _ time.Duration) slog.Attr {
+		if principal := ctx.Principal(); principal != nil {
+			return slog.String("principal_id", principal.ID)
+		}
+		return slog.String("principal_id", "")
+	}
+}
@@ -42,3 +42,18 @@ func TestLogRequestBytes(t *testing.T) {
 		t.Fatalf("expected request_bytes %d, got %d", len(body), got)
 	}
 }
+
+func TestLogRoute(t *testing.T) {
+	app := bebo.New()
+	var route string
+	app.GET("/users/:id", func(ctx *bebo.Context) error {
+		route = LogRoute()(ctx, newResponseRecorder(ctx.ResponseWriter), 0).Value.String()
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if route != "/users/:id" {
+		t.Fatalf("expected route pattern, got %q", route)
+	}
+}
@@ -13,8 +13,10 @@ import (
 )
 
 type captureHandler struct {
-	mu     sync.Mutex
-	levels []slog.Level
+	mu         sync.Mutex
+	levels     []slog.Level
+	statuses   []int64
+	principals []string
 }
 
 func (c *captureHandler) Enabled(context.Context, slog.Level) bool {
@@ -25,6 +27,15 @@ func (c *captureHandler) Handle(_ context.Context, record slog.Record) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.levels = append(c.levels, record.Level)
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "status" {
+			c.statuses = append(c.statuses, attr.Value.Int64())
+		}
+		if attr.Key == "principal_id" {
+			c.principals = append(c.principals, attr.Value.String())
+		}
+		return true
+	})
 	return nil
 }
 
@@ -42,6 +53,89 @@ func (c *captureHandler) Levels() []slog.Level {
 	return append([]slog.Level{}, c.levels...)
 }
 
+func (c *captureHandler) Statuses() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64{}, c.statuses...)
+}
+
+func (c *captureHandler) Principals() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.principals...)
+}
+
+func TestLoggerPrincipalID(t *testing.T) {
+	handler := &captureHandler{}
+	app := bebo.New(bebo.WithLogger(slog.New(handler)))
+	app.Use(LoggerWithOptions(LoggerOptions{Fields: []LogField{LogPrincipalID()}}))
+	app.GET("/public", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	app.GET("/private", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	}, RequireAuth(testAuth{principal: &bebo.Principal{ID: "user-7"}}))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/public", nil))
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/private", nil))
+
+	principals := handler.Principals()
+	if len(principals) != 2 || principals[0] != "" || principals[1] != "user-7" {
+		t.Fatalf("expected empty then authenticated principal id, got %q", principals)
+	}
+}
+
+func TestLoggerRecordsRecoveredPanic(t *testing.T) {
+	orderings := map[string][]bebo.Middleware{
+		"logger outside recover": {nil, Recover()},
+		"recover outside logger": {Recover(), nil},
+	}
+	for name, order := range orderings {
+		t.Run(name, func(t *testing.T) {
+			handler := &captureHandler{}
+			app := bebo.New(bebo.WithLogger(slog.New(handler)))
+			logger := LoggerWithOptions(LoggerOptions{Fields: []LogField{LogStatus()}})
+			for _, middleware := range order {
+				if middleware == nil {
+					middleware = logger
+				}
+				app.Use(middleware)
+			}
+			app.GET("/panic", func(ctx *bebo.Context) error {
+				_, _ = ctx.ResponseWriter.Write([]byte("partial"))
+				panic("boom")
+			})
+
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+			statuses := handler.Statuses()
+			if len(statuses) != 1 || statuses[0] != http.StatusInternalServerError {
+				t.Fatalf("expected one access log with status 500, got %v", statuses)
+			}
+		})
+	}
+}
+
+func TestLoggerKeepsErrorStatus(t *testing.T) {
+	handler := &captureHandler{}
+	app := bebo.New(bebo.WithLogger(slog.New(handler)))
+	app.Use(Logger())
+	app.GET("/missing", func(ctx *bebo.Context) error {
+		return apperr.NotFound("missing", nil)
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected response status 404, got %d", rec.Code)
+	}
+	if statuses := handler.Statuses(); len(statuses) != 1 || statuses[0] != http.StatusNotFound {
+		t.Fatalf("expected logged status 404, got %v", statuses)
+	}
+}
+
 func TestLoggerOptionsErrorLevel(t *testing.T) {
 	handler := &captureHandler{}
 	logger := slog.New(handler)
@@ -137,3 +231,30 @@ func TestLoggerSamplingKeepsErrors(t *testing.T) {
 		t.Fatalf("expected error level, got %v", levels[0])
 	}
 }
+
+func TestLoggerStatusClassSampling(t *testing.T) {
+	handler := &captureHandler{}
+	logger := slog.New(handler)
+
+	app := bebo.New(bebo.WithLogger(logger))
+	app.Use(LoggerWithOptions(LoggerOptions{
+		Fields:        []LogField{LogStatus()},
+		StatusSampler: StatusClassSampler(map[int]float64{2: 0}),
+	}))
+	app.GET("/ok", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	app.GET("/missing", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusNotFound, "missing")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if len(handler.Levels()) != 0 {
+		t.Fatalf("expected 2xx responses to be sampled out")
+	}
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if len(handler.Levels()) != 1 {
+		t.Fatalf("expected 4xx response to be logged, got %d entries", len(handler.Levels()))
+	}
+}
@@ -9,20 +9,33 @@ import (
 )
 
 // MethodOverrideOptions configures form method overrides.
+// AllowedMethods is limited to PUT, PATCH, and DELETE; other entries are ignored.
+// Only the form field is read by default. The header source is off unless
+// Header is set or HeaderName is given; the query source is off unless Query
+// is set.
 type MethodOverrideOptions struct {
 	DisableDefaults bool
 	HeaderName      string
 	FormField       string
 	AllowedMethods  []string
+	Header          bool
+	Query           bool
+	QueryParam      string
 }
 
 type methodOverrideConfig struct {
 	headerName string
 	formField  string
+	queryParam string
 	allowed    map[string]struct{}
 }
 
-// MethodOverride enables HTML form method overrides via header or _method field.
+// MethodOverride enables HTML form method overrides via _method field, query
+// parameter, or header, checked in that order. Only POST requests are
+// overridden, and the form field is read only from URL-encoded bodies: JSON
+// payloads carrying "_method" are left alone, and multipart bodies are not
+// parsed before routing, so multipart forms need the query source
+// (e.g. action="/items/1?_method=PUT" with Query set).
 func MethodOverride(options MethodOverrideOptions) bebo.PreMiddleware {
 	cfg := normalizeMethodOverride(options)
 	return func(ctx *bebo.Context) error {
@@ -31,16 +44,22 @@ func MethodOverride(options MethodOverrideOptions) bebo.PreMiddleware {
 			return nil
 		}
 
-		override := strings.TrimSpace(r.Header.Get(cfg.headerName))
-		if override == "" && isFormRequest(r) {
+		override := ""
+		if isURLEncodedForm(r) {
 			_ = r.ParseForm()
-			override = r.Form.Get(cfg.formField)
+			override = strings.TrimSpace(r.PostForm.Get(cfg.formField))
+		}
+		if override == "" && cfg.queryParam != "" {
+			override = strings.TrimSpace(r.URL.Query().Get(cfg.queryParam))
+		}
+		if override == "" && cfg.headerName != "" {
+			override = strings.TrimSpace(r.Header.Get(cfg.headerName))
 		}
 		if override == "" {
 			return nil
 		}
 
-		override = strings.ToUpper(strings.TrimSpace(override))
+		override = strings.ToUpper(override)
 		if _, ok := cfg.allowed[override]; !ok {
 			return apperr.BadRequest("method override not allowed", nil)
 		}
@@ -54,7 +73,7 @@ func normalizeMethodOverride(options MethodOverrideOptions) methodOverrideConfig
 	cfg := methodOverrideConfig{}
 	if options.HeaderName != "" {
 		cfg.headerName = options.HeaderName
-	} else {
+	} else if options.Header {
 		cfg.headerName = "X-HTTP-Method-Override"
 	}
 	if options.FormField != "" {
@@ -62,6 +81,13 @@ func normalizeMethodOverride(options MethodOverrideOptions) methodOverrideConfig
 	} else {
 		cfg.formField = "_method"
 	}
+	if options.Query {
+		if options.QueryParam != "" {
+			cfg.queryParam = options.QueryParam
+		} else {
+			cfg.queryParam = cfg.formField
+		}
+	}
 
 	allowed := options.AllowedMethods
 	if len(allowed) == 0 && !options.DisableDefaults {
@@ -70,10 +96,23 @@ func normalizeMethodOverride(options MethodOverrideOptions) methodOverrideConfig
 	cfg.allowed = make(map[string]struct{}, len(allowed))
 	for _, method := range allowed {
 		method = strings.ToUpper(strings.TrimSpace(method))
-		if method == "" {
+		if !isOverridableMethod(method) {
 			continue
 		}
 		cfg.allowed[method] = struct{}{}
 	}
 	return cfg
 }
+
+func isOverridableMethod(method string) bool {
+	switch method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isURLEncodedForm(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded")
+}
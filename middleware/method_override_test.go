@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"bytes"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -44,3 +46,212 @@ func TestMethodOverrideRejectsInvalid(t *testing.T) {
 		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
 	}
 }
+
+func TestMethodOverrideIgnoresJSON(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{}))
+	app.POST("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "post")
+	})
+	app.DELETE("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "delete")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123?_method=DELETE", strings.NewReader(`{"_method":"DELETE"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "post" {
+		t.Fatalf("expected JSON POST to keep its method, got %q", rec.Body.String())
+	}
+}
+
+func TestMethodOverrideIgnoresNonPost(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{}))
+	app.PUT("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "put")
+	})
+	app.DELETE("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "delete")
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/items/123", strings.NewReader("_method=DELETE"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "put" {
+		t.Fatalf("expected PUT to keep its method, got %q", rec.Body.String())
+	}
+}
+
+func TestMethodOverrideRejectsUnsafeTargets(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{AllowedMethods: []string{http.MethodGet, http.MethodDelete}}))
+	app.GET("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "get")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123", strings.NewReader("_method=GET"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestMethodOverrideFromQuery(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{Query: true}))
+	app.DELETE("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "delete")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123?_method=delete", nil)
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "delete" {
+		t.Fatalf("expected query override to DELETE, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMethodOverrideQueryDisabledByDefault(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{}))
+	app.POST("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "post")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123?_method=DELETE", strings.NewReader("name=x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "post" {
+		t.Fatalf("expected query override to be ignored, got %q", rec.Body.String())
+	}
+}
+
+func TestMethodOverrideFromHeader(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{Header: true}))
+	app.PATCH("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "patch")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123", nil)
+	req.Header.Set("X-HTTP-Method-Override", "PATCH")
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "patch" {
+		t.Fatalf("expected header override to PATCH, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMethodOverrideHeaderDisabledByDefault(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{}))
+	app.POST("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "post")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123", nil)
+	req.Header.Set("X-HTTP-Method-Override", "DELETE")
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "post" {
+		t.Fatalf("expected header override to be ignored, got %q", rec.Body.String())
+	}
+}
+
+func TestMethodOverrideFormPrecedence(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{Query: true, Header: true}))
+	app.PUT("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "put")
+	})
+	app.DELETE("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "delete")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123?_method=DELETE", strings.NewReader("_method=PUT"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-HTTP-Method-Override", "DELETE")
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "put" {
+		t.Fatalf("expected form field to win, got %q", rec.Body.String())
+	}
+}
+
+func TestMethodOverrideQueryIgnoresGet(t *testing.T) {
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{Query: true}))
+	app.GET("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "get")
+	})
+	app.DELETE("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "delete")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/123?_method=DELETE", nil)
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "get" {
+		t.Fatalf("expected GET to keep its method, got %q", rec.Body.String())
+	}
+}
+
+func TestMethodOverrideMultipartUsesQuery(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("_method", "DELETE")
+	_ = writer.WriteField("title", "hello")
+	_ = writer.Close()
+
+	app := bebo.New()
+	app.UsePre(MethodOverride(MethodOverrideOptions{Query: true}))
+	app.POST("/items/:id", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "post")
+	})
+	app.PUT("/items/:id", func(ctx *bebo.Context) error {
+		if err := ctx.Request.ParseMultipartForm(1 << 20); err != nil {
+			return err
+		}
+		return ctx.Text(http.StatusOK, "put:"+ctx.Request.FormValue("title"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/items/123", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Body.String() != "post" {
+		t.Fatalf("expected multipart _method field to be ignored, got %q", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/items/123?_method=PUT", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	if rec.Body.String() != "put:hello" {
+		t.Fatalf("expected query override with the body left intact, got %q", rec.Body.String())
+	}
+}
@@ -6,35 +6,72 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/devmarvs/bebo"
 	"github.com/devmarvs/bebo/apperr"
+	"github.com/devmarvs/bebo/internal/httpx"
 )
 
+// RequestIDOptions configures request id handling.
+// Header is read from the request and echoed on the response; it defaults to
+// bebo.RequestIDHeader. Generator defaults to bebo.NewRequestID.
+type RequestIDOptions struct {
+	Header    string
+	Generator func() string
+}
+
 // RequestID ensures a request id header is present.
 func RequestID() bebo.Middleware {
+	return RequestIDWithOptions(RequestIDOptions{})
+}
+
+// RequestIDWithOptions ensures a request id is present using a custom header or generator.
+// The id is also stored under bebo.RequestIDHeader so ctx.RequestID keeps working,
+// and a custom header is recorded in the request metadata for propagation.
+func RequestIDWithOptions(options RequestIDOptions) bebo.Middleware {
+	opts := normalizeRequestID(options)
 	return func(next bebo.Handler) bebo.Handler {
 		return func(ctx *bebo.Context) error {
-			requestID := ctx.RequestID()
+			requestID := ctx.Request.Header.Get(opts.Header)
 			if requestID == "" {
-				requestID = bebo.NewRequestID()
-				if requestID != "" {
-					ctx.Request.Header.Set(bebo.RequestIDHeader, requestID)
-				}
+				requestID = opts.Generator()
 			}
 			if requestID != "" {
-				ctx.ResponseWriter.Header().Set(bebo.RequestIDHeader, requestID)
+				ctx.Request.Header.Set(opts.Header, requestID)
+				ctx.Request.Header.Set(bebo.RequestIDHeader, requestID)
+				ctx.ResponseWriter.Header().Set(opts.Header, requestID)
 				metadata := bebo.RequestMetadataFromRequest(ctx.Request)
 				metadata.RequestID = requestID
+				if http.CanonicalHeaderKey(opts.Header) != http.CanonicalHeaderKey(bebo.RequestIDHeader) {
+					metadata.Headers = metadata.Headers.Clone()
+					if metadata.Headers == nil {
+						metadata.Headers = http.Header{}
+					}
+					metadata.Headers.Set(opts.Header, requestID)
+				}
 				ctx.Request = ctx.Request.WithContext(bebo.WithRequestMetadata(ctx.Request.Context(), metadata))
+				bebo.SetRequestID(ctx, requestID)
 			}
 			return next(ctx)
 		}
 	}
 }
 
-// Recover converts panics into internal errors.
+func normalizeRequestID(options RequestIDOptions) RequestIDOptions {
+	options.Header = strings.TrimSpace(options.Header)
+	if options.Header == "" {
+		options.Header = bebo.RequestIDHeader
+	}
+	if options.Generator == nil {
+		options.Generator = bebo.NewRequestID
+	}
+	return options
+}
+
+// Recover converts panics into internal errors. It may be registered before
+// or after Logger; either way the access log records a 500.
 func Recover() bebo.Middleware {
 	return func(next bebo.Handler) bebo.Handler {
 		return func(ctx *bebo.Context) (err error) {
@@ -59,13 +96,16 @@ func LoggerWith(fields ...LogField) bebo.Middleware {
 }
 
 // LoggerOptions configures access logging.
+// StatusSampler, when set, replaces Sampler and SampleRate and is called with the
+// final response status. Errors and 5xx responses are always logged.
 type LoggerOptions struct {
-	Fields     []LogField
-	Message    string
-	SkipPaths  []string
-	ErrorLevel bool
-	Sampler    Sampler
-	SampleRate float64
+	Fields        []LogField
+	Message       string
+	SkipPaths     []string
+	ErrorLevel    bool
+	Sampler       Sampler
+	SampleRate    float64
+	StatusSampler StatusSampler
 }
 
 // DefaultLoggerOptions returns default logging options.
@@ -91,41 +131,54 @@ func LoggerWithOptions(options LoggerOptions) bebo.Middleware {
 			recorder := newResponseRecorder(ctx.ResponseWriter)
 			ctx.ResponseWriter = recorder
 
+			logRequest := func(status int, err error) {
+				recorder.resolved = status
+
+				duration := time.Since(start)
+				attrs := make([]slog.Attr, 0, len(options.Fields))
+				for _, field := range options.Fields {
+					attrs = append(attrs, field(ctx, recorder, duration))
+				}
+
+				shouldLog := true
+				if options.StatusSampler != nil {
+					shouldLog = options.StatusSampler(ctx, status)
+				} else if options.Sampler != nil {
+					shouldLog = options.Sampler(ctx)
+				}
+				if !shouldLog && (err != nil || status >= http.StatusInternalServerError) {
+					shouldLog = true
+				}
+				if !shouldLog {
+					return
+				}
+				if options.ErrorLevel && (err != nil || status >= http.StatusInternalServerError) {
+					ctx.Logger().Error(options.Message, attrs...)
+					return
+				}
+				ctx.Logger().Info(options.Message, attrs...)
+			}
+
+			// A panic from an inner handler is logged as a 500 and re-raised, so
+			// the entry is written whether Recover runs inside or outside Logger.
+			defer func() {
+				if rec := recover(); rec != nil {
+					logRequest(http.StatusInternalServerError, apperr.Internal("panic", fmt.Errorf("%v", rec)))
+					panic(rec)
+				}
+			}()
+
 			err := next(ctx)
 
 			status := recorder.Status()
 			if err != nil {
 				if appErr := apperr.As(err); appErr != nil {
 					status = appErr.Status
-				} else if status == 0 {
+				} else if !recorder.header.Written() {
 					status = http.StatusInternalServerError
 				}
 			}
-			if recorder.status == 0 {
-				recorder.status = status
-			}
-
-			duration := time.Since(start)
-			attrs := make([]slog.Attr, 0, len(options.Fields))
-			for _, field := range options.Fields {
-				attrs = append(attrs, field(ctx, recorder, duration))
-			}
-
-			shouldLog := true
-			if options.Sampler != nil {
-				shouldLog = options.Sampler(ctx)
-			}
-			if !shouldLog && (err != nil || status >= http.StatusInternalServerError) {
-				shouldLog = true
-			}
-
-			if shouldLog && options.ErrorLevel && (err != nil || status >= http.StatusInternalServerError) {
-				ctx.Logger().Error(options.Message, attrs...)
-				return err
-			}
-			if shouldLog {
-				ctx.Logger().Info(options.Message, attrs...)
-			}
+			logRequest(status, err)
 			return err
 		}
 	}
@@ -138,7 +191,7 @@ func normalizeLoggerOptions(options LoggerOptions) LoggerOptions {
 	if options.Message == "" {
 		options.Message = "request completed"
 	}
-	if options.Sampler == nil {
+	if options.Sampler == nil && options.StatusSampler == nil {
 		if options.SampleRate == 0 {
 			options.SampleRate = 1
 		}
@@ -150,8 +203,11 @@ func normalizeLoggerOptions(options LoggerOptions) LoggerOptions {
 // responseRecorder captures status and response size.
 type responseRecorder struct {
 	writer http.ResponseWriter
-	status int
+	header httpx.WriteHeaderOnce
 	bytes  int
+	// resolved is the status Logger reports, which differs from the written
+	// one when an error or panic follows an earlier write.
+	resolved int
 }
 
 func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
@@ -163,24 +219,21 @@ func (r *responseRecorder) Header() http.Header {
 }
 
 func (r *responseRecorder) WriteHeader(status int) {
-	r.status = status
-	r.writer.WriteHeader(status)
+	r.header.WriteHeader(r.writer, status)
 }
 
 func (r *responseRecorder) Write(p []byte) (int, error) {
-	if r.status == 0 {
-		r.status = http.StatusOK
-	}
+	r.header.Record(http.StatusOK)
 	n, err := r.writer.Write(p)
 	r.bytes += n
 	return n, err
 }
 
 func (r *responseRecorder) Status() int {
-	if r.status == 0 {
-		return http.StatusOK
+	if r.resolved != 0 {
+		return r.resolved
 	}
-	return r.status
+	return r.header.Status()
 }
 
 func (r *responseRecorder) Bytes() int {
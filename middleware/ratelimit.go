@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"math"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -9,19 +11,15 @@ import (
 
 	"github.com/devmarvs/bebo"
 	"github.com/devmarvs/bebo/apperr"
+	"github.com/devmarvs/bebo/internal/ratelimit"
 )
 
-type bucket struct {
-	tokens float64
-	last   time.Time
-}
-
 // Limiter enforces a token-bucket rate limit.
 type Limiter struct {
 	rate        float64
 	burst       float64
 	mu          sync.Mutex
-	buckets     map[string]*bucket
+	buckets     map[string]*ratelimit.Bucket
 	ttl         time.Duration
 	lastCleanup time.Time
 }
@@ -41,7 +39,7 @@ func NewLimiter(rate float64, burst int, options ...LimiterOption) *Limiter {
 	limiter := &Limiter{
 		rate:    rate,
 		burst:   float64(burst),
-		buckets: make(map[string]*bucket),
+		buckets: make(map[string]*ratelimit.Bucket),
 		ttl:     0,
 	}
 	for _, opt := range options {
@@ -50,8 +48,22 @@ func NewLimiter(rate float64, burst int, options ...LimiterOption) *Limiter {
 	return limiter
 }
 
+// LimitResult describes the outcome of a rate limit check.
+type LimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
 // Allow reports whether the key can proceed.
 func (l *Limiter) Allow(key string) bool {
+	return l.Take(key).Allowed
+}
+
+// Take consumes a token for the key and reports the resulting limit state.
+func (l *Limiter) Take(key string) LimitResult {
 	now := time.Now()
 
 	l.mu.Lock()
@@ -63,30 +75,27 @@ func (l *Limiter) Allow(key string) bool {
 	}
 
 	b, ok := l.buckets[key]
-	if !ok {
-		b = &bucket{tokens: l.burst, last: now}
+	if !ok || (l.ttl > 0 && now.Sub(b.Last) >= l.ttl) {
+		b = ratelimit.New(l.rate, l.burst, now)
 		l.buckets[key] = b
 	}
 
-	if l.ttl > 0 && now.Sub(b.last) >= l.ttl {
-		delete(l.buckets, key)
-		b = &bucket{tokens: l.burst, last: now}
-		l.buckets[key] = b
+	result := LimitResult{Limit: int(l.burst), Allowed: b.Take(now)}
+	if !result.Allowed && l.rate > 0 {
+		result.RetryAfter = secondsDuration((1 - b.Tokens) / l.rate)
 	}
-
-	elapsed := now.Sub(b.last).Seconds()
-	b.tokens += elapsed * l.rate
-	if b.tokens > l.burst {
-		b.tokens = l.burst
+	result.Remaining = int(b.Tokens)
+	if l.rate > 0 {
+		result.Reset = secondsDuration((l.burst - b.Tokens) / l.rate)
 	}
-	b.last = now
+	return result
+}
 
-	if b.tokens < 1 {
-		return false
+func secondsDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
 	}
-
-	b.tokens -= 1
-	return true
+	return time.Duration(seconds * float64(time.Second))
 }
 
 func (l *Limiter) cleanup(now time.Time) {
@@ -94,7 +103,7 @@ func (l *Limiter) cleanup(now time.Time) {
 		return
 	}
 	for key, entry := range l.buckets {
-		if now.Sub(entry.last) >= l.ttl {
+		if now.Sub(entry.Last) >= l.ttl {
 			delete(l.buckets, key)
 		}
 	}
@@ -103,6 +112,9 @@ func (l *Limiter) cleanup(now time.Time) {
 // AllowFunc evaluates whether a request should proceed.
 type AllowFunc func(*bebo.Context, string) (bool, error)
 
+// LimitFunc evaluates a request and reports the resulting limit state.
+type LimitFunc func(*bebo.Context, string) (LimitResult, error)
+
 // KeyFunc extracts a rate limiting key from the request.
 type KeyFunc func(*bebo.Context) string
 
@@ -118,6 +130,7 @@ type rateLimitConfig struct {
 	onError    ErrorHandler
 	retryAfter time.Duration
 	failOpen   bool
+	noHeaders  bool
 }
 
 // RateLimitOption customizes rate limit middleware behavior.
@@ -158,18 +171,31 @@ func RateLimitRetryAfter(duration time.Duration) RateLimitOption {
 	}
 }
 
+// RateLimitHeaders toggles the RateLimit-Limit/Remaining/Reset response headers (enabled by default).
+func RateLimitHeaders(enabled bool) RateLimitOption {
+	return func(cfg *rateLimitConfig) {
+		cfg.noHeaders = !enabled
+	}
+}
+
 // RateLimit enforces a token bucket rate limit.
 func RateLimit(limiter *Limiter, options ...RateLimitOption) bebo.Middleware {
-	return RateLimitWith(func(_ *bebo.Context, key string) (bool, error) {
+	return RateLimitWithResult(func(_ *bebo.Context, key string) (LimitResult, error) {
 		if limiter == nil {
-			return false, apperr.Internal("rate limiter not configured", nil)
+			return LimitResult{}, apperr.Internal("rate limiter not configured", nil)
 		}
-		return limiter.Allow(key), nil
+		return limiter.Take(key), nil
 	}, options...)
 }
 
 // RateLimitWith enforces a rate limit using a custom allow function.
 func RateLimitWith(allow AllowFunc, options ...RateLimitOption) bebo.Middleware {
+	return RateLimitWithResult(limitFromAllow(allow), options...)
+}
+
+// RateLimitWithResult enforces a rate limit using a limit function that reports quota state.
+// RateLimit-* headers are set whenever the result carries a positive Limit.
+func RateLimitWithResult(limit LimitFunc, options ...RateLimitOption) bebo.Middleware {
 	cfg := rateLimitConfig{keyFunc: clientIPKey, retryAfter: 0}
 	for _, opt := range options {
 		opt(&cfg)
@@ -177,13 +203,23 @@ func RateLimitWith(allow AllowFunc, options ...RateLimitOption) bebo.Middleware
 
 	return func(next bebo.Handler) bebo.Handler {
 		return func(ctx *bebo.Context) error {
-			return applyRateLimit(ctx, allow, cfg, next)
+			return applyRateLimit(ctx, limit, cfg, next)
 		}
 	}
 }
 
-func applyRateLimit(ctx *bebo.Context, allow AllowFunc, cfg rateLimitConfig, next bebo.Handler) error {
+func limitFromAllow(allow AllowFunc) LimitFunc {
 	if allow == nil {
+		return nil
+	}
+	return func(ctx *bebo.Context, key string) (LimitResult, error) {
+		allowed, err := allow(ctx, key)
+		return LimitResult{Allowed: allowed}, err
+	}
+}
+
+func applyRateLimit(ctx *bebo.Context, limit LimitFunc, cfg rateLimitConfig, next bebo.Handler) error {
+	if limit == nil {
 		return apperr.Internal("rate limiter not configured", nil)
 	}
 	if next == nil {
@@ -195,7 +231,7 @@ func applyRateLimit(ctx *bebo.Context, allow AllowFunc, cfg rateLimitConfig, nex
 		return next(ctx)
 	}
 
-	allowed, err := allow(ctx, key)
+	result, err := limit(ctx, key)
 	if err != nil {
 		if cfg.onError != nil {
 			return cfg.onError(ctx, err)
@@ -209,9 +245,17 @@ func applyRateLimit(ctx *bebo.Context, allow AllowFunc, cfg rateLimitConfig, nex
 		return apperr.Internal("rate limiter error", err)
 	}
 
-	if !allowed {
-		if cfg.retryAfter > 0 {
-			ctx.ResponseWriter.Header().Set("Retry-After", formatRetryAfter(cfg.retryAfter))
+	if !cfg.noHeaders && result.Limit > 0 {
+		setRateLimitHeaders(ctx.ResponseWriter.Header(), result)
+	}
+
+	if !result.Allowed {
+		retryAfter := cfg.retryAfter
+		if retryAfter <= 0 {
+			retryAfter = result.RetryAfter
+		}
+		if retryAfter > 0 {
+			ctx.ResponseWriter.Header().Set("Retry-After", formatRetryAfter(retryAfter))
 		}
 		if cfg.onLimit != nil {
 			return cfg.onLimit(ctx)
@@ -238,6 +282,16 @@ func clientIPKey(ctx *bebo.Context) string {
 	return ctx.Request.RemoteAddr
 }
 
+func setRateLimitHeaders(header http.Header, result LimitResult) {
+	remaining := result.Remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+}
+
 func formatRetryAfter(duration time.Duration) string {
 	seconds := int(duration.Seconds())
 	if seconds < 1 {
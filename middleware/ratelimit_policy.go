@@ -11,29 +11,31 @@ import (
 )
 
 // RateLimitPolicy maps a route to an allow function.
+// Limit takes precedence over Allow and enables RateLimit-* headers.
 type RateLimitPolicy struct {
 	Method string
 	Host   string
 	Path   string
 	Allow  AllowFunc
+	Limit  LimitFunc
 }
 
 // RateLimitPolicies applies rate limits per route.
 type RateLimitPolicies struct {
 	router   *router.Router
-	policies map[router.RouteID]AllowFunc
+	policies map[router.RouteID]LimitFunc
 }
 
 // NewRateLimitPolicies builds a route policy matcher.
 func NewRateLimitPolicies(policies []RateLimitPolicy) (*RateLimitPolicies, error) {
 	matcher := router.New()
-	mapping := make(map[router.RouteID]AllowFunc, len(policies))
+	mapping := make(map[router.RouteID]LimitFunc, len(policies))
 
 	for _, policy := range policies {
 		if policy.Path == "" {
 			return nil, errors.New("rate limit policy path required")
 		}
-		if policy.Allow == nil {
+		if policy.Allow == nil && policy.Limit == nil {
 			return nil, errors.New("rate limit policy allow function required")
 		}
 		method := policy.Method
@@ -44,7 +46,11 @@ func NewRateLimitPolicies(policies []RateLimitPolicy) (*RateLimitPolicies, error
 		if err != nil {
 			return nil, err
 		}
-		mapping[id] = policy.Allow
+		if policy.Limit != nil {
+			mapping[id] = policy.Limit
+		} else {
+			mapping[id] = limitFromAllow(policy.Allow)
+		}
 	}
 
 	return &RateLimitPolicies{router: matcher, policies: mapping}, nil
@@ -67,8 +73,8 @@ func (p *RateLimitPolicies) Middleware(options ...RateLimitOption) bebo.Middlewa
 			if !ok {
 				return next(ctx)
 			}
-			allow := p.policies[id]
-			return applyRateLimit(ctx, allow, cfg, next)
+			limit := p.policies[id]
+			return applyRateLimit(ctx, limit, cfg, next)
 		}
 	}
 }
@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"strconv"
 	"time"
 
+	"github.com/devmarvs/bebo"
 	"github.com/devmarvs/bebo/redis"
 )
 
@@ -29,16 +32,53 @@ end
 local delta = math.max(0, now - last)
 tokens = math.min(burst, tokens + (delta * rate / 1000))
 local allowed = 0
+local retry = 0
 if tokens >= 1 then
   tokens = tokens - 1
   allowed = 1
+elseif rate > 0 then
+  retry = math.ceil((1 - tokens) * 1000 / rate)
 end
 
 redis.call("HMSET", key, "tokens", tokens, "last", now)
 if ttl > 0 then
   redis.call("PEXPIRE", key, ttl)
 end
-return allowed`
+
+local reset = 0
+if rate > 0 then
+  reset = math.ceil((burst - tokens) * 1000 / rate)
+end
+return {allowed, math.floor(tokens), reset, retry}`
+
+const redisSlidingWindowScript = `local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+local allowed = 0
+local retry = 0
+if count < limit then
+  redis.call("ZADD", key, now, member)
+  count = count + 1
+  allowed = 1
+else
+  local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+  if oldest[2] then
+    retry = tonumber(oldest[2]) + window - now
+  end
+end
+redis.call("PEXPIRE", key, window)
+
+local reset = 0
+local newest = redis.call("ZRANGE", key, -1, -1, "WITHSCORES")
+if newest[2] then
+  reset = tonumber(newest[2]) + window - now
+end
+return {allowed, limit - count, reset, retry}`
 
 // RedisLimiterOptions configures a Redis-backed limiter.
 type RedisLimiterOptions struct {
@@ -56,10 +96,11 @@ type RedisLimiterOptions struct {
 	Now             func() time.Time
 }
 
-// RedisLimiter enforces a token bucket rate limit in Redis.
+// RedisLimiter enforces a token bucket or sliding window rate limit in Redis.
 type RedisLimiter struct {
 	rate    float64
 	burst   float64
+	window  time.Duration
 	options RedisLimiterOptions
 	client  *redis.Client
 	now     func() time.Time
@@ -114,45 +155,117 @@ func NewRedisLimiter(rate float64, burst int, options RedisLimiterOptions) *Redi
 	}
 }
 
-// Allow evaluates a token bucket rate limit for a key.
+// NewRedisSlidingWindowLimiter creates a Redis-backed limiter allowing limit requests per window.
+// Requests are tracked in a sorted set per key, so the window is shared across instances.
+func NewRedisSlidingWindowLimiter(limit int, window time.Duration, options RedisLimiterOptions) *RedisLimiter {
+	limiter := NewRedisLimiter(0, limit, options)
+	limiter.window = window
+	return limiter
+}
+
+// Allow evaluates the rate limit for a key.
 func (l *RedisLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	result, err := l.Take(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return result.Allowed, nil
+}
+
+// Take evaluates the rate limit for a key and reports the shared quota state.
+func (l *RedisLimiter) Take(ctx context.Context, key string) (LimitResult, error) {
 	if l == nil {
-		return false, errors.New("redis limiter not configured")
+		return LimitResult{}, errors.New("redis limiter not configured")
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	if key == "" {
-		return true, nil
+		return LimitResult{Allowed: true}, nil
 	}
 	now := l.now().UnixNano() / int64(time.Millisecond)
-	ttl := l.options.TTL.Milliseconds()
-
-	resp, err := l.client.DoContext(ctx,
-		"EVAL",
-		redisTokenBucketScript,
-		"1",
-		l.options.Prefix+key,
-		strconv.FormatFloat(l.rate, 'f', -1, 64),
-		strconv.FormatFloat(l.burst, 'f', -1, 64),
-		strconv.FormatInt(now, 10),
-		strconv.FormatInt(ttl, 10),
-	)
+
+	var args []string
+	if l.window > 0 {
+		member, err := windowMember(now)
+		if err != nil {
+			return LimitResult{}, err
+		}
+		args = []string{
+			"EVAL",
+			redisSlidingWindowScript,
+			"1",
+			l.options.Prefix + key,
+			strconv.FormatFloat(l.burst, 'f', -1, 64),
+			strconv.FormatInt(l.window.Milliseconds(), 10),
+			strconv.FormatInt(now, 10),
+			member,
+		}
+	} else {
+		args = []string{
+			"EVAL",
+			redisTokenBucketScript,
+			"1",
+			l.options.Prefix + key,
+			strconv.FormatFloat(l.rate, 'f', -1, 64),
+			strconv.FormatFloat(l.burst, 'f', -1, 64),
+			strconv.FormatInt(now, 10),
+			strconv.FormatInt(l.options.TTL.Milliseconds(), 10),
+		}
+	}
+
+	resp, err := l.client.DoContext(ctx, args...)
 	if err != nil {
-		return false, err
+		return LimitResult{}, err
 	}
 
-	switch value := resp.(type) {
-	case int64:
-		return value == 1, nil
-	case string:
-		parsed, err := strconv.Atoi(value)
+	items, ok := resp.([]any)
+	if !ok || len(items) < 4 {
+		return LimitResult{}, errors.New("redis: invalid response")
+	}
+	values := make([]int64, len(items))
+	for i, item := range items {
+		value, err := redisInt(item)
 		if err != nil {
-			return false, err
+			return LimitResult{}, err
 		}
-		return parsed == 1, nil
+		values[i] = value
+	}
+
+	return LimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      int(l.burst),
+		Remaining:  int(values[1]),
+		Reset:      time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+// Limit returns a LimitFunc for use with RateLimitWithResult.
+func (l *RedisLimiter) Limit() LimitFunc {
+	return func(ctx *bebo.Context, key string) (LimitResult, error) {
+		return l.Take(ctx.Request.Context(), key)
+	}
+}
+
+func windowMember(now int64) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(now, 10) + "-" + hex.EncodeToString(buf), nil
+}
+
+func redisInt(value any) (int64, error) {
+	switch typed := value.(type) {
+	case int64:
+		return typed, nil
+	case string:
+		return strconv.ParseInt(typed, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(typed), 10, 64)
 	default:
-		return false, errors.New("redis: invalid response")
+		return 0, errors.New("redis: invalid response")
 	}
 }
 
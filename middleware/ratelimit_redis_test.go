@@ -49,3 +49,42 @@ func TestRedisLimiter(t *testing.T) {
 		t.Fatalf("expected token refill")
 	}
 }
+
+func TestRedisLimiterTakeReportsQuota(t *testing.T) {
+	addr, shutdown := redistest.Start(t)
+	defer shutdown()
+
+	current := time.Unix(0, 0)
+	limiter := NewRedisLimiter(1, 2, RedisLimiterOptions{
+		Address:      addr,
+		DialTimeout:  500 * time.Millisecond,
+		ReadTimeout:  500 * time.Millisecond,
+		WriteTimeout: 500 * time.Millisecond,
+		Now: func() time.Time {
+			return current
+		},
+	})
+
+	result, err := limiter.Take(context.Background(), "client")
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if !result.Allowed || result.Limit != 2 || result.Remaining != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Reset != time.Second {
+		t.Fatalf("expected reset 1s, got %s", result.Reset)
+	}
+
+	_, _ = limiter.Take(context.Background(), "client")
+	result, err = limiter.Take(context.Background(), "client")
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if result.Allowed || result.Remaining != 0 {
+		t.Fatalf("expected limited result, got %+v", result)
+	}
+	if result.RetryAfter != time.Second {
+		t.Fatalf("expected retry after 1s, got %s", result.RetryAfter)
+	}
+}
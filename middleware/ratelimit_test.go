@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/devmarvs/bebo"
 )
 
 func TestLimiterTTLEvictsIdleBuckets(t *testing.T) {
@@ -30,3 +34,60 @@ func TestLimiterTTLEvictsIdleBuckets(t *testing.T) {
 		t.Fatalf("expected 1 bucket, got %d", got)
 	}
 }
+
+func TestRateLimitHeaders(t *testing.T) {
+	app := bebo.New()
+	app.Use(RateLimit(NewLimiter(1, 2)))
+	app.GET("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+		t.Fatalf("expected limit 2, got %q", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "1" {
+		t.Fatalf("expected remaining 1, got %q", got)
+	}
+	if got := rec.Header().Get("RateLimit-Reset"); got != "1" {
+		t.Fatalf("expected reset 1, got %q", got)
+	}
+
+	_ = send()
+	rec = send()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Fatalf("expected remaining 0, got %q", got)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
+	}
+}
+
+func TestRateLimitHeadersDisabled(t *testing.T) {
+	app := bebo.New()
+	app.Use(RateLimit(NewLimiter(1, 2), RateLimitHeaders(false)))
+	app.GET("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Header().Get("RateLimit-Limit") != "" {
+		t.Fatalf("expected no rate limit headers")
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devmarvs/bebo"
+)
+
+// SlidingWindowLimiter enforces at most limit requests per key within a rolling window.
+type SlidingWindowLimiter struct {
+	limit       int
+	window      time.Duration
+	mu          sync.Mutex
+	hits        map[string][]time.Time
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+// NewSlidingWindowLimiter creates a limiter allowing limit requests per window.
+func NewSlidingWindowLimiter(limit int, window time.Duration) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Allow reports whether the key can proceed.
+func (l *SlidingWindowLimiter) Allow(key string) bool {
+	return l.Take(key).Allowed
+}
+
+// Take records a request for the key and reports the resulting limit state.
+func (l *SlidingWindowLimiter) Take(key string) LimitResult {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.lastCleanup.IsZero() || now.Sub(l.lastCleanup) >= l.window {
+		l.cleanup(now)
+		l.lastCleanup = now
+	}
+
+	hits := pruneHits(l.hits[key], now.Add(-l.window))
+	result := LimitResult{Limit: l.limit}
+	if len(hits) < l.limit {
+		hits = append(hits, now)
+		result.Allowed = true
+	} else if len(hits) > 0 {
+		result.RetryAfter = hits[0].Add(l.window).Sub(now)
+	}
+	l.hits[key] = hits
+
+	result.Remaining = l.limit - len(hits)
+	if len(hits) > 0 {
+		result.Reset = hits[len(hits)-1].Add(l.window).Sub(now)
+	}
+	return result
+}
+
+// Limit returns a LimitFunc for use with RateLimitWithResult.
+func (l *SlidingWindowLimiter) Limit() LimitFunc {
+	return func(_ *bebo.Context, key string) (LimitResult, error) {
+		return l.Take(key), nil
+	}
+}
+
+func (l *SlidingWindowLimiter) cleanup(now time.Time) {
+	cutoff := now.Add(-l.window)
+	for key, hits := range l.hits {
+		hits = pruneHits(hits, cutoff)
+		if len(hits) == 0 {
+			delete(l.hits, key)
+			continue
+		}
+		l.hits[key] = hits
+	}
+}
+
+func pruneHits(hits []time.Time, cutoff time.Time) []time.Time {
+	idx := 0
+	for idx < len(hits) && !hits[idx].After(cutoff) {
+		idx++
+	}
+	if idx == 0 {
+		return hits
+	}
+	return append(hits[:0], hits[idx:]...)
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo/internal/redistest"
+)
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	current := time.Unix(100, 0)
+	limiter := NewSlidingWindowLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return current }
+
+	if !limiter.Allow("client") {
+		t.Fatalf("expected first request allowed")
+	}
+	current = current.Add(30 * time.Second)
+	if !limiter.Allow("client") {
+		t.Fatalf("expected second request allowed")
+	}
+
+	result := limiter.Take("client")
+	if result.Allowed {
+		t.Fatalf("expected third request limited")
+	}
+	if result.Remaining != 0 || result.RetryAfter != 30*time.Second {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if !limiter.Allow("other") {
+		t.Fatalf("expected other key allowed")
+	}
+
+	current = current.Add(30 * time.Second)
+	result = limiter.Take("client")
+	if !result.Allowed {
+		t.Fatalf("expected request allowed after oldest hit expires")
+	}
+	if result.Remaining != 0 || result.Reset != time.Minute {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestRedisSlidingWindowLimiter(t *testing.T) {
+	addr, shutdown := redistest.Start(t)
+	defer shutdown()
+
+	current := time.Unix(100, 0)
+	limiter := NewRedisSlidingWindowLimiter(2, time.Minute, RedisLimiterOptions{
+		Address:      addr,
+		DialTimeout:  500 * time.Millisecond,
+		ReadTimeout:  500 * time.Millisecond,
+		WriteTimeout: 500 * time.Millisecond,
+		Now: func() time.Time {
+			return current
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Take(context.Background(), "client")
+		if err != nil {
+			t.Fatalf("take: %v", err)
+		}
+		if !result.Allowed {
+			t.Fatalf("expected request %d allowed", i+1)
+		}
+	}
+
+	result, err := limiter.Take(context.Background(), "client")
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if result.Allowed || result.Remaining != 0 || result.Limit != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.RetryAfter != time.Minute {
+		t.Fatalf("expected retry after 1m, got %s", result.RetryAfter)
+	}
+
+	current = current.Add(time.Minute)
+	allowed, err := limiter.Allow(context.Background(), "client")
+	if err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+	if !allowed {
+		t.Fatalf("expected window to slide")
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+)
+
+func TestRequestIDDefaultHeader(t *testing.T) {
+	app := bebo.New()
+	app.Use(RequestID())
+	app.GET("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, ctx.RequestID())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(bebo.RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(bebo.RequestIDHeader); got != "req-1" {
+		t.Fatalf("expected echoed request id, got %q", got)
+	}
+	if rec.Body.String() != "req-1" {
+		t.Fatalf("expected ctx request id, got %q", rec.Body.String())
+	}
+}
+
+func TestRequestIDCustomHeader(t *testing.T) {
+	app := bebo.New()
+	app.Use(RequestIDWithOptions(RequestIDOptions{Header: "X-Correlation-Id"}))
+	app.GET("/", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusOK, ctx.RequestID())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Correlation-Id", "corr-1")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("X-Correlation-Id"); got != "corr-1" {
+		t.Fatalf("expected correlation id header, got %q", got)
+	}
+	if got := rec.Header().Get(bebo.RequestIDHeader); got != "" {
+		t.Fatalf("expected no default header, got %q", got)
+	}
+	if rec.Body.String() != "corr-1" {
+		t.Fatalf("expected ctx request id corr-1, got %q", rec.Body.String())
+	}
+}
+
+func TestRequestIDCustomGenerator(t *testing.T) {
+	app := bebo.New()
+	app.Use(RequestIDWithOptions(RequestIDOptions{
+		Header:    "Request-Id",
+		Generator: func() string { return "generated" },
+	}))
+	var meta bebo.RequestMetadata
+	app.GET("/", func(ctx *bebo.Context) error {
+		meta = bebo.RequestMetadataFromContext(ctx.Request.Context())
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := rec.Header().Get("Request-Id"); got != "generated" {
+		t.Fatalf("expected generated id, got %q", got)
+	}
+	if meta.RequestID != "generated" {
+		t.Fatalf("expected metadata request id, got %q", meta.RequestID)
+	}
+}
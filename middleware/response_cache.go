@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/cache"
+)
+
+// CacheOptions configures response caching.
+// Only GET responses with status 200 are stored. Requests or responses with
+// Cache-Control: no-store bypass the cache, as do responses setting cookies
+// or larger than MaxSize. Store errors fall back to running the handler.
+type CacheOptions struct {
+	DisableDefaults bool
+	// TTL is how long entries live (default 1m).
+	TTL time.Duration
+	// Vary lists request headers whose values are part of the default key.
+	Vary []string
+	// MaxSize caps the cached body size in bytes (default 1MB).
+	MaxSize int64
+	// Prefix is prepended to cache keys (default "response:").
+	Prefix string
+	// KeyFunc overrides the default key of method, URL and Vary headers.
+	KeyFunc KeyFunc
+	Skip    func(*bebo.Context) bool
+}
+
+// cachedResponse is the stored form of a response.
+type cachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// Cache serves repeated GET requests from store.
+func Cache(store cache.Store, options CacheOptions) bebo.Middleware {
+	cfg := normalizeCacheOptions(options)
+	return func(next bebo.Handler) bebo.Handler {
+		return func(ctx *bebo.Context) error {
+			if store == nil || ctx.Request.Method != http.MethodGet || hasNoStore(ctx.Request.Header) {
+				return next(ctx)
+			}
+			if cfg.Skip != nil && cfg.Skip(ctx) {
+				return next(ctx)
+			}
+			key := cfg.KeyFunc(ctx)
+			if key == "" {
+				return next(ctx)
+			}
+			key = cfg.Prefix + key
+
+			reqCtx := ctx.Request.Context()
+			if entry, ok, err := cache.GetJSON[cachedResponse](reqCtx, store, key); err == nil && ok {
+				copyHeaders(ctx.ResponseWriter.Header(), entry.Header)
+				ctx.ResponseWriter.WriteHeader(entry.Status)
+				_, _ = ctx.ResponseWriter.Write(entry.Body)
+				return nil
+			}
+
+			original := ctx.ResponseWriter
+			writer := newETagWriter(original, cfg.MaxSize)
+			ctx.ResponseWriter = writer
+
+			err := next(ctx)
+			if err != nil {
+				ctx.ResponseWriter = original
+				return err
+			}
+
+			if !writer.overflow && !writer.wrote && cacheableResponse(writer) {
+				entry := cachedResponse{Status: writer.status.Status(), Header: writer.header.Clone(), Body: writer.buffer}
+				_ = cache.SetJSON(reqCtx, store, key, entry, cfg.TTL)
+			}
+			writer.flushToUnderlying()
+			return nil
+		}
+	}
+}
+
+func normalizeCacheOptions(options CacheOptions) CacheOptions {
+	cfg := options
+	if !cfg.DisableDefaults {
+		if cfg.TTL <= 0 {
+			cfg.TTL = time.Minute
+		}
+		if cfg.MaxSize == 0 {
+			cfg.MaxSize = 1 << 20
+		}
+		if cfg.Prefix == "" {
+			cfg.Prefix = "response:"
+		}
+	}
+	if cfg.KeyFunc == nil {
+		vary := cfg.Vary
+		cfg.KeyFunc = func(ctx *bebo.Context) string {
+			return responseCacheKey(ctx.Request, vary)
+		}
+	}
+	return cfg
+}
+
+// responseCacheKey hashes the method, request URI and Vary header values.
+func responseCacheKey(r *http.Request, vary []string) string {
+	var b strings.Builder
+	b.WriteString(r.Method)
+	b.WriteByte(' ')
+	b.WriteString(r.URL.RequestURI())
+	for _, name := range vary {
+		b.WriteByte('\n')
+		b.WriteString(http.CanonicalHeaderKey(name))
+		b.WriteByte(':')
+		b.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func cacheableResponse(writer *etagWriter) bool {
+	if writer.status.Status() != http.StatusOK {
+		return false
+	}
+	if hasNoStore(writer.header) || writer.header.Get("Set-Cookie") != "" {
+		return false
+	}
+	return true
+}
+
+func hasNoStore(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
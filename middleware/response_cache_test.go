@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo"
+)
+
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	ttls    map[string]time.Duration
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.entries[key]
+	return value, ok, nil
+}
+
+func (m *memoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+func (m *memoryCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *memoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+func serveCached(app *bebo.App, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCacheServesRepeatedGets(t *testing.T) {
+	store := newMemoryCache()
+	calls := 0
+	app := bebo.New()
+	app.Use(Cache(store, CacheOptions{TTL: 30 * time.Second, Vary: []string{"Accept-Language"}}))
+	app.GET("/greeting", func(ctx *bebo.Context) error {
+		calls++
+		ctx.ResponseWriter.Header().Set("X-Lang", ctx.Request.Header.Get("Accept-Language"))
+		return ctx.Text(http.StatusOK, "hello "+ctx.Request.Header.Get("Accept-Language"))
+	})
+
+	english := http.Header{"Accept-Language": {"en"}}
+	first := serveCached(app, "/greeting", english)
+	second := serveCached(app, "/greeting", english)
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusOK || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected cached body %q, got %d %q", first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get("X-Lang") != "en" || second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Fatalf("expected cached headers, got %v", second.Header())
+	}
+	for key, ttl := range store.ttls {
+		if ttl != 30*time.Second {
+			t.Fatalf("expected ttl 30s for %s, got %v", key, ttl)
+		}
+	}
+
+	french := serveCached(app, "/greeting", http.Header{"Accept-Language": {"fr"}})
+	if calls != 2 || french.Body.String() != "hello fr" {
+		t.Fatalf("expected vary header to split entries, calls=%d body=%q", calls, french.Body.String())
+	}
+	serveCached(app, "/greeting?page=2", english)
+	if calls != 3 {
+		t.Fatalf("expected query string to be part of the key, calls=%d", calls)
+	}
+}
+
+func TestCacheSkipsUncacheableResponses(t *testing.T) {
+	store := newMemoryCache()
+	app := bebo.New(bebo.WithErrorHandler(func(ctx *bebo.Context, err error) {
+		ctx.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+	}))
+	app.Use(Cache(store, CacheOptions{}))
+	app.GET("/created", func(ctx *bebo.Context) error {
+		return ctx.Text(http.StatusCreated, "created")
+	})
+	app.GET("/private", func(ctx *bebo.Context) error {
+		ctx.ResponseWriter.Header().Set("Cache-Control", "private, no-store")
+		return ctx.Text(http.StatusOK, "secret")
+	})
+	app.GET("/cookie", func(ctx *bebo.Context) error {
+		http.SetCookie(ctx.ResponseWriter, &http.Cookie{Name: "sid", Value: "1"})
+		return ctx.Text(http.StatusOK, "cookie")
+	})
+	app.GET("/fail", func(ctx *bebo.Context) error {
+		return http.ErrAbortHandler
+	})
+
+	for _, path := range []string{"/created", "/private", "/cookie", "/fail"} {
+		serveCached(app, path, nil)
+	}
+	if store.Len() != 0 {
+		t.Fatalf("expected no cached entries, got %d", store.Len())
+	}
+
+	rec := serveCached(app, "/created", nil)
+	if rec.Code != http.StatusCreated || rec.Body.String() != "created" {
+		t.Fatalf("expected uncached response to pass through, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCacheRequestNoStoreAndSkip(t *testing.T) {
+	store := newMemoryCache()
+	calls := 0
+	app := bebo.New()
+	app.Use(Cache(store, CacheOptions{
+		KeyFunc: func(ctx *bebo.Context) string { return ctx.Request.URL.Path },
+		Skip:    func(ctx *bebo.Context) bool { return ctx.Request.Header.Get("Authorization") != "" },
+	}))
+	app.GET("/items", func(ctx *bebo.Context) error {
+		calls++
+		return ctx.Text(http.StatusOK, "items")
+	})
+
+	serveCached(app, "/items", http.Header{"Cache-Control": {"no-store"}})
+	serveCached(app, "/items", http.Header{"Authorization": {"Bearer token"}})
+	if calls != 2 || store.Len() != 0 {
+		t.Fatalf("expected bypassed requests, calls=%d entries=%d", calls, store.Len())
+	}
+
+	serveCached(app, "/items", nil)
+	serveCached(app, "/items?ignored=1", nil)
+	if calls != 3 {
+		t.Fatalf("expected custom key to ignore the query, calls=%d", calls)
+	}
+	if _, ok := store.entries["response:/items"]; !ok {
+		t.Fatalf("expected prefixed custom key, got %v", store.entries)
+	}
+}
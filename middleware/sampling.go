@@ -11,6 +11,9 @@ import (
 // Sampler decides whether to sample a request.
 type Sampler func(*bebo.Context) bool
 
+// StatusSampler decides whether to sample a request once its status is known.
+type StatusSampler func(*bebo.Context, int) bool
+
 // SampleRate returns a sampler that samples a percentage of requests.
 func SampleRate(rate float64) Sampler {
 	if rate >= 1 {
@@ -28,3 +31,20 @@ func SampleRate(rate float64) Sampler {
 		return value < rate
 	}
 }
+
+// StatusClassSampler samples requests at a rate chosen by status class.
+// Rates are keyed by the class digit (2 for 2xx, 4 for 4xx); classes without
+// an entry are always sampled.
+func StatusClassSampler(rates map[int]float64) StatusSampler {
+	samplers := make(map[int]Sampler, len(rates))
+	for class, rate := range rates {
+		samplers[class] = SampleRate(rate)
+	}
+	return func(ctx *bebo.Context, status int) bool {
+		sampler, ok := samplers[status/100]
+		if !ok {
+			return true
+		}
+		return sampler(ctx)
+	}
+}
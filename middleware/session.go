@@ -8,7 +8,7 @@ import (
 	"github.com/devmarvs/bebo/session"
 )
 
-const sessionKey = "bebo.session"
+var sessionKey = bebo.NewContextKey[*session.Session]("bebo.session")
 
 type sessionConfig struct {
 	clearInvalid bool
@@ -45,7 +45,7 @@ func Session(store session.Store, options ...SessionOption) bebo.Middleware {
 				store.Clear(ctx.ResponseWriter, sess)
 			}
 
-			ctx.Set(sessionKey, sess)
+			sessionKey.Set(ctx, sess)
 			return next(ctx)
 		}
 	}
@@ -53,15 +53,10 @@ func Session(store session.Store, options ...SessionOption) bebo.Middleware {
 
 // SessionFromContext returns the loaded session.
 func SessionFromContext(ctx *bebo.Context) (*session.Session, bool) {
-	value, ok := ctx.Get(sessionKey)
-	if !ok {
-		return nil, false
-	}
-	sess, ok := value.(*session.Session)
-	return sess, ok
+	return sessionKey.Get(ctx)
 }
 
 // SetSession stores a session in context for downstream handlers.
 func SetSession(ctx *bebo.Context, sess *session.Session) {
-	ctx.Set(sessionKey, sess)
+	sessionKey.Set(ctx, sess)
 }
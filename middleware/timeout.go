@@ -1,14 +1,44 @@
 package middleware
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/apperr"
 )
 
-// Timeout enforces a request timeout.
+// TimeoutOptions configures the timeout middleware.
+// Status defaults to 503 and may be set to 504 for gateway-style services.
+type TimeoutOptions struct {
+	Duration time.Duration
+	Status   int
+	Message  string
+}
+
+// Timeout enforces a request timeout, responding with a 503 timeout error.
 func Timeout(duration time.Duration) bebo.Middleware {
+	return TimeoutWithOptions(TimeoutOptions{Duration: duration})
+}
+
+// TimeoutWithOptions enforces a request timeout with custom options.
+// Handlers run against a buffered writer, so anything written before the
+// deadline is discarded and replaced by the error response.
+func TimeoutWithOptions(options TimeoutOptions) bebo.Middleware {
+	opts := normalizeTimeout(options)
 	return func(next bebo.Handler) bebo.Handler {
-		return bebo.TimeoutHandler(next, duration)
+		return bebo.TimeoutHandlerWith(next, opts.Duration, func(_ *bebo.Context, cause error) error {
+			return apperr.New(apperr.CodeTimeout, opts.Status, opts.Message, cause)
+		})
+	}
+}
+
+func normalizeTimeout(options TimeoutOptions) TimeoutOptions {
+	if options.Status == 0 {
+		options.Status = http.StatusServiceUnavailable
+	}
+	if options.Message == "" {
+		options.Message = "request timeout"
 	}
+	return options
 }
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo"
+)
+
+func TestTimeoutReturnsJSONError(t *testing.T) {
+	app := bebo.New()
+	app.Use(RequestID(), Timeout(20*time.Millisecond))
+	app.GET("/slow", func(ctx *bebo.Context) error {
+		_, _ = ctx.ResponseWriter.Write([]byte("partial"))
+		<-ctx.Request.Context().Done()
+		return ctx.Text(http.StatusOK, "late")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(bebo.RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "partial") {
+		t.Fatalf("expected partial output to be discarded, got %q", rec.Body.String())
+	}
+
+	var payload struct {
+		Error struct {
+			Code      string `json:"code"`
+			RequestID string `json:"request_id"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Error.Code != "timeout" || payload.Error.RequestID != "req-1" {
+		t.Fatalf("unexpected payload %+v", payload.Error)
+	}
+}
+
+func TestTimeoutWithGatewayStatus(t *testing.T) {
+	app := bebo.New()
+	app.Use(TimeoutWithOptions(TimeoutOptions{Duration: 20 * time.Millisecond, Status: http.StatusGatewayTimeout}))
+	app.GET("/slow", func(ctx *bebo.Context) error {
+		<-ctx.Request.Context().Done()
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d", rec.Code)
+	}
+}
+
+func TestTimeoutPassesFastHandlers(t *testing.T) {
+	app := bebo.New()
+	app.Use(Timeout(time.Second))
+	app.GET("/fast", func(ctx *bebo.Context) error {
+		ctx.ResponseWriter.Header().Set("X-Handler", "fast")
+		return ctx.Text(http.StatusCreated, "ok")
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "ok" {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("X-Handler") != "fast" {
+		t.Fatalf("expected handler header")
+	}
+}
@@ -13,17 +13,31 @@ type Tracer interface {
 	Start(*bebo.Context) (context.Context, func(status int, err error))
 }
 
+// SpanStarter is an optional Tracer extension that receives the span name
+// chosen by the middleware.
+type SpanStarter interface {
+	StartSpan(ctx *bebo.Context, name string) (context.Context, func(status int, err error))
+}
+
+// TracePropagator is an optional Tracer extension that writes the active span's
+// traceparent/tracestate headers, which the middleware sets on the response.
+type TracePropagator interface {
+	Inject(ctx context.Context, header http.Header)
+}
+
 // Trace records request spans using the provided tracer.
 func Trace(tracer Tracer) bebo.Middleware {
 	return TraceWithOptions(TraceOptions{Tracer: tracer})
 }
 
 // TraceOptions configures tracing middleware.
+// SpanName defaults to RouteSpanName.
 type TraceOptions struct {
 	Tracer     Tracer
 	SkipPaths  []string
 	Sampler    Sampler
 	SampleRate float64
+	SpanName   func(*bebo.Context) string
 }
 
 // DefaultTraceOptions returns default tracing options.
@@ -42,9 +56,22 @@ func normalizeTraceOptions(options TraceOptions) TraceOptions {
 		}
 		options.Sampler = SampleRate(options.SampleRate)
 	}
+	if options.SpanName == nil {
+		options.SpanName = RouteSpanName
+	}
 	return options
 }
 
+// RouteSpanName names spans by method and matched route pattern, such as
+// "GET /users/:id". Unmatched requests use the method alone to keep span
+// names low-cardinality.
+func RouteSpanName(ctx *bebo.Context) string {
+	if pattern := ctx.RoutePattern(); pattern != "" {
+		return ctx.Request.Method + " " + pattern
+	}
+	return ctx.Request.Method
+}
+
 // TraceWithOptions records request spans with options.
 func TraceWithOptions(options TraceOptions) bebo.Middleware {
 	options = normalizeTraceOptions(options)
@@ -63,9 +90,18 @@ func TraceWithOptions(options TraceOptions) bebo.Middleware {
 			recorder := newResponseRecorder(ctx.ResponseWriter)
 			ctx.ResponseWriter = recorder
 
-			traceCtx, finish := options.Tracer.Start(ctx)
+			var traceCtx context.Context
+			var finish func(int, error)
+			if starter, ok := options.Tracer.(SpanStarter); ok {
+				traceCtx, finish = starter.StartSpan(ctx, options.SpanName(ctx))
+			} else {
+				traceCtx, finish = options.Tracer.Start(ctx)
+			}
 			if traceCtx != nil {
 				ctx.Request = ctx.Request.WithContext(traceCtx)
+				if propagator, ok := options.Tracer.(TracePropagator); ok {
+					propagator.Inject(traceCtx, ctx.ResponseWriter.Header())
+				}
 			}
 
 			err := next(ctx)
@@ -74,7 +110,7 @@ func TraceWithOptions(options TraceOptions) bebo.Middleware {
 			if err != nil {
 				if appErr := apperr.As(err); appErr != nil {
 					status = appErr.Status
-				} else if status == 0 {
+				} else if !recorder.header.Written() {
 					status = http.StatusInternalServerError
 				}
 			}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -60,3 +61,92 @@ func TestTraceWithOptionsSampling(t *testing.T) {
 		t.Fatalf("expected 0 trace start, got %d", tracer.starts)
 	}
 }
+
+type traceCtxKey struct{}
+
+type spanTracer struct {
+	name   string
+	status int
+	err    error
+}
+
+func (t *spanTracer) Start(ctx *bebo.Context) (context.Context, func(status int, err error)) {
+	return t.StartSpan(ctx, "unnamed")
+}
+
+func (t *spanTracer) StartSpan(ctx *bebo.Context, name string) (context.Context, func(status int, err error)) {
+	t.name = name
+	spanCtx := context.WithValue(ctx.Request.Context(), traceCtxKey{}, "span-1")
+	return spanCtx, func(status int, err error) {
+		t.status = status
+		t.err = err
+	}
+}
+
+func (t *spanTracer) Inject(ctx context.Context, header http.Header) {
+	if ctx.Value(traceCtxKey{}) == "span-1" {
+		header.Set(bebo.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		header.Set(bebo.TracestateHeader, "vendor=value")
+	}
+}
+
+func TestTraceSpanUsesRoutePattern(t *testing.T) {
+	tracer := &spanTracer{}
+	app := bebo.New()
+	app.Use(Trace(tracer))
+
+	var spanValue any
+	app.GET("/users/:id", func(ctx *bebo.Context) error {
+		spanValue = ctx.Request.Context().Value(traceCtxKey{})
+		return ctx.Text(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if tracer.name != "GET /users/:id" {
+		t.Fatalf("expected route span name, got %q", tracer.name)
+	}
+	if spanValue != "span-1" {
+		t.Fatalf("expected span context in request")
+	}
+	if rec.Header().Get(bebo.TraceparentHeader) == "" || rec.Header().Get(bebo.TracestateHeader) != "vendor=value" {
+		t.Fatalf("expected trace headers, got %v", rec.Header())
+	}
+	if tracer.status != http.StatusOK || tracer.err != nil {
+		t.Fatalf("unexpected span result %d %v", tracer.status, tracer.err)
+	}
+}
+
+func TestTraceSpanRecordsError(t *testing.T) {
+	tracer := &spanTracer{}
+	app := bebo.New(bebo.WithErrorHandler(func(*bebo.Context, error) {}))
+	app.Use(Trace(tracer))
+
+	boom := errors.New("boom")
+	app.GET("/boom", func(ctx *bebo.Context) error {
+		return boom
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	if !errors.Is(tracer.err, boom) {
+		t.Fatalf("expected span error, got %v", tracer.err)
+	}
+	if tracer.status != http.StatusInternalServerError {
+		t.Fatalf("expected 500 status, got %d", tracer.status)
+	}
+}
+
+func TestTraceSpanNameForUnmatchedRoute(t *testing.T) {
+	tracer := &spanTracer{}
+	app := bebo.New()
+	app.Use(Trace(tracer))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing/123", nil))
+
+	if tracer.name != http.MethodGet {
+		t.Fatalf("expected method-only span name, got %q", tracer.name)
+	}
+}
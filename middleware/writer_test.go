@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo"
+)
+
+type headerCountingWriter struct {
+	*httptest.ResponseRecorder
+	statuses []int
+}
+
+func (w *headerCountingWriter) WriteHeader(status int) {
+	w.statuses = append(w.statuses, status)
+	w.ResponseRecorder.WriteHeader(status)
+}
+
+func TestStackedWritersWriteHeaderOnce(t *testing.T) {
+	var logged []int
+	captureStatus := func(_ *bebo.Context, recorder *responseRecorder, _ time.Duration) slog.Attr {
+		logged = append(logged, recorder.Status())
+		return slog.Int("status", recorder.Status())
+	}
+
+	app := bebo.New()
+	var hookStatus int
+	app.AfterResponse(func(_ *bebo.Context, status, _ int, _ time.Duration) {
+		hookStatus = status
+	})
+	app.Use(
+		LoggerWithOptions(LoggerOptions{Fields: []LogField{captureStatus}}),
+		LoggerWithOptions(LoggerOptions{Fields: []LogField{captureStatus}}),
+	)
+	app.GET("/created", func(ctx *bebo.Context) error {
+		ctx.ResponseWriter.WriteHeader(http.StatusCreated)
+		ctx.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+		_, err := ctx.ResponseWriter.Write([]byte("ok"))
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/created", nil)
+	rec := &headerCountingWriter{ResponseRecorder: httptest.NewRecorder()}
+	app.ServeHTTP(rec, req)
+
+	if len(rec.statuses) != 1 || rec.statuses[0] != http.StatusCreated {
+		t.Fatalf("expected a single WriteHeader(201), got %v", rec.statuses)
+	}
+	if len(logged) != 2 || logged[0] != http.StatusCreated || logged[1] != http.StatusCreated {
+		t.Fatalf("expected both recorders to log 201, got %v", logged)
+	}
+	if hookStatus != http.StatusCreated {
+		t.Fatalf("expected after-response status 201, got %d", hookStatus)
+	}
+}
+
+func TestBufferedWritersWriteHeaderOnce(t *testing.T) {
+	app := bebo.New()
+	app.Use(LoggerWithOptions(LoggerOptions{Fields: []LogField{LogStatus()}}))
+	app.GET("/created", func(ctx *bebo.Context) error {
+		ctx.ResponseWriter.WriteHeader(http.StatusCreated)
+		ctx.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+		_, err := ctx.ResponseWriter.Write([]byte("created"))
+		return err
+	}, ETag(ETagOptions{}), Compress(CompressOptions{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/created", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := &headerCountingWriter{ResponseRecorder: httptest.NewRecorder()}
+	app.ServeHTTP(rec, req)
+
+	if len(rec.statuses) != 1 || rec.statuses[0] != http.StatusCreated {
+		t.Fatalf("expected a single WriteHeader(201), got %v", rec.statuses)
+	}
+}
+
+func TestResponseRecorderIgnoresLateWriteHeader(t *testing.T) {
+	base := &headerCountingWriter{ResponseRecorder: httptest.NewRecorder()}
+	outer := newResponseRecorder(newResponseRecorder(base))
+
+	_, _ = outer.Write([]byte("body"))
+	outer.WriteHeader(http.StatusNotFound)
+
+	if len(base.statuses) != 0 || base.Code != http.StatusOK {
+		t.Fatalf("expected implicit 200 only, got %v (%d)", base.statuses, base.Code)
+	}
+	if outer.Status() != http.StatusOK {
+		t.Fatalf("expected recorded 200, got %d", outer.Status())
+	}
+}
@@ -87,15 +87,35 @@ func (a AdvisoryLocker) Unlock(ctx context.Context, db *sql.DB) error {
 
 // Runner executes migrations from a directory.
 type Runner struct {
-	DB     *sql.DB
-	Dir    string
+	DB  *sql.DB
+	Dir string
+	// Dirs, when set, replaces Dir with several directories whose
+	// migrations are merged by version; see NewMulti.
+	Dirs   []string
 	Table  string
 	Locker Locker
+	// SeedTable tracks applied seed files (default "schema_seeds").
+	SeedTable string
+	// Splitter splits a migration file into statements that are executed one
+	// at a time in the migration transaction (default SplitStatements).
+	Splitter func(string) []string
+	// MultiStatement executes each file with a single Exec, for drivers that
+	// accept several statements per call.
+	MultiStatement bool
 }
 
 // New creates a new Runner.
 func New(db *sql.DB, dir string) *Runner {
-	return &Runner{DB: db, Dir: dir, Table: "schema_migrations"}
+	return &Runner{DB: db, Dir: dir, Table: "schema_migrations", SeedTable: "schema_seeds"}
+}
+
+// NewMulti creates a Runner loading migrations from several directories,
+// such as a base set plus an environment overlay. Versions must be unique
+// across the directories.
+func NewMulti(db *sql.DB, dirs ...string) *Runner {
+	runner := New(db, "")
+	runner.Dirs = append([]string{}, dirs...)
+	return runner
 }
 
 // Plan returns a migration plan, optionally marking applied migrations.
@@ -262,7 +282,39 @@ func (r *Runner) appliedVersions(ctx context.Context) ([]int, error) {
 }
 
 func (r *Runner) loadMigrations() ([]Migration, error) {
-	entries, err := os.ReadDir(r.Dir)
+	dirs := r.Dirs
+	if len(dirs) == 0 {
+		dirs = []string{r.Dir}
+	}
+
+	byVersion := make(map[int]Migration)
+	for _, dir := range dirs {
+		found, err := loadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for version, migration := range found {
+			if existing, ok := byVersion[version]; ok {
+				return nil, fmt.Errorf("duplicate migration version %d in %s and %s", version, migrationDir(existing), dir)
+			}
+			byVersion[version] = migration
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func loadDir(dir string) (map[int]Migration, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
@@ -291,7 +343,7 @@ func (r *Runner) loadMigrations() ([]Migration, error) {
 			migration.Name = versionParts[1]
 		}
 
-		fullPath := filepath.Join(r.Dir, name)
+		fullPath := filepath.Join(dir, name)
 		switch direction {
 		case "up":
 			migration.UpPath = fullPath
@@ -304,16 +356,14 @@ func (r *Runner) loadMigrations() ([]Migration, error) {
 		byVersion[version] = migration
 	}
 
-	migrations := make([]Migration, 0, len(byVersion))
-	for _, migration := range byVersion {
-		migrations = append(migrations, migration)
-	}
-
-	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].Version < migrations[j].Version
-	})
+	return byVersion, nil
+}
 
-	return migrations, nil
+func migrationDir(migration Migration) string {
+	if migration.UpPath != "" {
+		return filepath.Dir(migration.UpPath)
+	}
+	return filepath.Dir(migration.DownPath)
 }
 
 func (r *Runner) apply(ctx context.Context, migration Migration, up bool) error {
@@ -321,6 +371,20 @@ func (r *Runner) apply(ctx context.Context, migration Migration, up bool) error
 	if !up {
 		path = migration.DownPath
 	}
+	return r.execFile(ctx, path, fmt.Sprintf("migration %d", migration.Version), func(tx *sql.Tx) error {
+		if up {
+			query := fmt.Sprintf(`INSERT INTO %s (version, name, applied_at) VALUES ($1, $2, $3)`, r.Table)
+			_, err := tx.ExecContext(ctx, query, migration.Version, migration.Name, time.Now().UTC())
+			return err
+		}
+		query := fmt.Sprintf(`DELETE FROM %s WHERE version = $1`, r.Table)
+		_, err := tx.ExecContext(ctx, query, migration.Version)
+		return err
+	})
+}
+
+// execFile runs the statements in path and then record in one transaction.
+func (r *Runner) execFile(ctx context.Context, path, label string, record func(*sql.Tx) error) error {
 	contents, err := os.ReadFile(path)
 	if err != nil {
 		return err
@@ -331,20 +395,15 @@ func (r *Runner) apply(ctx context.Context, migration Migration, up bool) error
 		return err
 	}
 
-	if _, err := tx.ExecContext(ctx, string(contents)); err != nil {
-		_ = tx.Rollback()
-		return err
-	}
-
-	if up {
-		query := fmt.Sprintf(`INSERT INTO %s (version, name, applied_at) VALUES ($1, $2, $3)`, r.Table)
-		if _, err := tx.ExecContext(ctx, query, migration.Version, migration.Name, time.Now().UTC()); err != nil {
+	for _, statement := range r.statements(string(contents)) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
 			_ = tx.Rollback()
-			return err
+			return fmt.Errorf("%s: %w", label, err)
 		}
-	} else {
-		query := fmt.Sprintf(`DELETE FROM %s WHERE version = $1`, r.Table)
-		if _, err := tx.ExecContext(ctx, query, migration.Version); err != nil {
+	}
+
+	if record != nil {
+		if err := record(tx); err != nil {
 			_ = tx.Rollback()
 			return err
 		}
@@ -353,6 +412,17 @@ func (r *Runner) apply(ctx context.Context, migration Migration, up bool) error
 	return tx.Commit()
 }
 
+func (r *Runner) statements(contents string) []string {
+	if r.MultiStatement {
+		return []string{contents}
+	}
+	splitter := r.Splitter
+	if splitter == nil {
+		splitter = SplitStatements
+	}
+	return splitter(contents)
+}
+
 func tryAdvisoryLock(ctx context.Context, db *sql.DB, id int64) (bool, error) {
 	row := db.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", id)
 	var locked bool
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -43,3 +44,51 @@ func TestPlanWithoutDB(t *testing.T) {
 		t.Fatalf("expected plan entry to be pending")
 	}
 }
+
+func TestNewMultiMergesDirectories(t *testing.T) {
+	base := t.TempDir()
+	overlay := t.TempDir()
+	for path, contents := range map[string]string{
+		filepath.Join(base, "0001_init.up.sql"):           "-- up",
+		filepath.Join(base, "0001_init.down.sql"):         "-- down",
+		filepath.Join(base, "0003_users.up.sql"):          "-- up",
+		filepath.Join(overlay, "0002_test_data.up.sql"):   "-- up",
+		filepath.Join(overlay, "0002_test_data.down.sql"): "-- down",
+	} {
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	plan, err := NewMulti(nil, base, overlay).Plan(context.Background())
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(plan) != 3 {
+		t.Fatalf("expected 3 migrations, got %d", len(plan))
+	}
+	for i, version := range []int{1, 2, 3} {
+		if plan[i].Version != version {
+			t.Fatalf("expected version %d at %d, got %d", version, i, plan[i].Version)
+		}
+	}
+	if filepath.Dir(plan[1].UpPath) != overlay || filepath.Dir(plan[1].DownPath) != overlay {
+		t.Fatalf("expected overlay paths, got %+v", plan[1].Migration)
+	}
+}
+
+func TestNewMultiRejectsDuplicateVersions(t *testing.T) {
+	base := t.TempDir()
+	overlay := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "0001_init.up.sql"), []byte("-- up"), 0o644); err != nil {
+		t.Fatalf("write base: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(overlay, "0001_seed.down.sql"), []byte("-- down"), 0o644); err != nil {
+		t.Fatalf("write overlay: %v", err)
+	}
+
+	_, err := NewMulti(nil, base, overlay).Plan(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "duplicate migration version 1") {
+		t.Fatalf("expected duplicate version error, got %v", err)
+	}
+}
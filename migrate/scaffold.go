@@ -0,0 +1,205 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Scaffold templates understood by Scaffold.
+const (
+	TemplateEmpty       = ""
+	TemplateCreateTable = "create-table"
+	TemplateAddColumn   = "add-column"
+	TemplateAddIndex    = "add-index"
+	TemplateDropTable   = "drop-table"
+)
+
+// Column describes a column used by scaffold templates.
+type Column struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	Unique     bool
+	PrimaryKey bool
+}
+
+// ScaffoldOptions configures generated migration SQL.
+type ScaffoldOptions struct {
+	Template string
+	Table    string
+	Columns  []Column
+	// Index names the index for add-index (default: idx_<table>_<columns>).
+	Index string
+	// Unique creates a unique index for add-index.
+	Unique bool
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// ParseColumns parses a comma-separated column list of the form
+// name:type[:modifier...]. Supported modifiers are notnull, unique and pk.
+// Commas inside parentheses, as in numeric(10,2), are kept in the type.
+func ParseColumns(spec string) ([]Column, error) {
+	var columns []Column
+	for _, item := range splitColumns(spec) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		column := Column{Name: strings.TrimSpace(fields[0])}
+		if !identifierPattern.MatchString(column.Name) {
+			return nil, fmt.Errorf("invalid column name %q", column.Name)
+		}
+		if len(fields) > 1 {
+			column.Type = strings.TrimSpace(fields[1])
+			if strings.ContainsAny(column.Type, ";'\"") {
+				return nil, fmt.Errorf("invalid type %q for column %s", column.Type, column.Name)
+			}
+		}
+		for _, modifier := range fields[min(len(fields), 2):] {
+			switch strings.ToLower(strings.TrimSpace(modifier)) {
+			case "notnull":
+				column.NotNull = true
+			case "unique":
+				column.Unique = true
+			case "pk", "primarykey":
+				column.PrimaryKey = true
+			default:
+				return nil, fmt.Errorf("unknown modifier %q for column %s", modifier, column.Name)
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func splitColumns(spec string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range spec {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, spec[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, spec[start:])
+}
+
+// Scaffold generates the up and down SQL for a migration template. The empty
+// template yields placeholder comments.
+func Scaffold(options ScaffoldOptions) (string, string, error) {
+	if options.Template == TemplateEmpty {
+		return "-- write migration here\n", "-- rollback migration here\n", nil
+	}
+	if !identifierPattern.MatchString(options.Table) {
+		return "", "", fmt.Errorf("invalid table name %q", options.Table)
+	}
+
+	switch options.Template {
+	case TemplateCreateTable:
+		if err := requireTypedColumns(options.Columns); err != nil {
+			return "", "", err
+		}
+		return createTableSQL(options.Table, options.Columns), dropTableSQL(options.Table), nil
+	case TemplateDropTable:
+		down := fmt.Sprintf("-- recreate %s here\n", options.Table)
+		if len(options.Columns) > 0 {
+			if err := requireTypedColumns(options.Columns); err != nil {
+				return "", "", err
+			}
+			down = createTableSQL(options.Table, options.Columns)
+		}
+		return dropTableSQL(options.Table), down, nil
+	case TemplateAddColumn:
+		if err := requireTypedColumns(options.Columns); err != nil {
+			return "", "", err
+		}
+		var up, down strings.Builder
+		for _, column := range options.Columns {
+			fmt.Fprintf(&up, "ALTER TABLE %s ADD COLUMN %s;\n", options.Table, columnSQL(column))
+		}
+		for i := len(options.Columns) - 1; i >= 0; i-- {
+			fmt.Fprintf(&down, "ALTER TABLE %s DROP COLUMN %s;\n", options.Table, options.Columns[i].Name)
+		}
+		return up.String(), down.String(), nil
+	case TemplateAddIndex:
+		if len(options.Columns) == 0 {
+			return "", "", errors.New("add-index requires at least one column")
+		}
+		names := make([]string, len(options.Columns))
+		for i, column := range options.Columns {
+			names[i] = column.Name
+		}
+		index := options.Index
+		if index == "" {
+			index = "idx_" + strings.ReplaceAll(options.Table, ".", "_") + "_" + strings.Join(names, "_")
+		}
+		if !identifierPattern.MatchString(index) {
+			return "", "", fmt.Errorf("invalid index name %q", index)
+		}
+		create := "CREATE INDEX"
+		if options.Unique {
+			create = "CREATE UNIQUE INDEX"
+		}
+		up := fmt.Sprintf("%s %s ON %s (%s);\n", create, index, options.Table, strings.Join(names, ", "))
+		return up, fmt.Sprintf("DROP INDEX %s;\n", index), nil
+	default:
+		return "", "", fmt.Errorf("unknown migration template %q", options.Template)
+	}
+}
+
+func requireTypedColumns(columns []Column) error {
+	if len(columns) == 0 {
+		return errors.New("at least one column is required")
+	}
+	for _, column := range columns {
+		if column.Type == "" {
+			return fmt.Errorf("column %s requires a type", column.Name)
+		}
+	}
+	return nil
+}
+
+func createTableSQL(table string, columns []Column) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n", table)
+	for i, column := range columns {
+		b.WriteString("    " + columnSQL(column))
+		if i < len(columns)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(");\n")
+	return b.String()
+}
+
+func dropTableSQL(table string) string {
+	return fmt.Sprintf("DROP TABLE %s;\n", table)
+}
+
+func columnSQL(column Column) string {
+	parts := []string{column.Name, strings.ToUpper(column.Type)}
+	if column.PrimaryKey {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if column.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if column.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	return strings.Join(parts, " ")
+}
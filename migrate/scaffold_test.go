@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/devmarvs/bebo/testutil"
+)
+
+func TestScaffoldCreateTableGolden(t *testing.T) {
+	columns, err := ParseColumns("id:bigserial:pk,email:text:notnull:unique,amount:numeric(10,2),created_at:timestamptz")
+	if err != nil {
+		t.Fatalf("parse columns: %v", err)
+	}
+
+	up, down, err := Scaffold(ScaffoldOptions{Template: TemplateCreateTable, Table: "users", Columns: columns})
+	if err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+
+	testutil.AssertGolden(t, filepath.Join("testdata", "golden", "create_table.up.sql"), []byte(up))
+	testutil.AssertGolden(t, filepath.Join("testdata", "golden", "create_table.down.sql"), []byte(down))
+}
+
+func TestScaffoldInverses(t *testing.T) {
+	columns, err := ParseColumns("email:text:notnull,created_at:timestamptz")
+	if err != nil {
+		t.Fatalf("parse columns: %v", err)
+	}
+
+	cases := []struct {
+		template string
+		unique   bool
+		up       string
+		down     string
+	}{
+		{
+			template: TemplateAddColumn,
+			up:       "ALTER TABLE users ADD COLUMN email TEXT NOT NULL;\nALTER TABLE users ADD COLUMN created_at TIMESTAMPTZ;\n",
+			down:     "ALTER TABLE users DROP COLUMN created_at;\nALTER TABLE users DROP COLUMN email;\n",
+		},
+		{
+			template: TemplateAddIndex,
+			unique:   true,
+			up:       "CREATE UNIQUE INDEX idx_users_email_created_at ON users (email, created_at);\n",
+			down:     "DROP INDEX idx_users_email_created_at;\n",
+		},
+		{
+			template: TemplateDropTable,
+			up:       "DROP TABLE users;\n",
+			down:     "CREATE TABLE users (\n    email TEXT NOT NULL,\n    created_at TIMESTAMPTZ\n);\n",
+		},
+	}
+
+	for _, tc := range cases {
+		up, down, err := Scaffold(ScaffoldOptions{Template: tc.template, Table: "users", Columns: columns, Unique: tc.unique})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.template, err)
+		}
+		if up != tc.up {
+			t.Fatalf("%s up:\n%s", tc.template, up)
+		}
+		if down != tc.down {
+			t.Fatalf("%s down:\n%s", tc.template, down)
+		}
+	}
+}
+
+func TestScaffoldEmptyAndErrors(t *testing.T) {
+	up, down, err := Scaffold(ScaffoldOptions{})
+	if err != nil || up != "-- write migration here\n" || down != "-- rollback migration here\n" {
+		t.Fatalf("unexpected empty scaffold: %q %q %v", up, down, err)
+	}
+
+	if _, _, err := Scaffold(ScaffoldOptions{Template: TemplateCreateTable, Table: "users"}); err == nil {
+		t.Fatal("expected error without columns")
+	}
+	if _, _, err := Scaffold(ScaffoldOptions{Template: TemplateCreateTable, Table: "users; drop", Columns: []Column{{Name: "id", Type: "int"}}}); err == nil {
+		t.Fatal("expected invalid table error")
+	}
+	if _, _, err := Scaffold(ScaffoldOptions{Template: "rename-table", Table: "users"}); err == nil {
+		t.Fatal("expected unknown template error")
+	}
+	if _, err := ParseColumns("email:text:sometimes"); err == nil {
+		t.Fatal("expected unknown modifier error")
+	}
+}
@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Seed describes a seed file. Files named "<name>.always.sql" are
+// idempotent and run on every Seed call; other ".sql" files run once and
+// again only when their contents change.
+type Seed struct {
+	Name       string
+	Path       string
+	Checksum   string
+	Idempotent bool
+}
+
+// tableNamePattern matches the plain or schema-qualified table names accepted
+// for SeedTable, which is interpolated into SQL.
+var tableNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+(\.[a-zA-Z0-9_]+)?$`)
+
+// ListSeeds returns the seed files in dir, ordered by file name. A run-once
+// and an idempotent file sharing a name (seed.sql and seed.always.sql) are
+// rejected, since they would share one tracking row.
+func ListSeeds(dir string) ([]Seed, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var seeds []Seed
+	paths := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(contents)
+
+		seed := Seed{Name: strings.TrimSuffix(name, ".sql"), Path: path, Checksum: hex.EncodeToString(sum[:])}
+		if base, ok := strings.CutSuffix(seed.Name, ".always"); ok {
+			seed.Name = base
+			seed.Idempotent = true
+		}
+		if existing, ok := paths[seed.Name]; ok {
+			return nil, fmt.Errorf("duplicate seed %s in %s and %s", seed.Name, existing, path)
+		}
+		paths[seed.Name] = path
+		seeds = append(seeds, seed)
+	}
+
+	sort.Slice(seeds, func(i, j int) bool {
+		return seeds[i].Path < seeds[j].Path
+	})
+	return seeds, nil
+}
+
+// Seed runs the seed files in dir, each in its own transaction, and returns
+// how many ran. Applied seeds are recorded in SeedTable with their checksum
+// so unchanged files are skipped on later runs.
+func (r *Runner) Seed(ctx context.Context, dir string) (int, error) {
+	if r.DB == nil {
+		return 0, errors.New("db is required")
+	}
+	if r.SeedTable == "" {
+		r.SeedTable = "schema_seeds"
+	}
+	if !tableNamePattern.MatchString(r.SeedTable) {
+		return 0, fmt.Errorf("invalid seed table name: %s", r.SeedTable)
+	}
+	if err := r.ensureSeedTable(ctx); err != nil {
+		return 0, err
+	}
+	if r.Locker != nil {
+		if err := r.Locker.Lock(ctx, r.DB); err != nil {
+			return 0, err
+		}
+		defer func() {
+			_ = r.Locker.Unlock(ctx, r.DB)
+		}()
+	}
+
+	seeds, err := ListSeeds(dir)
+	if err != nil {
+		return 0, err
+	}
+	applied, err := r.appliedSeeds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, seed := range seeds {
+		checksum, ok := applied[seed.Name]
+		if !seed.Idempotent && ok && checksum == seed.Checksum {
+			continue
+		}
+		var record func(*sql.Tx) error
+		if !seed.Idempotent {
+			record = r.recordSeed(ctx, seed)
+		}
+		if err := r.execFile(ctx, seed.Path, "seed "+seed.Name, record); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (r *Runner) ensureSeedTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (name text primary key, checksum text not null, applied_at timestamptz not null)`, r.SeedTable)
+	_, err := r.DB.ExecContext(ctx, query)
+	return err
+}
+
+func (r *Runner) appliedSeeds(ctx context.Context) (map[string]string, error) {
+	query := fmt.Sprintf(`SELECT name, checksum FROM %s`, r.SeedTable)
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]string)
+	for rows.Next() {
+		var name, checksum string
+		if err := rows.Scan(&name, &checksum); err != nil {
+			return nil, err
+		}
+		applied[name] = checksum
+	}
+	return applied, rows.Err()
+}
+
+// recordSeed replaces the seed's row, so a changed file updates its checksum.
+func (r *Runner) recordSeed(ctx context.Context, seed Seed) func(*sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE name = $1`, r.SeedTable), seed.Name); err != nil {
+			return err
+		}
+		query := fmt.Sprintf(`INSERT INTO %s (name, checksum, applied_at) VALUES ($1, $2, $3)`, r.SeedTable)
+		_, err := tx.ExecContext(ctx, query, seed.Name, seed.Checksum, time.Now().UTC())
+		return err
+	}
+}
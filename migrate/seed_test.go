@@ -0,0 +1,218 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSeedRunsChangedAndIdempotentFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSeed(t, dir, "001_roles.sql", "INSERT INTO roles VALUES ('admin');\nINSERT INTO roles VALUES ('member');")
+	writeSeed(t, dir, "002_settings.always.sql", "UPSERT settings")
+	writeSeed(t, dir, "README.md", "not a seed")
+
+	fake := &fakeSeedDB{seeds: map[string]string{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	runner := New(db, "")
+	ctx := context.Background()
+
+	count, err := runner.Seed(ctx, dir)
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 seeds to run, got %d", count)
+	}
+	if got := fake.Executed(); strings.Join(got, "|") != "INSERT INTO roles VALUES ('admin')|INSERT INTO roles VALUES ('member')|UPSERT settings" {
+		t.Fatalf("unexpected statements %q", got)
+	}
+	if _, ok := fake.seeds["002_settings"]; ok {
+		t.Fatalf("expected idempotent seed to stay untracked")
+	}
+
+	count, err = runner.Seed(ctx, dir)
+	if err != nil {
+		t.Fatalf("reseed: %v", err)
+	}
+	if count != 1 || fake.Executed()[3] != "UPSERT settings" || len(fake.Executed()) != 4 {
+		t.Fatalf("expected only the idempotent seed to re-run, count=%d statements=%q", count, fake.Executed())
+	}
+
+	writeSeed(t, dir, "001_roles.sql", "INSERT INTO roles VALUES ('owner');")
+	if count, err = runner.Seed(ctx, dir); err != nil || count != 2 {
+		t.Fatalf("expected changed seed to re-run, count=%d err=%v", count, err)
+	}
+	if len(fake.seeds) != 1 {
+		t.Fatalf("expected one tracked seed row, got %v", fake.seeds)
+	}
+}
+
+func TestSeedRollsBackFailedFile(t *testing.T) {
+	dir := t.TempDir()
+	writeSeed(t, dir, "001_bad.sql", "INSERT INTO ok VALUES (1);\nFAIL")
+
+	fake := &fakeSeedDB{seeds: map[string]string{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	_, err := New(db, "").Seed(context.Background(), dir)
+	if err == nil || !strings.Contains(err.Error(), "seed 001_bad") {
+		t.Fatalf("expected labelled seed error, got %v", err)
+	}
+	if len(fake.seeds) != 0 || len(fake.Executed()) != 0 {
+		t.Fatalf("expected failed seed to roll back, seeds=%v statements=%q", fake.seeds, fake.Executed())
+	}
+}
+
+func TestListSeedsRejectsNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	writeSeed(t, dir, "001_roles.sql", "INSERT INTO roles VALUES ('admin');")
+	writeSeed(t, dir, "001_roles.always.sql", "UPSERT roles")
+
+	_, err := ListSeeds(dir)
+	if err == nil || !strings.Contains(err.Error(), "duplicate seed 001_roles") {
+		t.Fatalf("expected duplicate seed error, got %v", err)
+	}
+}
+
+func TestSeedRejectsInvalidTable(t *testing.T) {
+	fake := &fakeSeedDB{seeds: map[string]string{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	runner := New(db, "")
+	runner.SeedTable = "seeds; DROP TABLE users"
+	_, err := runner.Seed(context.Background(), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "invalid seed table name") {
+		t.Fatalf("expected invalid table error, got %v", err)
+	}
+	if len(fake.Executed()) != 0 {
+		t.Fatalf("expected no statements, got %q", fake.Executed())
+	}
+}
+
+func writeSeed(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+// fakeSeedDB is a database/sql driver understanding the statements Seed
+// issues. Work inside a transaction is applied on commit.
+type fakeSeedDB struct {
+	mu       sync.Mutex
+	seeds    map[string]string
+	executed []string
+}
+
+func (d *fakeSeedDB) Connect(context.Context) (driver.Conn, error) { return &fakeSeedConn{db: d}, nil }
+func (d *fakeSeedDB) Driver() driver.Driver                        { return nil }
+
+func (d *fakeSeedDB) Executed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.executed...)
+}
+
+type fakeSeedConn struct {
+	db      *fakeSeedDB
+	pending []func()
+	inTx    bool
+}
+
+func (c *fakeSeedConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSeedStmt{conn: c, query: query}, nil
+}
+func (c *fakeSeedConn) Close() error { return nil }
+func (c *fakeSeedConn) Begin() (driver.Tx, error) {
+	c.inTx, c.pending = true, nil
+	return c, nil
+}
+
+func (c *fakeSeedConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	for _, op := range c.pending {
+		op()
+	}
+	c.inTx, c.pending = false, nil
+	return nil
+}
+
+func (c *fakeSeedConn) Rollback() error {
+	c.inTx, c.pending = false, nil
+	return nil
+}
+
+type fakeSeedStmt struct {
+	conn  *fakeSeedConn
+	query string
+}
+
+func (s *fakeSeedStmt) Close() error  { return nil }
+func (s *fakeSeedStmt) NumInput() int { return strings.Count(s.query, "$") }
+
+func (s *fakeSeedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.conn.db
+	var op func()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS schema_seeds"):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(s.query, "DELETE FROM schema_seeds"):
+		op = func() { delete(db.seeds, args[0].(string)) }
+	case strings.HasPrefix(s.query, "INSERT INTO schema_seeds"):
+		op = func() { db.seeds[args[0].(string)] = args[1].(string) }
+	case s.query == "FAIL":
+		return nil, errors.New("syntax error")
+	default:
+		query := s.query
+		op = func() { db.executed = append(db.executed, query) }
+	}
+	if s.conn.inTx {
+		s.conn.pending = append(s.conn.pending, op)
+	} else {
+		db.mu.Lock()
+		op()
+		db.mu.Unlock()
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSeedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query != "SELECT name, checksum FROM schema_seeds" {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	rows := &fakeSeedRows{}
+	for name, checksum := range db.seeds {
+		rows.values = append(rows.values, []driver.Value{name, checksum})
+	}
+	return rows, nil
+}
+
+type fakeSeedRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeSeedRows) Columns() []string { return []string{"name", "checksum"} }
+func (r *fakeSeedRows) Close() error      { return nil }
+func (r *fakeSeedRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
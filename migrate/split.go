@@ -0,0 +1,97 @@
+package migrate
+
+import "strings"
+
+// SplitStatements splits SQL into individual statements on `;` terminators.
+// Semicolons inside single-quoted strings, double-quoted or backtick-quoted
+// identifiers, comments, and dollar-quoted bodies ($$ ... $$ or $tag$ ... $tag$)
+// are kept. Statements are trimmed, terminators dropped, and statements that
+// contain only comments are skipped. Backslash escapes inside strings are not
+// recognized; use a custom Runner.Splitter when a dialect relies on them.
+func SplitStatements(sql string) []string {
+	var statements []string
+	start := 0
+	hasCode := false
+
+	flush := func(end int) {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(sql[start:end]))
+		}
+		start = end + 1
+		hasCode = false
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == ';':
+			flush(i)
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+		case c == '\'' || c == '"' || c == '`':
+			hasCode = true
+			i = skipQuoted(sql, i, c)
+		case c == '$':
+			hasCode = true
+			if tag, ok := dollarTag(sql[i:]); ok {
+				end := strings.Index(sql[i+len(tag):], tag)
+				if end < 0 {
+					i = len(sql)
+				} else {
+					i += len(tag) + end + len(tag) - 1
+				}
+			}
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			hasCode = true
+		}
+	}
+	if start < len(sql) {
+		flush(len(sql))
+	}
+	return statements
+}
+
+// skipQuoted returns the index of the quote closing the literal opened at
+// start. A doubled quote is an escaped quote.
+func skipQuoted(sql string, start int, quote byte) int {
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(sql)
+}
+
+// dollarTag reports the dollar-quote delimiter at the start of s, such as
+// "$$" or "$body$". Positional parameters like $1 are not tags.
+func dollarTag(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1], true
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 1:
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
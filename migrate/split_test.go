@@ -0,0 +1,193 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSplitStatementsMultiple(t *testing.T) {
+	sql := `-- create tables
+CREATE TABLE users (id bigint primary key);
+CREATE TABLE posts (id bigint primary key, user_id bigint);
+
+/* trailing; comment */
+`
+	got := SplitStatements(sql)
+	want := []string{
+		"-- create tables\nCREATE TABLE users (id bigint primary key)",
+		"CREATE TABLE posts (id bigint primary key, user_id bigint)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected statements: %q", got)
+	}
+}
+
+func TestSplitStatementsQuoted(t *testing.T) {
+	sql := `INSERT INTO notes (body) VALUES ('a; b ''c;'' d');
+UPDATE "odd;name" SET v = $1;
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at = now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+DO $body$ BEGIN PERFORM 1; END $body$`
+	got := SplitStatements(sql)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 statements, got %d: %q", len(got), got)
+	}
+	if got[0] != `INSERT INTO notes (body) VALUES ('a; b ''c;'' d')` {
+		t.Fatalf("unexpected string literal statement: %q", got[0])
+	}
+	if got[1] != `UPDATE "odd;name" SET v = $1` {
+		t.Fatalf("unexpected identifier statement: %q", got[1])
+	}
+	if !strings.HasSuffix(got[2], "$$ LANGUAGE plpgsql") {
+		t.Fatalf("unexpected dollar-quoted statement: %q", got[2])
+	}
+	if got[3] != "DO $body$ BEGIN PERFORM 1; END $body$" {
+		t.Fatalf("unexpected tagged statement: %q", got[3])
+	}
+}
+
+func TestUpExecutesStatementsIndividually(t *testing.T) {
+	dir := t.TempDir()
+	up := "CREATE TABLE a (id int);\nINSERT INTO a VALUES (1);\nINSERT INTO notes VALUES ('x;y');\n"
+	if err := os.WriteFile(filepath.Join(dir, "0001_init.up.sql"), []byte(up), 0o644); err != nil {
+		t.Fatalf("write up: %v", err)
+	}
+
+	db, rec := openRecordingDB(t)
+	runner := New(db, dir)
+	count, err := runner.Up(context.Background())
+	if err != nil || count != 1 {
+		t.Fatalf("up: %d %v", count, err)
+	}
+	want := []string{"CREATE TABLE a (id int)", "INSERT INTO a VALUES (1)", "INSERT INTO notes VALUES ('x;y')"}
+	if got := rec.migrationExecs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected execs: %q", got)
+	}
+
+	db, rec = openRecordingDB(t)
+	runner = New(db, dir)
+	runner.MultiStatement = true
+	if _, err := runner.Up(context.Background()); err != nil {
+		t.Fatalf("up multi: %v", err)
+	}
+	if got := rec.migrationExecs(); !reflect.DeepEqual(got, []string{up}) {
+		t.Fatalf("expected a single exec, got %q", got)
+	}
+}
+
+func TestUpRollsBackOnStatementError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0001_init.up.sql"), []byte("SELECT 1; FAIL; SELECT 2;"), 0o644); err != nil {
+		t.Fatalf("write up: %v", err)
+	}
+
+	db, rec := openRecordingDB(t)
+	if _, err := New(db, dir).Up(context.Background()); !errors.Is(err, errFakeExec) {
+		t.Fatalf("expected exec error, got %v", err)
+	}
+	if got := rec.migrationExecs(); !reflect.DeepEqual(got, []string{"SELECT 1", "FAIL"}) {
+		t.Fatalf("expected execution to stop at failure, got %q", got)
+	}
+	if !rec.rolledBack {
+		t.Fatal("expected rollback")
+	}
+}
+
+var (
+	errFakeExec    = errors.New("fake exec failure")
+	fakeDriverOnce sync.Once
+	fakeRecorders  sync.Map
+)
+
+type execRecorder struct {
+	mu         sync.Mutex
+	execs      []string
+	rolledBack bool
+}
+
+// migrationExecs returns executed statements, minus the runner bookkeeping.
+func (r *execRecorder) migrationExecs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []string
+	for _, query := range r.execs {
+		if strings.Contains(query, "schema_migrations") {
+			continue
+		}
+		out = append(out, query)
+	}
+	return out
+}
+
+func openRecordingDB(t *testing.T) (*sql.DB, *execRecorder) {
+	t.Helper()
+	fakeDriverOnce.Do(func() { sql.Register("migrate-fake", fakeDriver{}) })
+	rec := &execRecorder{}
+	fakeRecorders.Store(t.Name(), rec)
+	db, err := sql.Open("migrate-fake", t.Name())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, rec
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	rec, ok := fakeRecorders.Load(name)
+	if !ok {
+		return nil, errors.New("unknown recorder")
+	}
+	return &fakeConn{rec: rec.(*execRecorder)}, nil
+}
+
+type fakeConn struct{ rec *execRecorder }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return fakeTx{c.rec}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.rec.mu.Lock()
+	defer c.rec.mu.Unlock()
+	c.rec.execs = append(c.rec.execs, query)
+	if query == "FAIL" {
+		return nil, errFakeExec
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return fakeRows{}, nil
+}
+
+type fakeTx struct{ rec *execRecorder }
+
+func (fakeTx) Commit() error { return nil }
+func (tx fakeTx) Rollback() error {
+	tx.rec.mu.Lock()
+	tx.rec.rolledBack = true
+	tx.rec.mu.Unlock()
+	return nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return []string{"version"} }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
@@ -0,0 +1,6 @@
+CREATE TABLE users (
+    id BIGSERIAL PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    amount NUMERIC(10,2),
+    created_at TIMESTAMPTZ
+);
@@ -0,0 +1,74 @@
+package bebo
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const mountParam = "mount"
+
+// Mount delegates every method under prefix to an http.Handler, such as a pprof
+// mux or a metrics handler. The prefix is stripped from the request path before
+// the handler runs; app and route middleware still apply.
+func (a *App) Mount(prefix string, handler http.Handler, middleware ...Middleware) {
+	a.MountMethods([]string{"*"}, prefix, handler, middleware...)
+}
+
+// MountMethods is Mount restricted to methods, so other methods get 405 like
+// regular routes; use it for read-only handlers such as health checks:
+//
+//	app.MountMethods([]string{http.MethodGet}, "/health", registry.Handler())
+func (a *App) MountMethods(methods []string, prefix string, handler http.Handler, middleware ...Middleware) {
+	prefix = cleanPrefix(prefix)
+	for _, method := range methods {
+		a.Handle(method, buildStaticPattern(prefix, mountParam), mountHandler(prefix, handler), middleware...)
+	}
+}
+
+// Mount delegates every method under the group prefix plus prefix to an http.Handler.
+func (g *Group) Mount(prefix string, handler http.Handler, middleware ...Middleware) {
+	g.MountMethods([]string{"*"}, prefix, handler, middleware...)
+}
+
+// MountMethods is Group.Mount restricted to methods.
+func (g *Group) MountMethods(methods []string, prefix string, handler http.Handler, middleware ...Middleware) {
+	fullPrefix := joinPaths(g.prefix, prefix)
+	for _, method := range methods {
+		g.handle(method, buildStaticPattern(prefix, mountParam), mountHandler(fullPrefix, handler), middleware)
+	}
+}
+
+func mountHandler(prefix string, handler http.Handler) Handler {
+	return func(ctx *Context) error {
+		handler.ServeHTTP(ctx.ResponseWriter, stripPrefix(ctx.Request, prefix))
+		return nil
+	}
+}
+
+// stripPrefix returns a shallow copy of r with prefix removed from its path.
+func stripPrefix(r *http.Request, prefix string) *http.Request {
+	if prefix == "" || prefix == "/" {
+		return r
+	}
+	stripped := *r
+	stripped.URL = new(url.URL)
+	*stripped.URL = *r.URL
+	stripped.URL.Path = ensureLeadingSlash(strings.TrimPrefix(r.URL.Path, prefix))
+	if r.URL.RawPath != "" {
+		stripped.URL.RawPath = ensureLeadingSlash(strings.TrimPrefix(r.URL.RawPath, prefix))
+	}
+	return &stripped
+}
+
+// hasPathPrefix reports whether path is prefix or lies beneath it.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func ensureLeadingSlash(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return "/" + path
+	}
+	return path
+}
@@ -0,0 +1,87 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMountStripsPrefix(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path))
+	})
+
+	app := New()
+	var sawMiddleware bool
+	app.Mount("/debug", mux, func(next Handler) Handler {
+		return func(ctx *Context) error {
+			sawMiddleware = true
+			return next(ctx)
+		}
+	})
+
+	cases := map[string]string{
+		"/debug/pprof/heap": "POST /pprof/heap",
+		"/debug":            "POST /",
+	}
+	for path, want := range cases {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		if rec.Body.String() != want {
+			t.Fatalf("%s: expected %q, got %q", path, want, rec.Body.String())
+		}
+	}
+	if !sawMiddleware {
+		t.Fatalf("expected mount middleware to run")
+	}
+}
+
+func TestGroupMount(t *testing.T) {
+	app := New()
+	admin := app.Group("/admin")
+	admin.Mount("/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/metrics/cpu", nil))
+
+	if rec.Body.String() != "/cpu" {
+		t.Fatalf("expected stripped path, got %q", rec.Body.String())
+	}
+}
+
+func TestMountMethods(t *testing.T) {
+	app := New()
+	app.MountMethods([]string{http.MethodGet}, "/health", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok " + r.URL.Path))
+	}))
+	api := app.Group("/api")
+	api.MountMethods([]string{http.MethodGet, http.MethodPost}, "/hooks", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok /" {
+		t.Fatalf("expected mounted GET, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/health", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for unmounted method, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/hooks/github", nil))
+	if rec.Body.String() != "POST /github" {
+		t.Fatalf("expected group mount, got %q", rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/hooks/github", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for group mount, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,138 @@
+package bebo
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+// MultipartLimits caps how many parts a multipart form may contain.
+type MultipartLimits struct {
+	// MaxParts limits the total number of parts, files included. Values above
+	// 1000 also need GODEBUG=multipartmaxparts, which caps the stdlib reader.
+	MaxParts int
+	// MaxFields limits the number of non-file form fields.
+	MaxFields int
+}
+
+// DefaultMultipartLimits returns the limits applied when none are configured.
+func DefaultMultipartLimits() MultipartLimits {
+	return MultipartLimits{MaxParts: 1000, MaxFields: 500}
+}
+
+// WithMultipartLimits sets the part and field limits used by BindMultipart and FormFile.
+func WithMultipartLimits(limits MultipartLimits) Option {
+	return func(app *App) {
+		app.multipartLimits = limits
+	}
+}
+
+func normalizeMultipartLimits(limits MultipartLimits) MultipartLimits {
+	defaults := DefaultMultipartLimits()
+	if limits.MaxParts <= 0 {
+		limits.MaxParts = defaults.MaxParts
+	}
+	if limits.MaxFields <= 0 {
+		limits.MaxFields = defaults.MaxFields
+	}
+	return limits
+}
+
+var (
+	errTooManyParts  = errors.New("too many multipart parts")
+	errTooManyFields = errors.New("too many multipart fields")
+)
+
+// parseMultipart populates Request.MultipartForm like ParseMultipartForm, but
+// streams the body through a multipart.Reader first so the part and field
+// counts are enforced before anything is buffered or spooled to disk.
+func (c *Context) parseMultipart(maxMemory int64) error {
+	r := c.Request
+	if r.MultipartForm != nil {
+		return nil
+	}
+	if maxMemory <= 0 {
+		maxMemory = DefaultMultipartMemory
+	}
+	limits := DefaultMultipartLimits()
+	if c.app != nil {
+		limits = normalizeMultipartLimits(c.app.multipartLimits)
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return apperr.BadRequest("invalid multipart form", err)
+	}
+	source, err := r.MultipartReader()
+	if err != nil {
+		return apperr.BadRequest("invalid multipart form", err)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(copyMultipart(writer, source, limits))
+	}()
+
+	form, err := multipart.NewReader(pr, writer.Boundary()).ReadForm(maxMemory)
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		switch {
+		case errors.Is(err, errTooManyParts), errors.Is(err, errTooManyFields):
+			return apperr.BadRequest(err.Error(), err)
+		case errors.Is(err, multipart.ErrMessageTooLarge):
+			return apperr.PayloadTooLarge("multipart form too large", err)
+		}
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return apperr.PayloadTooLarge("request body too large", err)
+		}
+		return apperr.BadRequest("invalid multipart form", err)
+	}
+
+	if r.PostForm == nil {
+		r.PostForm = make(map[string][]string)
+	}
+	for key, values := range form.Value {
+		r.Form[key] = append(r.Form[key], values...)
+		r.PostForm[key] = append(r.PostForm[key], values...)
+	}
+	r.MultipartForm = form
+	return nil
+}
+
+// copyMultipart re-encodes parts from source into writer, failing as soon as
+// a limit is exceeded.
+func copyMultipart(writer *multipart.Writer, source *multipart.Reader, limits MultipartLimits) error {
+	parts, fields := 0, 0
+	for {
+		part, err := source.NextPart()
+		if err == io.EOF {
+			return writer.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		parts++
+		if parts > limits.MaxParts {
+			return errTooManyParts
+		}
+		if part.FileName() == "" {
+			fields++
+			if fields > limits.MaxFields {
+				return errTooManyFields
+			}
+		}
+
+		dst, err := writer.CreatePart(part.Header)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, part); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package bebo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+func multipartRequest(t *testing.T, fields, files int) *http.Request {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	for i := 0; i < fields; i++ {
+		if err := writer.WriteField(fmt.Sprintf("f%d", i), "v"); err != nil {
+			t.Fatalf("write field: %v", err)
+		}
+	}
+	for i := 0; i < files; i++ {
+		part, err := writer.CreateFormFile(fmt.Sprintf("file%d", i), "a.txt")
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		_, _ = part.Write([]byte("x"))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/?q=1", buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestBindMultipartWithinLimits(t *testing.T) {
+	app := New(WithMultipartLimits(MultipartLimits{MaxParts: 3, MaxFields: 2}))
+	req := multipartRequest(t, 2, 1)
+	ctx := NewContext(httptest.NewRecorder(), req, nil, app)
+
+	var payload struct {
+		F0 string `form:"f0"`
+		F1 string `form:"f1"`
+	}
+	if err := ctx.BindMultipart(&payload, 0); err != nil {
+		t.Fatalf("bind multipart: %v", err)
+	}
+	if payload.F0 != "v" || payload.F1 != "v" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if req.FormValue("f1") != "v" || req.FormValue("q") != "1" {
+		t.Fatalf("expected multipart values merged into Form, got %v", req.Form)
+	}
+
+	header, err := ctx.FormFile("file0", 0)
+	if err != nil {
+		t.Fatalf("form file: %v", err)
+	}
+	if header.Filename != "a.txt" || header.Size != 1 {
+		t.Fatalf("unexpected file header: %+v", header)
+	}
+}
+
+func TestBindMultipartRejectsTooManyParts(t *testing.T) {
+	app := New(WithMultipartLimits(MultipartLimits{MaxParts: 3, MaxFields: 10}))
+	ctx := NewContext(httptest.NewRecorder(), multipartRequest(t, 2, 2), nil, app)
+
+	var payload struct{}
+	err := ctx.BindMultipart(&payload, 0)
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || appErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+	if appErr.Message != "too many multipart parts" {
+		t.Fatalf("unexpected message: %s", appErr.Message)
+	}
+}
+
+func TestFormFileRejectsTooManyFields(t *testing.T) {
+	app := New(WithMultipartLimits(MultipartLimits{MaxFields: 5}))
+	ctx := NewContext(httptest.NewRecorder(), multipartRequest(t, 6, 1), nil, app)
+
+	_, err := ctx.FormFile("file0", 0)
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || appErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+	if appErr.Message != "too many multipart fields" {
+		t.Fatalf("unexpected message: %s", appErr.Message)
+	}
+}
+
+func TestBindMultipartDefaultLimits(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), multipartRequest(t, DefaultMultipartLimits().MaxFields+1, 0), nil, New())
+
+	var payload struct{}
+	if err := ctx.BindMultipart(&payload, 0); err == nil {
+		t.Fatal("expected default field limit to apply")
+	}
+}
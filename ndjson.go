@@ -0,0 +1,32 @@
+package bebo
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// NDJSON streams newline-delimited JSON. emit calls write once per value;
+// each value is encoded on its own line and flushed so clients can process
+// records as they arrive. An error from emit or from encoding stops the
+// stream and is returned.
+func (c *Context) NDJSON(status int, emit func(write func(v any) error) error) error {
+	w := c.ResponseWriter
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(status)
+
+	flusher := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	write := func(v any) error {
+		if err := encoder.Encode(c.jsonPayload(v)); err != nil {
+			return err
+		}
+		// Writers that cannot flush (http.ErrNotSupported) still stream
+		// once their buffer fills.
+		_ = flusher.Flush()
+		return nil
+	}
+	if emit == nil {
+		return nil
+	}
+	return emit(write)
+}
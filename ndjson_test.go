@@ -0,0 +1,87 @@
+package bebo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContextNDJSON(t *testing.T) {
+	type event struct {
+		ID      int    `json:"id"`
+		Message string `json:"message"`
+	}
+
+	app := New()
+	app.GET("/events", func(ctx *Context) error {
+		return ctx.NDJSON(http.StatusOK, func(write func(v any) error) error {
+			for i := 1; i <= 3; i++ {
+				if err := write(event{ID: i, Message: "line\nbreak"}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if !rec.Flushed {
+		t.Fatalf("expected the stream to be flushed")
+	}
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), rec.Body.String())
+	}
+	for i, line := range lines {
+		var decoded event
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if decoded.ID != i+1 || decoded.Message != "line\nbreak" {
+			t.Fatalf("unexpected line %d: %+v", i, decoded)
+		}
+	}
+}
+
+func TestContextNDJSONStopsOnError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := NewContext(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil, nil)
+
+	failure := errors.New("cursor closed")
+	calls := 0
+	err := ctx.NDJSON(http.StatusOK, func(write func(v any) error) error {
+		for i := 0; i < 5; i++ {
+			calls++
+			if i == 1 {
+				return failure
+			}
+			if err := write(map[string]int{"n": i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if calls != 2 || rec.Body.String() != "{\"n\":0}\n" {
+		t.Fatalf("expected stream to stop after the error, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	ctx = NewContext(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil, nil)
+	err = ctx.NDJSON(http.StatusOK, func(write func(v any) error) error {
+		return write(func() {})
+	})
+	if err == nil {
+		t.Fatalf("expected encoding error")
+	}
+}
@@ -0,0 +1,120 @@
+package bebo
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	mimeJSON = "application/json"
+	mimeHTML = "text/html"
+)
+
+// mediaRange is one entry of an Accept header, such as "text/*;q=0.5".
+type mediaRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// NegotiateContentType picks the offer the Accept header ranks highest.
+// Each offer uses the q-value of its most specific matching range, so
+// "text/html" beats "text/*" and "*/*". Ties go to the earlier offer, an
+// empty header accepts the first offer, and "" means nothing is acceptable.
+func NegotiateContentType(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+	best := ""
+	bestQ := 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(ranges, offer); q > bestQ {
+			best = offer
+			bestQ = q
+		}
+	}
+	return best
+}
+
+// PrefersJSON reports whether the request ranks JSON at least as high as
+// HTML. Requests without an Accept header, or accepting "*/*", prefer JSON.
+func PrefersJSON(r *http.Request) bool {
+	return NegotiateContentType(r.Header.Get("Accept"), mimeJSON, mimeHTML) == mimeJSON
+}
+
+// PrefersHTML reports whether the request ranks HTML above JSON.
+func PrefersHTML(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return strings.TrimSpace(accept) != "" && NegotiateContentType(accept, mimeJSON, mimeHTML) == mimeHTML
+}
+
+// Negotiate returns the offered content type the client prefers, or "" when
+// none is acceptable. See NegotiateContentType.
+func (c *Context) Negotiate(offers ...string) string {
+	return NegotiateContentType(c.Request.Header.Get("Accept"), offers...)
+}
+
+func parseAccept(accept string) []mediaRange {
+	ranges := make([]mediaRange, 0, 4)
+	for _, part := range strings.Split(accept, ",") {
+		pieces := strings.Split(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(pieces[0])), "/")
+		if !ok {
+			if typ != "*" {
+				continue
+			}
+			subtype = "*"
+		}
+		item := mediaRange{typ: strings.TrimSpace(typ), subtype: strings.TrimSpace(subtype), q: 1}
+		if item.typ == "" || item.subtype == "" {
+			continue
+		}
+		for _, param := range pieces[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || parsed < 0 {
+				parsed = 0
+			}
+			item.q = min(parsed, 1)
+		}
+		ranges = append(ranges, item)
+	}
+	return ranges
+}
+
+// acceptQuality returns the q-value of the most specific range matching
+// offer, or 0 when no range matches.
+func acceptQuality(ranges []mediaRange, offer string) float64 {
+	base, _, _ := strings.Cut(offer, ";")
+	typ, subtype, _ := strings.Cut(strings.ToLower(strings.TrimSpace(base)), "/")
+
+	q := 0.0
+	specificity := -1
+	for _, item := range ranges {
+		level := 0
+		switch {
+		case item.typ == typ && item.subtype == subtype:
+			level = 2
+		case item.typ == typ && item.subtype == "*":
+			level = 1
+		case item.typ == "*" && item.subtype == "*":
+			level = 0
+		default:
+			continue
+		}
+		if level > specificity {
+			specificity = level
+			q = item.q
+		}
+	}
+	return q
+}
@@ -0,0 +1,92 @@
+package bebo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	offers := []string{"application/json", "text/html"}
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"text/html", "text/html"},
+		{"text/html;q=0.9,application/json;q=0.8", "text/html"},
+		{"application/json;q=0", ""},
+		{"application/json;q=0,*/*", "text/html"},
+		{"application/json;q=0,text/html;q=0", ""},
+		{"text/*;q=0.5,application/json;q=0.4", "text/html"},
+		{"text/html;q=0.2,*/*", "application/json"},
+		{"*/*;q=0.8,text/html;q=0", "application/json"},
+		{"image/png", ""},
+	}
+	for _, tc := range cases {
+		if got := NegotiateContentType(tc.accept, offers...); got != tc.want {
+			t.Errorf("Accept %q: expected %q, got %q", tc.accept, tc.want, got)
+		}
+	}
+}
+
+func TestPrefersJSONAndHTML(t *testing.T) {
+	cases := []struct {
+		accept string
+		json   bool
+		html   bool
+	}{
+		{"", true, false},
+		{"*/*", true, false},
+		{"text/html;q=0.9,application/json;q=0.8", false, true},
+		{"application/json;q=0", false, false},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false, true},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.accept != "" {
+			req.Header.Set("Accept", tc.accept)
+		}
+		if got := PrefersJSON(req); got != tc.json {
+			t.Errorf("Accept %q: PrefersJSON expected %v, got %v", tc.accept, tc.json, got)
+		}
+		if got := PrefersHTML(req); got != tc.html {
+			t.Errorf("Accept %q: PrefersHTML expected %v, got %v", tc.accept, tc.html, got)
+		}
+	}
+}
+
+func TestContextNegotiate(t *testing.T) {
+	app := New()
+	app.GET("/", func(ctx *Context) error {
+		return ctx.Text(http.StatusOK, ctx.Negotiate("text/csv", "application/json"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/json;q=0.5,text/csv")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "text/csv" {
+		t.Fatalf("expected text/csv, got %q", rec.Body.String())
+	}
+}
+
+func TestErrorResponseRespectsQValues(t *testing.T) {
+	app := New()
+	app.GET("/", func(ctx *Context) error {
+		return apperr.NotFound("missing", nil)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html;q=0.9,application/json;q=0.8")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct == "" || ct[:9] != "text/html" {
+		t.Fatalf("expected HTML error page, got %q", ct)
+	}
+}
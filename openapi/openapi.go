@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"reflect"
 	"strings"
 )
 
@@ -57,14 +58,15 @@ type SecurityScheme struct {
 
 // Schema describes a data schema.
 type Schema struct {
-	Ref         string            `json:"$ref,omitempty"`
-	Type        string            `json:"type,omitempty"`
-	Format      string            `json:"format,omitempty"`
-	Description string            `json:"description,omitempty"`
-	Properties  map[string]Schema `json:"properties,omitempty"`
-	Items       *Schema           `json:"items,omitempty"`
-	Required    []string          `json:"required,omitempty"`
-	Enum        []string          `json:"enum,omitempty"`
+	Ref                  string            `json:"$ref,omitempty"`
+	Type                 string            `json:"type,omitempty"`
+	Format               string            `json:"format,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Properties           map[string]Schema `json:"properties,omitempty"`
+	Items                *Schema           `json:"items,omitempty"`
+	AdditionalProperties *Schema           `json:"additionalProperties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+	Enum                 []string          `json:"enum,omitempty"`
 }
 
 // PathItem describes available operations on a path.
@@ -120,6 +122,22 @@ type Response struct {
 	Content     map[string]MediaType `json:"content,omitempty"`
 }
 
+// JSONRequestBody returns a required application/json request body.
+func JSONRequestBody(schema Schema) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{"application/json": {Schema: &schema}},
+	}
+}
+
+// JSONResponse returns a response with an application/json body.
+func JSONResponse(description string, schema Schema) Response {
+	return Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: &schema}},
+	}
+}
+
 // Header describes a response header.
 type Header struct {
 	Description string  `json:"description,omitempty"`
@@ -129,6 +147,8 @@ type Header struct {
 // Builder helps compose a Document.
 type Builder struct {
 	doc Document
+	// typeNames maps Go types to their component schema names.
+	typeNames map[reflect.Type]string
 }
 
 // New creates a Builder with default OpenAPI version.
@@ -1,6 +1,13 @@
 package openapi
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestBuilderAddRoute(t *testing.T) {
 	builder := New(Info{Title: "bebo", Version: "0.1"})
@@ -21,3 +28,189 @@ func TestBuilderUnsupportedMethod(t *testing.T) {
 		t.Fatalf("expected unsupported method error")
 	}
 }
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type testAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testUser struct {
+	testAudit
+	ID       int64          `json:"id,string"`
+	Name     string         `json:"name" validate:"required,min=2"`
+	Email    *string        `json:"email,omitempty" validate:"email"`
+	Tags     []string       `json:"tags" validate:"dive,required"`
+	Labels   map[string]int `json:"labels"`
+	Address  testAddress    `json:"address"`
+	Manager  *testUser      `json:"manager,omitempty"`
+	Avatar   []byte         `json:"avatar"`
+	Password string         `json:"-"`
+	Score    float64
+	internal bool
+}
+
+func TestAddSchemaFromType(t *testing.T) {
+	builder := New(Info{Title: "bebo", Version: "0.1"})
+	ref := builder.AddSchemaFromType("User", reflect.TypeFor[*testUser]())
+	if ref.Ref != "#/components/schemas/User" {
+		t.Fatalf("unexpected ref %q", ref.Ref)
+	}
+
+	schemas := builder.Document().Components.Schemas
+	user := schemas["User"]
+	if user.Type != "object" {
+		t.Fatalf("expected object schema, got %+v", user)
+	}
+	if !reflect.DeepEqual(user.Required, []string{"name"}) {
+		t.Fatalf("expected only name to be required, got %v", user.Required)
+	}
+
+	expect := map[string]Schema{
+		"created_at": {Type: "string", Format: "date-time"},
+		"id":         {Type: "string"},
+		"name":       {Type: "string"},
+		"email":      {Type: "string"},
+		"avatar":     {Type: "string", Format: "byte"},
+		"Score":      {Type: "number", Format: "double"},
+		"address":    {Ref: "#/components/schemas/testAddress"},
+		"manager":    {Ref: "#/components/schemas/User"},
+	}
+	for name, want := range expect {
+		if got := user.Properties[name]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("property %s: expected %+v, got %+v", name, want, got)
+		}
+	}
+	if tags := user.Properties["tags"]; tags.Type != "array" || tags.Items.Type != "string" {
+		t.Fatalf("unexpected tags schema %+v", tags)
+	}
+	if labels := user.Properties["labels"]; labels.Type != "object" || labels.AdditionalProperties.Format != "int64" {
+		t.Fatalf("unexpected labels schema %+v", labels)
+	}
+	for _, hidden := range []string{"Password", "internal", "testAudit"} {
+		if _, ok := user.Properties[hidden]; ok {
+			t.Fatalf("expected %s to be omitted", hidden)
+		}
+	}
+
+	address := schemas["testAddress"]
+	if address.Properties["city"].Type != "string" || !reflect.DeepEqual(address.Required, []string{"city"}) {
+		t.Fatalf("unexpected nested schema %+v", address)
+	}
+}
+
+func TestSchemaForInlinesUnnamedTypes(t *testing.T) {
+	builder := New(Info{Title: "bebo", Version: "0.1"})
+	schema := builder.SchemaFor(reflect.TypeFor[[]testAddress]())
+	if schema.Type != "array" || schema.Items.Ref != "#/components/schemas/testAddress" {
+		t.Fatalf("unexpected schema %+v", schema)
+	}
+
+	inline := builder.SchemaFor(reflect.TypeFor[struct {
+		OK bool `json:"ok"`
+	}]())
+	if inline.Type != "object" || inline.Properties["ok"].Type != "boolean" {
+		t.Fatalf("expected inline object, got %+v", inline)
+	}
+}
+
+func TestSwaggerUI(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SwaggerUI(`/openapi.json?v="1"`).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "SwaggerUIBundle") {
+		t.Fatalf("expected swagger ui bundle, got %s", body)
+	}
+	if !strings.Contains(body, `url: "/openapi.json?v=\"1\""`) {
+		t.Fatalf("expected escaped spec url, got %s", body)
+	}
+}
+
+func TestRedoc(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Redoc("/openapi.json").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/redoc", nil))
+
+	if !strings.Contains(rec.Body.String(), `<redoc spec-url="/openapi.json">`) {
+		t.Fatalf("expected redoc element, got %s", rec.Body.String())
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	builder := New(Info{Title: "bebo: api", Version: "1.0"})
+	builder.AddServer(Server{URL: "/api"})
+	if err := builder.AddRoute("GET", "/users/{id}", Operation{
+		Summary:    "show user",
+		Tags:       []string{"users", "yes"},
+		Parameters: []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}},
+		Responses:  map[string]Response{"200": JSONResponse("ok", Schema{Ref: "#/components/schemas/User"})},
+	}); err != nil {
+		t.Fatalf("add route: %v", err)
+	}
+	builder.AddSchema("User", Schema{
+		Type:                 "object",
+		Properties:           map[string]Schema{"id": {Type: "integer"}},
+		AdditionalProperties: &Schema{},
+	})
+
+	out, err := builder.MarshalYAML()
+	if err != nil {
+		t.Fatalf("marshal yaml: %v", err)
+	}
+	want := `openapi: "3.0.3"
+info:
+  title: "bebo: api"
+  version: "1.0"
+servers:
+  - url: /api
+paths:
+  /users/{id}:
+    get:
+      summary: show user
+      tags:
+        - users
+        - "yes"
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: integer
+      additionalProperties: {}
+`
+	if string(out) != want {
+		t.Fatalf("unexpected yaml:\n%s", out)
+	}
+}
+
+func TestHandlerYAML(t *testing.T) {
+	builder := New(Info{Title: "bebo", Version: "0.1"})
+	rec := httptest.NewRecorder()
+	builder.HandlerYAML().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/yaml; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "openapi: \"3.0.3\"\ninfo:\n  title: bebo\n") {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+}
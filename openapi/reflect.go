@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+	invalidComponentChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// AddSchemaFromType derives a schema from t, registers it in components
+// under name, and returns a reference to it. Struct properties follow `json`
+// tags, and fields whose `validate` tag includes "required" are required.
+// Named struct types reached from t are registered under their Go names.
+func (b *Builder) AddSchemaFromType(name string, t reflect.Type) Schema {
+	t = indirectType(t)
+	if b.typeNames == nil {
+		b.typeNames = map[reflect.Type]string{}
+	}
+	if t.Kind() == reflect.Struct {
+		if _, ok := b.typeNames[t]; !ok {
+			b.typeNames[t] = name
+		}
+	}
+	b.AddSchema(name, b.schemaFor(t, true))
+	return Schema{Ref: componentRef(name)}
+}
+
+// SchemaFor returns the schema for t, registering named struct types as
+// components and referencing them.
+func (b *Builder) SchemaFor(t reflect.Type) Schema {
+	return b.schemaFor(t, false)
+}
+
+func (b *Builder) schemaFor(t reflect.Type, inline bool) Schema {
+	t = indirectType(t)
+	switch {
+	case t == timeType:
+		return Schema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Custom JSON encodings can produce any shape.
+		return Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return Schema{Type: "string", Format: "byte"}
+		}
+		items := b.schemaFor(t.Elem(), false)
+		return Schema{Type: "array", Items: &items}
+	case reflect.Map:
+		values := b.schemaFor(t.Elem(), false)
+		return Schema{Type: "object", AdditionalProperties: &values}
+	case reflect.Struct:
+		if inline || t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return Schema{Ref: componentRef(b.registerType(t))}
+	}
+	return Schema{}
+}
+
+// registerType adds a component for a named struct type, once. The name is
+// reserved before the fields are walked so recursive types terminate.
+func (b *Builder) registerType(t reflect.Type) string {
+	if b.typeNames == nil {
+		b.typeNames = map[reflect.Type]string{}
+	}
+	if name, ok := b.typeNames[t]; ok {
+		return name
+	}
+	name := b.componentName(t)
+	b.typeNames[t] = name
+	b.AddSchema(name, b.structSchema(t))
+	return name
+}
+
+// componentName uses the Go type name, qualified by package when another
+// type already claimed it.
+func (b *Builder) componentName(t reflect.Type) string {
+	name := invalidComponentChars.ReplaceAllString(t.Name(), "_")
+	if b.componentTaken(name) {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	return name
+}
+
+func (b *Builder) componentTaken(name string) bool {
+	for _, taken := range b.typeNames {
+		if taken == name {
+			return true
+		}
+	}
+	if b.doc.Components != nil {
+		_, ok := b.doc.Components.Schemas[name]
+		return ok
+	}
+	return false
+}
+
+func (b *Builder) structSchema(t reflect.Type) Schema {
+	schema := Schema{Type: "object", Properties: map[string]Schema{}}
+	b.addFields(&schema, t)
+	return schema
+}
+
+func (b *Builder) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := indirectType(field.Type)
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.schemaFor(field.Type, false)
+		if hasOption(options, "string") {
+			property = Schema{Type: "string"}
+		}
+		schema.Properties[name] = property
+		if FieldRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// FieldRequired reports whether field's `validate` tag requires the field
+// itself; rules after "dive" apply to elements.
+func FieldRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		switch strings.TrimSpace(rule) {
+		case "required":
+			return true
+		case "dive":
+			return false
+		}
+	}
+	return false
+}
+
+func hasOption(options, option string) bool {
+	for _, current := range strings.Split(options, ",") {
+		if current == option {
+			return true
+		}
+	}
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func componentRef(name string) string {
+	return "#/components/schemas/" + name
+}
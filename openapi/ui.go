@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+)
+
+// The pages load their assets from jsDelivr; a Content-Security-Policy on
+// these routes must allow https://cdn.jsdelivr.net for scripts and styles.
+var (
+	swaggerUITemplate = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>API Docs</title>
+<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.}}, dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`))
+
+	redocTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>API Docs</title>
+</head>
+<body>
+<redoc spec-url="{{.}}"></redoc>
+<script src="https://cdn.jsdelivr.net/npm/redoc@2/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`))
+)
+
+// SwaggerUI returns a handler serving a Swagger UI page for the spec at
+// specURL.
+func SwaggerUI(specURL string) http.Handler {
+	return docsPage(swaggerUITemplate, specURL)
+}
+
+// Redoc returns a handler serving a Redoc page for the spec at specURL.
+func Redoc(specURL string) http.Handler {
+	return docsPage(redocTemplate, specURL)
+}
+
+func docsPage(page *template.Template, specURL string) http.Handler {
+	var buf bytes.Buffer
+	if err := page.Execute(&buf, specURL); err != nil {
+		panic(err)
+	}
+	body := buf.Bytes()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(body)
+	})
+}
@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// MarshalYAML encodes the built document as YAML.
+func (b *Builder) MarshalYAML() ([]byte, error) {
+	return MarshalYAML(&b.doc)
+}
+
+// HandlerYAML returns an HTTP handler that serves the builder document as YAML.
+func (b *Builder) HandlerYAML() http.Handler {
+	return HandlerYAML(&b.doc)
+}
+
+// MarshalYAML encodes doc as YAML. The document is converted from its JSON
+// form, so field names and order match the JSON output.
+func MarshalYAML(doc *Document) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeOrdered(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeYAML(&buf, value, 0)
+	return buf.Bytes(), nil
+}
+
+// HandlerYAML returns an HTTP handler that serves the document as YAML.
+func HandlerYAML(doc *Document) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if doc == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := MarshalYAML(doc)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		_, _ = w.Write(body)
+	})
+}
+
+// yamlMap is a JSON object with its key order preserved.
+type yamlMap struct {
+	keys   []string
+	values []any
+}
+
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		object := &yamlMap{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.keys = append(object.keys, key.(string))
+			object.values = append(object.values, value)
+		}
+		_, err := decoder.Token()
+		return object, err
+	case json.Delim('['):
+		list := []any{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := decoder.Token()
+		return list, err
+	case json.Delim('}'), json.Delim(']'):
+		return nil, errors.New("openapi: unexpected json delimiter")
+	}
+	return token, nil
+}
+
+// writeYAML writes a block-style mapping or sequence at indent, or a
+// scalar. Callers place scalars after "key: " or "- ".
+func writeYAML(buf *bytes.Buffer, value any, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch v := value.(type) {
+	case *yamlMap:
+		for i, key := range v.keys {
+			buf.WriteString(pad)
+			buf.WriteString(yamlScalar(key))
+			buf.WriteByte(':')
+			writeYAMLChild(buf, v.values[i], indent)
+		}
+	case []any:
+		for _, item := range v {
+			buf.WriteString(pad)
+			buf.WriteByte('-')
+			if object, ok := item.(*yamlMap); ok && len(object.keys) > 0 {
+				// The first key shares the dash's line; the rest align with it.
+				var nested bytes.Buffer
+				writeYAML(&nested, object, indent+1)
+				buf.WriteByte(' ')
+				buf.Write(bytes.TrimPrefix(nested.Bytes(), []byte(pad+"  ")))
+				continue
+			}
+			writeYAMLChild(buf, item, indent)
+		}
+	default:
+		buf.WriteString(pad)
+		buf.WriteString(yamlValue(v))
+		buf.WriteByte('\n')
+	}
+}
+
+// writeYAMLChild writes value after a "key:" or "-" already on the line.
+func writeYAMLChild(buf *bytes.Buffer, value any, indent int) {
+	switch v := value.(type) {
+	case *yamlMap:
+		if len(v.keys) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, v, indent+1)
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, v, indent+1)
+	default:
+		buf.WriteByte(' ')
+		buf.WriteString(yamlValue(v))
+		buf.WriteByte('\n')
+	}
+}
+
+func yamlValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlScalar(v)
+	}
+	return "null"
+}
+
+var plainYAML = regexp.MustCompile(`^[A-Za-z_/$][A-Za-z0-9_./${} -]*$`)
+
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+// yamlScalar leaves simple strings plain and double-quotes the rest,
+// including anything YAML would read as a number, boolean, or null.
+func yamlScalar(value string) string {
+	if plainYAML.MatchString(value) && !strings.HasSuffix(value, " ") && !yamlReserved[strings.ToLower(value)] {
+		return value
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -1,10 +1,17 @@
 package bebo
 
 import (
+	"encoding/json"
 	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/devmarvs/bebo/apperr"
 	"github.com/devmarvs/bebo/openapi"
 )
 
@@ -14,6 +21,16 @@ type OpenAPIOptions struct {
 	SkipPaths      []string
 	SkipMethods    []string
 	TagFromHost    bool
+	// Rebuild makes ServeOpenAPI regenerate the document on every request,
+	// for development. By default it is built once, on the first request.
+	Rebuild bool
+	// Security maps route patterns (exact, or prefixes ending in "*" as in
+	// SkipPaths) to the security scheme their operations require. The most
+	// specific matching pattern wins; routes using WithSecurity keep theirs.
+	Security map[string]string
+	// SecuritySchemes are registered in the document's components. Schemes
+	// referenced by routes but never registered default to HTTP bearer.
+	SecuritySchemes map[string]openapi.SecurityScheme
 }
 
 // OpenAPIOption customizes OpenAPI route derivation.
@@ -52,6 +69,103 @@ func WithOpenAPITagFromHost(enabled bool) OpenAPIOption {
 	}
 }
 
+// WithOpenAPIRebuild regenerates the served document per request (see ServeOpenAPI).
+func WithOpenAPIRebuild(enabled bool) OpenAPIOption {
+	return func(options *OpenAPIOptions) {
+		options.Rebuild = enabled
+	}
+}
+
+// WithOpenAPISecurity makes operations under pattern (e.g. "/api/*") require
+// the named security scheme.
+func WithOpenAPISecurity(pattern, scheme string) OpenAPIOption {
+	return func(options *OpenAPIOptions) {
+		if options.Security == nil {
+			options.Security = map[string]string{}
+		}
+		options.Security[pattern] = scheme
+	}
+}
+
+// WithOpenAPISecurityScheme registers a security scheme in the document's
+// components.
+func WithOpenAPISecurityScheme(name string, scheme openapi.SecurityScheme) OpenAPIOption {
+	return func(options *OpenAPIOptions) {
+		if options.SecuritySchemes == nil {
+			options.SecuritySchemes = map[string]openapi.SecurityScheme{}
+		}
+		options.SecuritySchemes[name] = scheme
+	}
+}
+
+// ServeOpenAPI serves a JSON OpenAPI document derived from the app's routes
+// at path. The document is built with AddOpenAPIRoutes on the first request,
+// so routes registered later are included, and cached unless
+// WithOpenAPIRebuild is set. The spec route itself is left out.
+func (a *App) ServeOpenAPI(path string, info openapi.Info, options ...OpenAPIOption) {
+	cfg := DefaultOpenAPIOptions()
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	options = append(append([]OpenAPIOption{}, options...), WithOpenAPISkipPaths(append(cfg.SkipPaths, path)...))
+
+	var (
+		mu     sync.Mutex
+		cached []byte
+	)
+	build := func() ([]byte, error) {
+		builder := openapi.New(info)
+		if err := a.AddOpenAPIRoutes(builder, options...); err != nil {
+			return nil, err
+		}
+		return json.Marshal(builder.Document())
+	}
+
+	a.GET(path, func(ctx *Context) error {
+		mu.Lock()
+		body := cached
+		if body == nil || cfg.Rebuild {
+			var err error
+			body, err = build()
+			if err != nil {
+				mu.Unlock()
+				return apperr.Internal("openapi build failed", err)
+			}
+			if !cfg.Rebuild {
+				cached = body
+			}
+		}
+		mu.Unlock()
+
+		ctx.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+		ctx.ResponseWriter.WriteHeader(http.StatusOK)
+		_, err := ctx.ResponseWriter.Write(body)
+		return err
+	})
+}
+
+// OpenAPIDocs serves builder's document as JSON at jsonPath and a Swagger UI
+// page loading it at uiPath. The UI pulls its assets from a CDN, so a strict
+// Content-Security-Policy must allow it on uiPath.
+func (a *App) OpenAPIDocs(jsonPath, uiPath string, builder *openapi.Builder) {
+	if builder == nil {
+		a.logger.Error("openapi builder is required", slog.String("path", jsonPath))
+		return
+	}
+
+	spec := builder.Handler()
+	a.GET(jsonPath, func(ctx *Context) error {
+		spec.ServeHTTP(ctx.ResponseWriter, ctx.Request)
+		return nil
+	})
+
+	ui := openapi.SwaggerUI(a.basePath + jsonPath)
+	a.GET(uiPath, func(ctx *Context) error {
+		ui.ServeHTTP(ctx.ResponseWriter, ctx.Request)
+		return nil
+	})
+}
+
 // AddOpenAPIRoutes derives OpenAPI operations from registered routes.
 func (a *App) AddOpenAPIRoutes(builder *openapi.Builder, options ...OpenAPIOption) error {
 	if builder == nil {
@@ -68,8 +182,15 @@ func (a *App) AddOpenAPIRoutes(builder *openapi.Builder, options ...OpenAPIOptio
 		skipMethods[strings.ToUpper(strings.TrimSpace(method))] = struct{}{}
 	}
 
-	routes := a.RoutesAll()
-	for _, route := range routes {
+	if a.basePath != "" && len(builder.Document().Servers) == 0 {
+		builder.AddServer(openapi.Server{URL: a.basePath})
+	}
+	for name, scheme := range cfg.SecuritySchemes {
+		builder.AddSecurityScheme(name, scheme)
+	}
+
+	for _, entry := range a.sortedRoutes() {
+		route := entry.info()
 		if route.Method == "*" {
 			continue
 		}
@@ -96,6 +217,9 @@ func (a *App) AddOpenAPIRoutes(builder *openapi.Builder, options ...OpenAPIOptio
 		if cfg.TagFromHost && route.Host != "" {
 			operation.Tags = []string{route.Host}
 		}
+		applyOpenAPISchemas(builder, &operation, entry.schemas)
+		operation.Security = routeSecurity(entry, cfg.Security)
+		registerSecuritySchemes(builder, operation.Security)
 
 		if err := builder.AddRoute(route.Method, path, operation); err != nil {
 			return err
@@ -105,32 +229,141 @@ func (a *App) AddOpenAPIRoutes(builder *openapi.Builder, options ...OpenAPIOptio
 	return nil
 }
 
+// applyOpenAPISchemas attaches the route's documented query and body types.
+func applyOpenAPISchemas(builder *openapi.Builder, operation *openapi.Operation, schemas routeSchemas) {
+	if schemas.query != nil {
+		operation.Parameters = append(operation.Parameters, queryParameters(builder, schemas.query)...)
+	}
+	if schemas.request != nil {
+		schema := builder.SchemaFor(schemas.request)
+		operation.RequestBody = openapi.JSONRequestBody(schema)
+	}
+	if len(schemas.responses) == 0 {
+		return
+	}
+	operation.Responses = make(map[string]openapi.Response, len(schemas.responses))
+	for status, t := range schemas.responses {
+		description := strings.ToLower(http.StatusText(status))
+		if t == nil {
+			operation.Responses[strconv.Itoa(status)] = openapi.Response{Description: description}
+			continue
+		}
+		operation.Responses[strconv.Itoa(status)] = openapi.JSONResponse(description, builder.SchemaFor(t))
+	}
+}
+
+// routeSecurity returns the route's own security requirements, or the scheme
+// of the most specific pattern in rules matching its pattern.
+func routeSecurity(entry *routeEntry, rules map[string]string) []map[string][]string {
+	if len(entry.security) > 0 {
+		return entry.security
+	}
+
+	best, bestLen := "", -1
+	for pattern, scheme := range rules {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || !matchesPathPattern(entry.pattern, pattern) {
+			continue
+		}
+		// An exact pattern outranks a prefix of the same length.
+		length := 2 * len(strings.TrimSuffix(pattern, "*"))
+		if !strings.HasSuffix(pattern, "*") {
+			length++
+		}
+		if length > bestLen || (length == bestLen && scheme < best) {
+			best, bestLen = scheme, length
+		}
+	}
+	if bestLen < 0 || best == "" {
+		return nil
+	}
+	return []map[string][]string{{best: {}}}
+}
+
+// registerSecuritySchemes adds an HTTP bearer scheme for each referenced
+// scheme missing from the document's components.
+func registerSecuritySchemes(builder *openapi.Builder, security []map[string][]string) {
+	for _, requirement := range security {
+		for name := range requirement {
+			if components := builder.Document().Components; components != nil {
+				if _, ok := components.SecuritySchemes[name]; ok {
+					continue
+				}
+			}
+			builder.AddSecurityScheme(name, openapi.SecurityScheme{Type: "http", Scheme: "bearer"})
+		}
+	}
+}
+
 // RoutesAll returns metadata for all registered routes.
 func (a *App) RoutesAll() []RouteInfo {
-	items := make([]RouteInfo, 0, len(a.routes))
+	entries := a.sortedRoutes()
+	items := make([]RouteInfo, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, entry.info())
+	}
+	return items
+}
+
+// sortedRoutes returns route entries ordered by pattern, method, host, and name.
+func (a *App) sortedRoutes() []*routeEntry {
+	entries := make([]*routeEntry, 0, len(a.routes))
 	for _, entry := range a.routes {
-		items = append(items, RouteInfo{
-			Name:    entry.name,
-			Method:  entry.method,
-			Host:    entry.host,
-			Pattern: entry.pattern,
-		})
+		entries = append(entries, entry)
 	}
 
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].Pattern == items[j].Pattern {
-			if items[i].Method == items[j].Method {
-				if items[i].Host == items[j].Host {
-					return items[i].Name < items[j].Name
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].pattern == entries[j].pattern {
+			if entries[i].method == entries[j].method {
+				if entries[i].host == entries[j].host {
+					return entries[i].name < entries[j].name
 				}
-				return items[i].Host < items[j].Host
+				return entries[i].host < entries[j].host
 			}
-			return items[i].Method < items[j].Method
+			return entries[i].method < entries[j].method
 		}
-		return items[i].Pattern < items[j].Pattern
+		return entries[i].pattern < entries[j].pattern
 	})
 
-	return items
+	return entries
+}
+
+func (e *routeEntry) info() RouteInfo {
+	return RouteInfo{Name: e.name, Method: e.method, Host: e.host, Pattern: e.pattern}
+}
+
+// queryParameters lists the fields BindQuery would fill in t.
+func queryParameters(builder *openapi.Builder, t reflect.Type) []openapi.Parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []openapi.Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isEmbeddedStruct(field) {
+			params = append(params, queryParameters(builder, field.Type)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name, _ := queryFieldName(field)
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := builder.SchemaFor(field.Type)
+		params = append(params, openapi.Parameter{
+			Name:     name,
+			In:       "query",
+			Required: openapi.FieldRequired(field),
+			Schema:   &schema,
+		})
+	}
+	return params
 }
 
 func openAPIPath(pattern string) (string, []openapi.Parameter) {
@@ -198,16 +431,18 @@ func shouldSkipPath(path string, patterns []string) bool {
 		if pattern == "" {
 			continue
 		}
-		if strings.HasSuffix(pattern, "*") {
-			prefix := strings.TrimSuffix(pattern, "*")
-			if strings.HasPrefix(path, prefix) {
-				return true
-			}
-			continue
-		}
-		if path == pattern {
+		if matchesPathPattern(path, pattern) {
 			return true
 		}
 	}
 	return false
 }
+
+// matchesPathPattern reports whether path equals pattern or, for a pattern
+// ending in "*", starts with the rest of it.
+func matchesPathPattern(path, pattern string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == pattern
+}
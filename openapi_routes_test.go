@@ -1,7 +1,11 @@
 package bebo
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/devmarvs/bebo/openapi"
@@ -42,3 +46,249 @@ func TestAddOpenAPIRoutesSkipUnnamed(t *testing.T) {
 		t.Fatalf("expected unnamed route to be skipped")
 	}
 }
+
+func TestAddOpenAPIRoutesSchemas(t *testing.T) {
+	type createUser struct {
+		Name string `json:"name" validate:"required"`
+	}
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	app := New()
+	app.Route(http.MethodPost, "/users", func(*Context) error { return nil },
+		WithName("user.create"),
+		WithRequestSchema(createUser{}),
+		WithResponseSchema(http.StatusCreated, &user{}),
+		WithResponseSchema(http.StatusUnprocessableEntity, nil),
+	)
+	app.Route(http.MethodGet, "/users", func(*Context) error { return nil },
+		WithResponseSchema(http.StatusOK, []user{}))
+
+	builder := openapi.New(openapi.Info{Title: "bebo", Version: "v0.1"})
+	if err := app.AddOpenAPIRoutes(builder); err != nil {
+		t.Fatalf("add openapi routes: %v", err)
+	}
+
+	create := builder.Document().Paths["/users"].Post
+	body := create.RequestBody
+	if body == nil || !body.Required {
+		t.Fatalf("expected required request body, got %+v", body)
+	}
+	if ref := body.Content["application/json"].Schema.Ref; ref != "#/components/schemas/createUser" {
+		t.Fatalf("unexpected request schema ref %q", ref)
+	}
+	if len(create.Responses) != 2 {
+		t.Fatalf("expected documented responses to replace defaults, got %v", create.Responses)
+	}
+	created := create.Responses["201"]
+	if created.Description != "created" || created.Content["application/json"].Schema.Ref != "#/components/schemas/user" {
+		t.Fatalf("unexpected 201 response %+v", created)
+	}
+	if invalid := create.Responses["422"]; invalid.Description != "unprocessable entity" || invalid.Content != nil {
+		t.Fatalf("unexpected 422 response %+v", invalid)
+	}
+
+	list := builder.Document().Paths["/users"].Get.Responses["200"].Content["application/json"].Schema
+	if list.Type != "array" || list.Items.Ref != "#/components/schemas/user" {
+		t.Fatalf("unexpected list schema %+v", list)
+	}
+
+	schemas := builder.Document().Components.Schemas
+	if required := schemas["createUser"].Required; len(required) != 1 || required[0] != "name" {
+		t.Fatalf("expected name to be required, got %v", required)
+	}
+	if schemas["user"].Properties["id"].Type != "integer" {
+		t.Fatalf("unexpected user schema %+v", schemas["user"])
+	}
+}
+
+type openAPIPage struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+func TestAddOpenAPIRoutesQueryModel(t *testing.T) {
+	type listParams struct {
+		openAPIPage
+		Sort    string   `query:"sort" validate:"required"`
+		Tags    []string `form:"tag"`
+		Search  *string  `json:"q"`
+		Ignored string   `query:"-"`
+		secret  string
+	}
+
+	app := New()
+	app.Route(http.MethodGet, "/orgs/:org/users", func(*Context) error { return nil },
+		WithName("user.list"),
+		WithQueryModel(reflect.TypeOf(listParams{})),
+	)
+
+	builder := openapi.New(openapi.Info{Title: "bebo", Version: "v0.1"})
+	if err := app.AddOpenAPIRoutes(builder); err != nil {
+		t.Fatalf("add openapi routes: %v", err)
+	}
+
+	params := builder.Document().Paths["/orgs/{org}/users"].Get.Parameters
+	var names []string
+	for _, param := range params {
+		names = append(names, param.In+":"+param.Name)
+	}
+	want := []string{"path:org", "query:page", "query:limit", "query:sort", "query:tag", "query:q"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected parameters %v, got %v", want, names)
+	}
+
+	byName := map[string]openapi.Parameter{}
+	for _, param := range params {
+		byName[param.Name] = param
+	}
+	if page := byName["page"]; page.Required || page.Schema.Type != "integer" {
+		t.Fatalf("unexpected page parameter %+v", page)
+	}
+	if sort := byName["sort"]; !sort.Required || sort.Schema.Type != "string" {
+		t.Fatalf("expected required string sort, got %+v", sort)
+	}
+	if tag := byName["tag"]; tag.Schema.Type != "array" || tag.Schema.Items.Type != "string" {
+		t.Fatalf("expected array tag parameter, got %+v", tag.Schema)
+	}
+	if q := byName["q"]; q.Schema.Type != "string" {
+		t.Fatalf("expected pointer to unwrap, got %+v", q.Schema)
+	}
+}
+
+func TestAddOpenAPIRoutesSecurity(t *testing.T) {
+	app := New()
+	handler := func(*Context) error { return nil }
+	app.GET("/health", handler)
+	app.GET("/api/users", handler)
+	app.GET("/api/admin/stats", handler)
+	app.Route(http.MethodGet, "/api/reports", handler, WithSecurity("oauth", "reports:read"))
+
+	builder := openapi.New(openapi.Info{Title: "bebo", Version: "v0.1"})
+	err := app.AddOpenAPIRoutes(builder,
+		WithOpenAPISecurity("/api/*", "bearerAuth"),
+		WithOpenAPISecurity("/api/admin/*", "adminKey"),
+		WithOpenAPISecurityScheme("adminKey", openapi.SecurityScheme{Type: "apiKey", Name: "X-Admin-Key", In: "header"}),
+	)
+	if err != nil {
+		t.Fatalf("add openapi routes: %v", err)
+	}
+
+	doc := builder.Document()
+	cases := map[string][]map[string][]string{
+		"/health":          nil,
+		"/api/users":       {{"bearerAuth": {}}},
+		"/api/admin/stats": {{"adminKey": {}}},
+		"/api/reports":     {{"oauth": {"reports:read"}}},
+	}
+	for path, want := range cases {
+		if got := doc.Paths[path].Get.Security; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: expected security %v, got %v", path, want, got)
+		}
+	}
+
+	schemes := doc.Components.SecuritySchemes
+	if scheme := schemes["bearerAuth"]; scheme.Type != "http" || scheme.Scheme != "bearer" {
+		t.Fatalf("expected default bearer scheme, got %+v", scheme)
+	}
+	if scheme := schemes["adminKey"]; scheme.Type != "apiKey" || scheme.Name != "X-Admin-Key" {
+		t.Fatalf("expected registered scheme to be kept, got %+v", scheme)
+	}
+	if _, ok := schemes["oauth"]; !ok {
+		t.Fatalf("expected route scheme to be registered, got %v", schemes)
+	}
+
+	encoded, err := json.Marshal(doc.Paths["/api/users"].Get)
+	if err != nil {
+		t.Fatalf("marshal operation: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"security":[{"bearerAuth":[]}]`) {
+		t.Fatalf("expected empty scope list in JSON, got %s", encoded)
+	}
+}
+
+func TestOpenAPIDocs(t *testing.T) {
+	app := New(WithBasePath("/api"))
+	builder := openapi.New(openapi.Info{Title: "bebo", Version: "v0.1"})
+	app.GET("/users", func(*Context) error { return nil })
+	if err := app.AddOpenAPIRoutes(builder); err != nil {
+		t.Fatalf("add openapi routes: %v", err)
+	}
+	app.OpenAPIDocs("/openapi.json", "/docs", builder)
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	if doc.Paths["/users"] == nil {
+		t.Fatalf("expected served spec to include routes, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("expected html docs page, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `url: "/api/openapi.json"`) {
+		t.Fatalf("expected ui to load the prefixed spec url, got %s", rec.Body.String())
+	}
+}
+
+func fetchOpenAPI(t *testing.T, app *App) openapi.Document {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	return doc
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	app := New()
+	app.ServeOpenAPI("/openapi.json", openapi.Info{Title: "bebo", Version: "v1"})
+	// Registered after ServeOpenAPI but before the first request.
+	app.Route(http.MethodGet, "/users/:id", func(*Context) error { return nil }, WithName("user.show"))
+
+	doc := fetchOpenAPI(t, app)
+	if doc.Info.Title != "bebo" {
+		t.Fatalf("unexpected info %+v", doc.Info)
+	}
+	if item := doc.Paths["/users/{id}"]; item == nil || item.Get == nil {
+		t.Fatalf("expected users route in spec, got %v", doc.Paths)
+	}
+	if _, ok := doc.Paths["/openapi.json"]; ok {
+		t.Fatalf("expected spec route to be skipped")
+	}
+
+	app.POST("/users", func(*Context) error { return nil })
+	if _, ok := fetchOpenAPI(t, app).Paths["/users"]; ok {
+		t.Fatalf("expected cached spec without rebuild")
+	}
+}
+
+func TestServeOpenAPIRebuild(t *testing.T) {
+	app := New()
+	app.ServeOpenAPI("/openapi.json", openapi.Info{Title: "bebo", Version: "v1"}, WithOpenAPIRebuild(true))
+	app.GET("/health", func(*Context) error { return nil })
+
+	if _, ok := fetchOpenAPI(t, app).Paths["/users"]; ok {
+		t.Fatalf("unexpected users route")
+	}
+	app.POST("/users", func(*Context) error { return nil })
+	item := fetchOpenAPI(t, app).Paths["/users"]
+	if item == nil || item.Post == nil {
+		t.Fatalf("expected rebuilt spec to include new route")
+	}
+}
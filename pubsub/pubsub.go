@@ -0,0 +1,215 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed indicates the broker has been closed.
+var ErrClosed = errors.New("pubsub broker is closed")
+
+// OverflowPolicy controls what Publish does when a subscriber queue is full.
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the message for the full subscriber.
+	DropNewest OverflowPolicy = iota
+	// Block waits for the subscriber until the publish context is done.
+	Block
+)
+
+// Options configures a Broker.
+type Options struct {
+	BufferSize int
+	Overflow   OverflowPolicy
+}
+
+// DefaultOptions returns a Broker configuration with sane defaults.
+func DefaultOptions() Options {
+	return Options{BufferSize: 16, Overflow: DropNewest}
+}
+
+// Broker fans messages of type T out to subscribers of named topics.
+// It is safe for concurrent use.
+type Broker[T any] struct {
+	opts   Options
+	mu     sync.RWMutex
+	topics map[string]map[*Subscription[T]]struct{}
+	closed bool
+}
+
+// Subscription receives messages published to a topic on C.
+// C is closed after Unsubscribe or when the broker closes.
+type Subscription[T any] struct {
+	C <-chan T
+
+	topic   string
+	broker  *Broker[T]
+	ch      chan T
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// New creates a broker.
+func New[T any](options Options) *Broker[T] {
+	return &Broker[T]{
+		opts:   normalizeOptions(options),
+		topics: make(map[string]map[*Subscription[T]]struct{}),
+	}
+}
+
+func normalizeOptions(options Options) Options {
+	if options.BufferSize <= 0 {
+		options.BufferSize = DefaultOptions().BufferSize
+	}
+	return options
+}
+
+// Subscribe registers a subscriber for topic.
+func (b *Broker[T]) Subscribe(topic string) (*Subscription[T], error) {
+	ch := make(chan T, b.opts.BufferSize)
+	sub := &Subscription[T]{
+		C:      ch,
+		topic:  topic,
+		broker: b,
+		ch:     ch,
+		done:   make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	subs := b.topics[topic]
+	if subs == nil {
+		subs = make(map[*Subscription[T]]struct{})
+		b.topics[topic] = subs
+	}
+	subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Publish delivers msg to every subscriber of topic and reports how many
+// received it. With the Block policy it waits for full queues until ctx is done.
+func (b *Broker[T]) Publish(ctx context.Context, topic string, msg T) (int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return 0, ErrClosed
+	}
+	subs := make([]*Subscription[T], 0, len(b.topics[topic]))
+	for sub := range b.topics[topic] {
+		subs = append(subs, sub)
+	}
+	b.mu.RUnlock()
+
+	delivered := 0
+	for _, sub := range subs {
+		ok, err := sub.deliver(ctx, msg, b.opts.Overflow)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// Subscribers returns the number of subscribers for topic.
+func (b *Broker[T]) Subscribers(topic string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.topics[topic])
+}
+
+// Close unsubscribes everyone and rejects further Subscribe and Publish calls.
+func (b *Broker[T]) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	topics := b.topics
+	b.topics = make(map[string]map[*Subscription[T]]struct{})
+	b.mu.Unlock()
+
+	for _, subs := range topics {
+		for sub := range subs {
+			sub.close()
+		}
+	}
+}
+
+// Topic returns the subscribed topic.
+func (s *Subscription[T]) Topic() string {
+	return s.topic
+}
+
+// Dropped returns how many messages were discarded because the queue was full.
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe removes the subscription and closes C.
+func (s *Subscription[T]) Unsubscribe() {
+	s.broker.mu.Lock()
+	if subs := s.broker.topics[s.topic]; subs != nil {
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(s.broker.topics, s.topic)
+		}
+	}
+	s.broker.mu.Unlock()
+	s.close()
+}
+
+func (s *Subscription[T]) deliver(ctx context.Context, msg T, policy OverflowPolicy) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return false, nil
+	}
+
+	select {
+	case s.ch <- msg:
+		return true, nil
+	default:
+	}
+	if policy != Block {
+		s.dropped.Add(1)
+		return false, nil
+	}
+
+	select {
+	case s.ch <- msg:
+		return true, nil
+	case <-s.done:
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// close signals blocked publishers before closing the channel so that no send
+// can race with close.
+func (s *Subscription[T]) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
+}
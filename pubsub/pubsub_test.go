@@ -0,0 +1,122 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type event struct {
+	ID int
+}
+
+func TestPublishFansOutToTopicSubscribers(t *testing.T) {
+	broker := New[event](DefaultOptions())
+	first, err := broker.Subscribe("orders")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	second, _ := broker.Subscribe("orders")
+	other, _ := broker.Subscribe("users")
+
+	delivered, err := broker.Publish(context.Background(), "orders", event{ID: 1})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if delivered != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", delivered)
+	}
+	for _, sub := range []*Subscription[event]{first, second} {
+		if msg := <-sub.C; msg.ID != 1 {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	}
+	select {
+	case msg := <-other.C:
+		t.Fatalf("unexpected message on other topic: %+v", msg)
+	default:
+	}
+}
+
+func TestPublishDropsWhenQueueFull(t *testing.T) {
+	broker := New[int](Options{BufferSize: 1})
+	sub, _ := broker.Subscribe("ticks")
+
+	_, _ = broker.Publish(context.Background(), "ticks", 1)
+	delivered, err := broker.Publish(context.Background(), "ticks", 2)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if delivered != 0 || sub.Dropped() != 1 {
+		t.Fatalf("expected dropped message, delivered=%d dropped=%d", delivered, sub.Dropped())
+	}
+	if msg := <-sub.C; msg != 1 {
+		t.Fatalf("expected first message kept, got %d", msg)
+	}
+}
+
+func TestPublishBlocksUntilContextDone(t *testing.T) {
+	broker := New[int](Options{BufferSize: 1, Overflow: Block})
+	sub, _ := broker.Subscribe("ticks")
+	_, _ = broker.Publish(context.Background(), "ticks", 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := broker.Publish(ctx, "ticks", 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-sub.C
+	}()
+	delivered, err := broker.Publish(context.Background(), "ticks", 3)
+	if err != nil || delivered != 1 {
+		t.Fatalf("expected blocked publish to deliver, delivered=%d err=%v", delivered, err)
+	}
+}
+
+func TestUnsubscribeClosesChannelAndReleasesPublisher(t *testing.T) {
+	broker := New[int](Options{BufferSize: 1, Overflow: Block})
+	sub, _ := broker.Subscribe("ticks")
+	_, _ = broker.Publish(context.Background(), "ticks", 1)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = broker.Publish(context.Background(), "ticks", 2)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	sub.Unsubscribe()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected blocked publish released by unsubscribe")
+	}
+	if broker.Subscribers("ticks") != 0 {
+		t.Fatalf("expected no subscribers")
+	}
+	<-sub.C
+	if _, ok := <-sub.C; ok {
+		t.Fatalf("expected channel closed")
+	}
+	sub.Unsubscribe()
+}
+
+func TestCloseRejectsFurtherUse(t *testing.T) {
+	broker := New[int](DefaultOptions())
+	sub, _ := broker.Subscribe("ticks")
+	broker.Close()
+
+	if _, ok := <-sub.C; ok {
+		t.Fatalf("expected subscription closed")
+	}
+	if _, err := broker.Subscribe("ticks"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if _, err := broker.Publish(context.Background(), "ticks", 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
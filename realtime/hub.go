@@ -0,0 +1,206 @@
+package realtime
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/devmarvs/bebo"
+)
+
+// HubOptions configures a websocket hub.
+// Clients whose send buffer stays full for SendTimeout are dropped and closed.
+// OnMessage, when set, receives data messages read by ServeWS.
+type HubOptions struct {
+	SendTimeout time.Duration
+	BufferSize  int
+	OnMessage   func(conn *Conn, opcode int, payload []byte)
+}
+
+// Hub tracks websocket connections and fans messages out to them.
+// It is safe for concurrent use.
+type Hub struct {
+	options HubOptions
+	mu      sync.RWMutex
+	clients map[*Conn]*hubClient
+}
+
+type hubMessage struct {
+	opcode  int
+	payload []byte
+}
+
+type hubClient struct {
+	conn *Conn
+	send chan hubMessage
+	done chan struct{}
+}
+
+// NewHub creates a hub.
+func NewHub(options HubOptions) *Hub {
+	return &Hub{
+		options: normalizeHub(options),
+		clients: make(map[*Conn]*hubClient),
+	}
+}
+
+func normalizeHub(options HubOptions) HubOptions {
+	if options.SendTimeout <= 0 {
+		options.SendTimeout = 5 * time.Second
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = 16
+	}
+	return options
+}
+
+// Register adds a connection to the hub.
+func (h *Hub) Register(conn *Conn) {
+	if conn == nil {
+		return
+	}
+	h.mu.Lock()
+	if _, ok := h.clients[conn]; ok {
+		h.mu.Unlock()
+		return
+	}
+	client := &hubClient{
+		conn: conn,
+		send: make(chan hubMessage, h.options.BufferSize),
+		done: make(chan struct{}),
+	}
+	h.clients[conn] = client
+	h.mu.Unlock()
+
+	go h.writeLoop(client)
+}
+
+// Unregister removes a connection from the hub without closing it.
+func (h *Hub) Unregister(conn *Conn) {
+	h.mu.Lock()
+	client, ok := h.clients[conn]
+	if ok {
+		delete(h.clients, conn)
+		close(client.done)
+	}
+	h.mu.Unlock()
+}
+
+// Len returns the number of registered connections.
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Broadcast sends a message to every registered connection.
+func (h *Hub) Broadcast(opcode int, payload []byte) {
+	h.BroadcastFilter(opcode, payload, nil)
+}
+
+// BroadcastText sends a text message to every registered connection.
+func (h *Hub) BroadcastText(message string) {
+	h.Broadcast(OpText, []byte(message))
+}
+
+// BroadcastFilter sends a message to connections accepted by filter.
+// A nil filter matches every connection.
+func (h *Hub) BroadcastFilter(opcode int, payload []byte, filter func(*Conn) bool) {
+	h.mu.RLock()
+	targets := make([]*hubClient, 0, len(h.clients))
+	for conn, client := range h.clients {
+		if filter == nil || filter(conn) {
+			targets = append(targets, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	msg := hubMessage{opcode: opcode, payload: payload}
+	var blocked []*hubClient
+	for _, client := range targets {
+		select {
+		case client.send <- msg:
+		case <-client.done:
+		default:
+			blocked = append(blocked, client)
+		}
+	}
+	if len(blocked) > 0 {
+		h.sendWithTimeout(blocked, msg)
+	}
+}
+
+// sendWithTimeout waits for room in each client's buffer concurrently, against
+// one SendTimeout deadline, and drops the clients still blocked when it expires.
+// Every client gets the full timeout, yet a broadcast with many stuck clients
+// takes about one SendTimeout rather than one per client.
+func (h *Hub) sendWithTimeout(clients []*hubClient, msg hubMessage) {
+	expired := make(chan struct{})
+	timer := time.AfterFunc(h.options.SendTimeout, func() { close(expired) })
+	defer timer.Stop()
+
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *hubClient) {
+			defer wg.Done()
+			select {
+			case client.send <- msg:
+			case <-client.done:
+			case <-expired:
+				h.drop(client.conn)
+			}
+		}(client)
+	}
+	wg.Wait()
+}
+
+// ServeWS upgrades the request, registers the connection, and reads until it closes.
+// Data messages are passed to HubOptions.OnMessage.
+func (h *Hub) ServeWS(ctx *bebo.Context, options WebSocketOptions) error {
+	conn, err := Upgrade(ctx, options)
+	if err != nil {
+		return err
+	}
+	h.Register(conn)
+	defer func() {
+		h.Unregister(conn)
+		_ = conn.Close()
+	}()
+
+	for {
+		opcode, payload, err := conn.ReadMessage()
+		if err != nil {
+			if errors.Is(err, ErrClosed) || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		if h.options.OnMessage != nil {
+			h.options.OnMessage(conn, opcode, payload)
+		}
+	}
+}
+
+func (h *Hub) writeLoop(client *hubClient) {
+	for {
+		select {
+		case <-client.done:
+			return
+		case msg := <-client.send:
+			if err := client.conn.WriteMessage(msg.opcode, msg.payload); err != nil {
+				h.drop(client.conn)
+				return
+			}
+		}
+	}
+}
+
+// drop unregisters a client and closes its connection without a close frame,
+// since a slow or broken peer may never read it.
+func (h *Hub) drop(conn *Conn) {
+	h.Unregister(conn)
+	_ = conn.shutdown(false, nil)
+}
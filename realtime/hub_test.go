@@ -0,0 +1,221 @@
+package realtime
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo"
+)
+
+func newPipeConn(t *testing.T) (*Conn, net.Conn) {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+	return newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{}), client
+}
+
+func readServerFrame(t *testing.T, client net.Conn) (int, string) {
+	t.Helper()
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(client, header); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	payload := make([]byte, int(header[1]&0x7F))
+	if _, err := io.ReadFull(client, payload); err != nil {
+		t.Fatalf("read frame payload: %v", err)
+	}
+	return int(header[0] & 0x0F), string(payload)
+}
+
+func TestHubBroadcast(t *testing.T) {
+	hub := NewHub(HubOptions{})
+	first, firstClient := newPipeConn(t)
+	second, secondClient := newPipeConn(t)
+	hub.Register(first)
+	hub.Register(second)
+
+	if hub.Len() != 2 {
+		t.Fatalf("expected 2 connections, got %d", hub.Len())
+	}
+
+	hub.BroadcastText("hello")
+	for _, client := range []net.Conn{firstClient, secondClient} {
+		opcode, payload := readServerFrame(t, client)
+		if opcode != OpText || payload != "hello" {
+			t.Fatalf("unexpected frame %d %q", opcode, payload)
+		}
+	}
+
+	hub.Unregister(second)
+	if hub.Len() != 1 {
+		t.Fatalf("expected 1 connection after unregister, got %d", hub.Len())
+	}
+}
+
+func TestHubBroadcastFilter(t *testing.T) {
+	hub := NewHub(HubOptions{})
+	first, firstClient := newPipeConn(t)
+	second, secondClient := newPipeConn(t)
+	hub.Register(first)
+	hub.Register(second)
+
+	hub.BroadcastFilter(OpText, []byte("only first"), func(conn *Conn) bool {
+		return conn == first
+	})
+
+	if _, payload := readServerFrame(t, firstClient); payload != "only first" {
+		t.Fatalf("unexpected payload %q", payload)
+	}
+	_ = secondClient.SetReadDeadline(time.Now().Add(30 * time.Millisecond))
+	if _, err := secondClient.Read(make([]byte, 1)); err == nil {
+		t.Fatalf("expected filtered connection to receive nothing")
+	}
+}
+
+func TestHubDropsSlowClients(t *testing.T) {
+	hub := NewHub(HubOptions{SendTimeout: 10 * time.Millisecond, BufferSize: 1})
+	slow, slowClient := newPipeConn(t)
+	fast, fastClient := newPipeConn(t)
+	hub.Register(slow)
+	hub.Register(fast)
+
+	received := make(chan string, 3)
+	go func() {
+		for i := 0; i < 3; i++ {
+			header := make([]byte, 2)
+			if _, err := io.ReadFull(fastClient, header); err != nil {
+				return
+			}
+			payload := make([]byte, int(header[1]&0x7F))
+			if _, err := io.ReadFull(fastClient, payload); err != nil {
+				return
+			}
+			received <- string(payload)
+		}
+	}()
+
+	for _, msg := range []string{"a", "b", "c"} {
+		hub.BroadcastText(msg)
+	}
+
+	if hub.Len() != 1 {
+		t.Fatalf("expected slow client dropped, got %d connections", hub.Len())
+	}
+	for _, want := range []string{"a", "b", "c"} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+
+	_ = slowClient.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadAll(slowClient); err != nil {
+		t.Fatalf("expected slow connection closed, got %v", err)
+	}
+}
+
+func TestHubSendTimeoutSharedAcrossClients(t *testing.T) {
+	timeout := 50 * time.Millisecond
+	hub := NewHub(HubOptions{SendTimeout: timeout, BufferSize: 1})
+	const stuck = 8
+	for i := 0; i < stuck; i++ {
+		conn, _ := newPipeConn(t)
+		hub.Register(conn)
+	}
+
+	// No client reads: the first two messages fill each writer and buffer,
+	// and the third blocks every client until the shared deadline.
+	hub.BroadcastText("a")
+	hub.BroadcastText("b")
+	start := time.Now()
+	hub.BroadcastText("c")
+	elapsed := time.Since(start)
+
+	if hub.Len() != 0 {
+		t.Fatalf("expected all stuck clients dropped, got %d connections", hub.Len())
+	}
+	if elapsed < timeout {
+		t.Fatalf("expected clients to get the full %s timeout, broadcast took %s", timeout, elapsed)
+	}
+	if elapsed > 3*timeout {
+		t.Fatalf("expected %d stuck clients to take about one %s timeout, broadcast took %s", stuck, timeout, elapsed)
+	}
+}
+
+func TestHubServeWS(t *testing.T) {
+	messages := make(chan string, 1)
+	hub := NewHub(HubOptions{
+		OnMessage: func(_ *Conn, _ int, payload []byte) {
+			messages <- string(payload)
+		},
+	})
+	app := bebo.New()
+	app.GET("/ws", func(ctx *bebo.Context) error {
+		return hub.ServeWS(ctx, WebSocketOptions{})
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	client, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+
+	handshake := "GET /ws HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := client.Write([]byte(handshake)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	reader := bufio.NewReader(client)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+
+	if err := writeMaskedText(client, []byte("hi hub")); err != nil {
+		t.Fatalf("write message: %v", err)
+	}
+	select {
+	case msg := <-messages:
+		if msg != "hi hub" {
+			t.Fatalf("unexpected message %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for message")
+	}
+	if hub.Len() != 1 {
+		t.Fatalf("expected registered connection, got %d", hub.Len())
+	}
+
+	hub.BroadcastText("welcome")
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatalf("read broadcast: %v", err)
+	}
+	payload := make([]byte, int(header[1]&0x7F))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("read broadcast payload: %v", err)
+	}
+	if string(payload) != "welcome" {
+		t.Fatalf("unexpected broadcast %q", string(payload))
+	}
+}
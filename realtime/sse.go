@@ -2,9 +2,7 @@ package realtime
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
@@ -16,7 +14,7 @@ type SSEOptions struct {
 	Headers map[string]string
 }
 
-// SSEMessage is an SSE payload.
+// SSEMessage is an SSE payload, encoded with bebo.WriteSSEEvent.
 type SSEMessage struct {
 	Event string
 	ID    string
@@ -59,20 +57,10 @@ func (s *SSE) Send(msg SSEMessage) error {
 		return errors.New("sse stream closed")
 	}
 
-	if msg.Retry > 0 {
-		fmt.Fprintf(s.w, "retry: %d\n", msg.Retry.Milliseconds())
+	event := bebo.SSEEvent{ID: msg.ID, Event: msg.Event, Data: msg.Data, Retry: msg.Retry}
+	if err := bebo.WriteSSEEvent(s.w, event); err != nil {
+		return err
 	}
-	if msg.ID != "" {
-		fmt.Fprintf(s.w, "id: %s\n", msg.ID)
-	}
-	if msg.Event != "" {
-		fmt.Fprintf(s.w, "event: %s\n", msg.Event)
-	}
-
-	for _, line := range strings.Split(msg.Data, "\n") {
-		fmt.Fprintf(s.w, "data: %s\n", line)
-	}
-	fmt.Fprint(s.w, "\n")
 	s.flusher.Flush()
 	return nil
 }
@@ -32,3 +32,22 @@ func TestSSESend(t *testing.T) {
 		t.Fatalf("expected data line, got %q", body)
 	}
 }
+
+func TestSSESendMatchesContextEncoding(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := bebo.NewContext(rec, httptest.NewRequest(http.MethodGet, "/events", nil), router.Params{}, bebo.New())
+
+	stream, err := StartSSE(ctx, SSEOptions{})
+	if err != nil {
+		t.Fatalf("start sse: %v", err)
+	}
+	if err := stream.Send(SSEMessage{ID: "1\nevent: spoof", Data: "a\r\nb"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	var want strings.Builder
+	_ = bebo.WriteSSEEvent(&want, bebo.SSEEvent{ID: "1\nevent: spoof", Data: "a\r\nb"})
+	if got := rec.Body.String(); got != want.String() || got != "id: 1event: spoof\ndata: a\ndata: b\n\n" {
+		t.Fatalf("unexpected stream %q", got)
+	}
+}
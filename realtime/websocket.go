@@ -7,12 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/internal/ratelimit"
 )
 
 const (
@@ -24,15 +27,40 @@ const (
 	OpPong         = 0xA
 )
 
+// Close codes sent by the server (RFC 6455 section 7.4.1).
+const (
+	CloseNormal          = 1000
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+)
+
 var ErrClosed = errors.New("websocket closed")
 
+// ErrMessageTooLarge indicates a message exceeded MaxMessageSize.
+var ErrMessageTooLarge = errors.New("message too large")
+
+// ErrRateLimited indicates the peer sent messages faster than MaxMessagesPerSecond.
+var ErrRateLimited = errors.New("websocket message rate exceeded")
+
 const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
 
+const closeWriteTimeout = time.Second
+
 // WebSocketOptions configures websocket behavior.
+// Subprotocols lists supported protocols in preference order; the first one
+// also offered by the client is echoed in Sec-WebSocket-Protocol.
+// EnableCompression negotiates permessage-deflate when the client offers it.
+// MaxMessagesPerSecond limits the data messages read per connection, allowing
+// bursts of MessageBurst (default: the rate rounded up); a peer exceeding it is
+// closed with ClosePolicyViolation. Oversized messages close with CloseMessageTooBig.
 type WebSocketOptions struct {
-	MaxMessageSize int64
-	ReadTimeout    time.Duration
-	WriteTimeout   time.Duration
+	MaxMessageSize       int64
+	ReadTimeout          time.Duration
+	WriteTimeout         time.Duration
+	Subprotocols         []string
+	EnableCompression    bool
+	MaxMessagesPerSecond float64
+	MessageBurst         int
 }
 
 // Conn represents a websocket connection.
@@ -42,6 +70,14 @@ type Conn struct {
 	maxMessageSize int64
 	readTimeout    time.Duration
 	writeTimeout   time.Duration
+	subprotocol    string
+	compression    bool
+	writeMu        sync.Mutex
+	closeOnce      sync.Once
+	closeErr       error
+	done           chan struct{}
+	pongs          chan struct{}
+	limiter        *ratelimit.Bucket
 }
 
 // Upgrade upgrades the request to a WebSocket connection.
@@ -62,19 +98,25 @@ func Upgrade(ctx *bebo.Context, options WebSocketOptions) (*Conn, error) {
 		return nil, errors.New("websocket key missing")
 	}
 
-	hj, ok := ctx.ResponseWriter.(http.Hijacker)
-	if !ok {
-		return nil, errors.New("response writer does not support hijacking")
-	}
-
-	conn, rw, err := hj.Hijack()
+	conn, rw, err := ctx.Hijack()
 	if err != nil {
 		return nil, err
 	}
 
+	subprotocol := negotiateSubprotocol(req.Header, options.Subprotocols)
+	compression := options.EnableCompression && offersDeflate(req.Header)
+
 	accept := acceptKey(key)
-	response := fmt.Sprintf("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept)
-	if _, err := rw.WriteString(response); err != nil {
+	var response strings.Builder
+	fmt.Fprintf(&response, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n", accept)
+	if subprotocol != "" {
+		fmt.Fprintf(&response, "Sec-WebSocket-Protocol: %s\r\n", subprotocol)
+	}
+	if compression {
+		response.WriteString("Sec-WebSocket-Extensions: " + deflateExtension + "\r\n")
+	}
+	response.WriteString("\r\n")
+	if _, err := rw.WriteString(response.String()); err != nil {
 		_ = conn.Close()
 		return nil, err
 	}
@@ -83,7 +125,10 @@ func Upgrade(ctx *bebo.Context, options WebSocketOptions) (*Conn, error) {
 		return nil, err
 	}
 
-	return newConn(conn, rw, options), nil
+	ws := newConn(conn, rw, options)
+	ws.subprotocol = subprotocol
+	ws.compression = compression
+	return ws, nil
 }
 
 func newConn(conn net.Conn, rw *bufio.ReadWriter, options WebSocketOptions) *Conn {
@@ -91,34 +136,98 @@ func newConn(conn net.Conn, rw *bufio.ReadWriter, options WebSocketOptions) *Con
 	if maxSize <= 0 {
 		maxSize = 1 << 20
 	}
-	return &Conn{
+	ws := &Conn{
 		conn:           conn,
 		rw:             rw,
 		maxMessageSize: maxSize,
 		readTimeout:    options.ReadTimeout,
 		writeTimeout:   options.WriteTimeout,
+		done:           make(chan struct{}),
+		pongs:          make(chan struct{}, 1),
+	}
+	if options.MaxMessagesPerSecond > 0 {
+		burst := float64(options.MessageBurst)
+		if burst <= 0 {
+			burst = math.Ceil(options.MaxMessagesPerSecond)
+		}
+		ws.limiter = ratelimit.New(options.MaxMessagesPerSecond, burst, time.Now())
 	}
+	return ws
 }
 
-// ReadMessage reads the next non-control frame.
+// Subprotocol returns the negotiated subprotocol, or "" when none was selected.
+func (c *Conn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// ReadMessage reads the next data message, reassembling fragmented frames.
+// Control frames received between fragments are handled immediately.
+// Exceeding the size or rate limit closes the connection with a close code.
 func (c *Conn) ReadMessage() (int, []byte, error) {
+	opcode, payload, err := c.readMessage()
+	if errors.Is(err, ErrMessageTooLarge) {
+		_ = c.CloseWithCode(CloseMessageTooBig, "message too large")
+		return 0, nil, err
+	}
+	if err == nil && c.limiter != nil && !c.limiter.Take(time.Now()) {
+		_ = c.CloseWithCode(ClosePolicyViolation, "message rate exceeded")
+		return 0, nil, ErrRateLimited
+	}
+	return opcode, payload, err
+}
+
+func (c *Conn) readMessage() (int, []byte, error) {
+	messageOp := -1
+	compressed := false
+	var message []byte
 	for {
-		opcode, payload, err := c.readFrame()
+		frame, err := c.readFrame()
 		if err != nil {
 			return 0, nil, err
 		}
+		opcode, payload := frame.opcode, frame.payload
 		switch opcode {
 		case OpPing:
 			_ = c.WriteMessage(OpPong, payload)
 			continue
 		case OpPong:
+			select {
+			case c.pongs <- struct{}{}:
+			default:
+			}
 			continue
 		case OpClose:
 			_ = c.WriteMessage(OpClose, nil)
 			return OpClose, nil, ErrClosed
+		case OpContinuation:
+			if messageOp < 0 {
+				return 0, nil, errors.New("unexpected continuation frame")
+			}
+			if frame.compressed {
+				return 0, nil, errors.New("unexpected reserved bits")
+			}
 		default:
-			return opcode, payload, nil
+			if messageOp >= 0 {
+				return 0, nil, errors.New("expected continuation frame")
+			}
+			messageOp = opcode
+			compressed = frame.compressed
+		}
+
+		if int64(len(message))+int64(len(payload)) > c.maxMessageSize {
+			return 0, nil, ErrMessageTooLarge
 		}
+		message = append(message, payload...)
+		if !frame.fin {
+			continue
+		}
+		if compressed {
+			message, err = inflateMessage(message, c.maxMessageSize)
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+		return messageOp, message, nil
 	}
 }
 
@@ -136,12 +245,22 @@ func (c *Conn) ReadText() (string, error) {
 
 // WriteMessage writes a websocket frame.
 func (c *Conn) WriteMessage(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
 	if c.writeTimeout > 0 {
 		_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
 	}
 
-	fin := byte(0x80)
-	b1 := fin | byte(opcode)
+	b1 := byte(0x80) | byte(opcode)
+	if c.compression && (opcode == OpText || opcode == OpBinary) {
+		compressed, err := deflateMessage(payload)
+		if err != nil {
+			return err
+		}
+		payload = compressed
+		b1 |= 0x40
+	}
 	if err := c.rw.WriteByte(b1); err != nil {
 		return err
 	}
@@ -184,76 +303,162 @@ func (c *Conn) WriteText(message string) error {
 	return c.WriteMessage(OpText, []byte(message))
 }
 
-// Close closes the websocket connection.
+// Close closes the websocket connection. It is safe to call more than once
+// and concurrently with the keepalive goroutine.
 func (c *Conn) Close() error {
-	_ = c.WriteMessage(OpClose, nil)
-	return c.conn.Close()
+	return c.shutdown(true, nil)
+}
+
+// CloseWithCode sends a close frame with code and reason, then closes the
+// connection. The reason is truncated to fit a control frame.
+func (c *Conn) CloseWithCode(code int, reason string) error {
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	payload := append([]byte{byte(code >> 8), byte(code)}, reason...)
+	if c.writeTimeout <= 0 {
+		// Don't let a peer that stopped reading hold the close open.
+		_ = c.conn.SetWriteDeadline(time.Now().Add(closeWriteTimeout))
+	}
+	return c.shutdown(true, payload)
+}
+
+// StartKeepalive pings the peer every interval and closes the connection when
+// no pong arrives within timeout. Pongs are only observed while ReadMessage is
+// being called, so keep a read loop running. A timeout <= 0 uses interval.
+func (c *Conn) StartKeepalive(interval, timeout time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	if timeout <= 0 {
+		timeout = interval
+	}
+	go c.keepalive(interval, timeout)
 }
 
-func (c *Conn) readFrame() (int, []byte, error) {
+func (c *Conn) keepalive(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		select {
+		case <-c.pongs:
+		default:
+		}
+		if err := c.WriteMessage(OpPing, nil); err != nil {
+			_ = c.shutdown(false, nil)
+			return
+		}
+
+		timer := time.NewTimer(timeout)
+		select {
+		case <-c.done:
+			timer.Stop()
+			return
+		case <-c.pongs:
+			timer.Stop()
+		case <-timer.C:
+			_ = c.shutdown(false, nil)
+			return
+		}
+	}
+}
+
+// shutdown closes the connection once, optionally sending a close frame
+// carrying payload first.
+func (c *Conn) shutdown(sendClose bool, payload []byte) error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		if sendClose {
+			_ = c.WriteMessage(OpClose, payload)
+		}
+		c.closeErr = c.conn.Close()
+	})
+	return c.closeErr
+}
+
+type frame struct {
+	fin        bool
+	compressed bool
+	opcode     int
+	payload    []byte
+}
+
+// readFrame reads a single frame; ReadMessage reassembles fragments.
+func (c *Conn) readFrame() (frame, error) {
 	if c.readTimeout > 0 {
 		_ = c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
 	}
 
 	b1, err := c.rw.ReadByte()
 	if err != nil {
-		return 0, nil, err
+		return frame{}, err
 	}
 	b2, err := c.rw.ReadByte()
 	if err != nil {
-		return 0, nil, err
+		return frame{}, err
 	}
 
 	fin := b1&0x80 != 0
+	rsv1 := b1&0x40 != 0
 	opcode := int(b1 & 0x0F)
 	masked := b2&0x80 != 0
 	payloadLen := int64(b2 & 0x7F)
 
-	if !fin && opcode != OpContinuation {
-		return 0, nil, errors.New("fragmented frames not supported")
+	if !fin && opcode >= OpClose {
+		return frame{}, errors.New("control frames must not be fragmented")
+	}
+	if b1&0x30 != 0 || (rsv1 && (!c.compression || opcode >= OpClose)) {
+		return frame{}, errors.New("unexpected reserved bits")
 	}
 	if !masked {
-		return 0, nil, errors.New("client frames must be masked")
+		return frame{}, errors.New("client frames must be masked")
 	}
 
 	switch payloadLen {
 	case 126:
 		value, err := readUint16(c.rw)
 		if err != nil {
-			return 0, nil, err
+			return frame{}, err
 		}
 		payloadLen = int64(value)
 	case 127:
 		value, err := readUint64(c.rw)
 		if err != nil {
-			return 0, nil, err
+			return frame{}, err
 		}
 		payloadLen = int64(value)
 	}
 
 	if payloadLen > c.maxMessageSize {
-		return 0, nil, errors.New("message too large")
+		return frame{}, ErrMessageTooLarge
 	}
 	if opcode >= OpClose && payloadLen > 125 {
-		return 0, nil, errors.New("control frame too large")
+		return frame{}, errors.New("control frame too large")
 	}
 
 	maskKey := make([]byte, 4)
 	if _, err := io.ReadFull(c.rw, maskKey); err != nil {
-		return 0, nil, err
+		return frame{}, err
 	}
 
 	payload := make([]byte, payloadLen)
 	if payloadLen > 0 {
 		if _, err := io.ReadFull(c.rw, payload); err != nil {
-			return 0, nil, err
+			return frame{}, err
 		}
 	}
 	for i := int64(0); i < payloadLen; i++ {
 		payload[i] ^= maskKey[i%4]
 	}
 
-	return opcode, payload, nil
+	return frame{fin: fin, compressed: rsv1, opcode: opcode, payload: payload}, nil
 }
 
 func acceptKey(key string) string {
@@ -0,0 +1,105 @@
+package realtime
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// deflateExtension is the permessage-deflate response. Both sides reset their
+// compression context per message, so every message inflates independently.
+const deflateExtension = "permessage-deflate; server_no_context_takeover; client_no_context_takeover"
+
+// deflateTail is the sync-flush marker stripped from compressed payloads (RFC 7692).
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+// negotiateSubprotocol returns the first supported protocol the client offered.
+func negotiateSubprotocol(h http.Header, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+	offered := map[string]struct{}{}
+	for _, value := range h.Values("Sec-WebSocket-Protocol") {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				offered[token] = struct{}{}
+			}
+		}
+	}
+	for _, protocol := range supported {
+		if _, ok := offered[protocol]; ok {
+			return protocol
+		}
+	}
+	return ""
+}
+
+// offersDeflate reports whether the client offered permessage-deflate with
+// parameters the server can honor.
+func offersDeflate(h http.Header) bool {
+	for _, value := range h.Values("Sec-WebSocket-Extensions") {
+		for _, offer := range strings.Split(value, ",") {
+			params := strings.Split(offer, ";")
+			if !strings.EqualFold(strings.TrimSpace(params[0]), "permessage-deflate") {
+				continue
+			}
+			if deflateParamsSupported(params[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func deflateParamsSupported(params []string) bool {
+	for _, param := range params {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch name {
+		case "server_no_context_takeover", "client_no_context_takeover", "client_max_window_bits":
+		case "server_max_window_bits":
+			// compress/flate always uses a 32KB window.
+			if value != "15" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func deflateMessage(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), deflateTail), nil
+}
+
+func inflateMessage(payload []byte, maxSize int64) ([]byte, error) {
+	// Restore the sync-flush marker, then add an empty final block so the
+	// reader reports io.EOF instead of io.ErrUnexpectedEOF.
+	source := io.MultiReader(bytes.NewReader(payload), bytes.NewReader(deflateTail), bytes.NewReader([]byte{0x01, 0x00, 0x00, 0xff, 0xff}))
+	reader := flate.NewReader(source)
+	defer reader.Close()
+
+	message, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(message)) > maxSize {
+		return nil, ErrMessageTooLarge
+	}
+	return message, nil
+}
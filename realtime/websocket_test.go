@@ -2,9 +2,16 @@ package realtime
 
 import (
 	"bufio"
+	"errors"
 	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/devmarvs/bebo"
 )
 
 func TestAcceptKey(t *testing.T) {
@@ -75,3 +82,350 @@ func writeMaskedText(conn net.Conn, payload []byte) error {
 	_, err := conn.Write(frame)
 	return err
 }
+
+func TestConnReadFragmentedMessage(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{})
+
+	go func() {
+		_ = writeMaskedFrame(client, false, OpText, []byte("hel"))
+		_ = writeMaskedFrame(client, true, OpPing, []byte("hi"))
+		_ = writeMaskedFrame(client, false, OpContinuation, []byte("lo "))
+		_ = writeMaskedFrame(client, true, OpContinuation, []byte("world"))
+	}()
+
+	pongCh := make(chan []byte, 1)
+	go func() {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(client, header); err != nil {
+			pongCh <- nil
+			return
+		}
+		payload := make([]byte, int(header[1]&0x7F))
+		_, _ = io.ReadFull(client, payload)
+		if header[0]&0x0F != OpPong {
+			payload = nil
+		}
+		pongCh <- payload
+	}()
+
+	opcode, payload, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	if opcode != OpText {
+		t.Fatalf("expected opcode %d, got %d", OpText, opcode)
+	}
+	if string(payload) != "hello world" {
+		t.Fatalf("expected payload %q, got %q", "hello world", string(payload))
+	}
+	if pong := <-pongCh; string(pong) != "hi" {
+		t.Fatalf("expected pong %q, got %q", "hi", string(pong))
+	}
+}
+
+func TestConnReadFragmentedMessageTooLarge(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{MaxMessageSize: 8})
+
+	go func() {
+		_ = writeMaskedFrame(client, false, OpText, []byte("hello"))
+		_ = writeMaskedFrame(client, true, OpContinuation, []byte("world"))
+	}()
+	closeCode := readCloseCode(client)
+
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected message too large error, got %v", err)
+	}
+	if code := <-closeCode; code != CloseMessageTooBig {
+		t.Fatalf("expected close code %d, got %d", CloseMessageTooBig, code)
+	}
+}
+
+func TestConnReadUnexpectedContinuation(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{})
+
+	go func() {
+		_ = writeMaskedFrame(client, true, OpContinuation, []byte("oops"))
+	}()
+
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Fatalf("expected continuation error")
+	}
+}
+
+func TestConnKeepaliveClosesWithoutPong(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{})
+	conn.StartKeepalive(10*time.Millisecond, 20*time.Millisecond)
+	defer conn.Close()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(client, header); err != nil {
+		t.Fatalf("read ping: %v", err)
+	}
+	if header[0]&0x0F != OpPing {
+		t.Fatalf("expected ping opcode, got %d", header[0]&0x0F)
+	}
+
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := client.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected connection closed after missed pong, got %v", err)
+	}
+}
+
+func TestConnKeepaliveStaysOpenWithPongs(t *testing.T) {
+	server, client := net.Pipe()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	conn.StartKeepalive(10*time.Millisecond, 50*time.Millisecond)
+
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	for pings := 0; pings < 3; pings++ {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(client, header); err != nil {
+			t.Fatalf("read ping %d: %v", pings, err)
+		}
+		if header[0]&0x0F != OpPing {
+			t.Fatalf("expected ping opcode, got %d", header[0]&0x0F)
+		}
+		if err := writeMaskedFrame(client, true, OpPong, nil); err != nil {
+			t.Fatalf("write pong: %v", err)
+		}
+	}
+
+	_ = client.Close()
+	_ = conn.Close()
+	_ = conn.Close()
+}
+
+func writeMaskedFrame(conn net.Conn, fin bool, opcode int, payload []byte) error {
+	maskKey := []byte{1, 2, 3, 4}
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first, 0x80 | byte(len(payload)), maskKey[0], maskKey[1], maskKey[2], maskKey[3]}
+	for i, b := range payload {
+		frame = append(frame, b^maskKey[i%4])
+	}
+	_, err := conn.Write(frame)
+	return err
+}
+
+func TestNegotiateSubprotocol(t *testing.T) {
+	header := http.Header{}
+	header.Add("Sec-WebSocket-Protocol", "chat, graphql-ws")
+	header.Add("Sec-WebSocket-Protocol", "graphql-transport-ws")
+
+	if got := negotiateSubprotocol(header, []string{"graphql-transport-ws", "graphql-ws"}); got != "graphql-transport-ws" {
+		t.Fatalf("expected server preference, got %q", got)
+	}
+	if got := negotiateSubprotocol(header, []string{"mqtt"}); got != "" {
+		t.Fatalf("expected no subprotocol, got %q", got)
+	}
+}
+
+func TestOffersDeflate(t *testing.T) {
+	cases := map[string]bool{
+		"permessage-deflate; client_max_window_bits":                        true,
+		"permessage-deflate; server_max_window_bits=10, permessage-deflate": true,
+		"permessage-deflate; server_max_window_bits=10":                     false,
+		"x-webkit-deflate-frame":                                            false,
+	}
+	for value, want := range cases {
+		header := http.Header{}
+		header.Set("Sec-WebSocket-Extensions", value)
+		if got := offersDeflate(header); got != want {
+			t.Fatalf("%q: expected %v, got %v", value, want, got)
+		}
+	}
+}
+
+func TestUpgradeNegotiatesSubprotocolAndCompression(t *testing.T) {
+	app := bebo.New()
+	app.GET("/ws", func(ctx *bebo.Context) error {
+		conn, err := Upgrade(ctx, WebSocketOptions{
+			Subprotocols:      []string{"graphql-transport-ws"},
+			EnableCompression: true,
+		})
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		msg, err := conn.ReadText()
+		if err != nil {
+			return nil
+		}
+		_ = conn.WriteText(conn.Subprotocol() + ":" + msg)
+		return nil
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	client, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+	_ = client.SetDeadline(time.Now().Add(time.Second))
+
+	handshake := "GET /ws HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		"Sec-WebSocket-Protocol: graphql-ws, graphql-transport-ws\r\n" +
+		"Sec-WebSocket-Extensions: permessage-deflate; client_max_window_bits\r\n\r\n"
+	if _, err := client.Write([]byte(handshake)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	reader := bufio.NewReader(client)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Protocol"); got != "graphql-transport-ws" {
+		t.Fatalf("expected negotiated subprotocol, got %q", got)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Extensions"); !strings.HasPrefix(got, "permessage-deflate") {
+		t.Fatalf("expected permessage-deflate, got %q", got)
+	}
+
+	compressed, err := deflateMessage([]byte("hello"))
+	if err != nil {
+		t.Fatalf("deflate: %v", err)
+	}
+	maskKey := []byte{1, 2, 3, 4}
+	frame := []byte{0xC1, 0x80 | byte(len(compressed)), maskKey[0], maskKey[1], maskKey[2], maskKey[3]}
+	for i, b := range compressed {
+		frame = append(frame, b^maskKey[i%4])
+	}
+	if _, err := client.Write(frame); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	if header[0]&0x40 == 0 {
+		t.Fatalf("expected compressed response frame")
+	}
+	payload := make([]byte, int(header[1]&0x7F))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	message, err := inflateMessage(payload, 1<<20)
+	if err != nil {
+		t.Fatalf("inflate: %v", err)
+	}
+	if string(message) != "graphql-transport-ws:hello" {
+		t.Fatalf("unexpected message %q", string(message))
+	}
+}
+
+func TestReadRejectsCompressedFrameWithoutNegotiation(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{})
+	go func() {
+		_, _ = client.Write([]byte{0xC1, 0x80, 1, 2, 3, 4})
+	}()
+
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Fatalf("expected reserved bits error")
+	}
+}
+
+func TestConnReadRateLimitClosesWithPolicyViolation(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{MaxMessagesPerSecond: 2})
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			if err := writeMaskedText(client, []byte("spam")); err != nil {
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 2; i++ {
+		if _, err := conn.ReadText(); err != nil {
+			t.Fatalf("read %d within burst: %v", i, err)
+		}
+	}
+
+	closeCode := readCloseCode(client)
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if code := <-closeCode; code != ClosePolicyViolation {
+		t.Fatalf("expected close code %d, got %d", ClosePolicyViolation, code)
+	}
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Fatal("expected connection to be closed")
+	}
+}
+
+func TestConnReadRateLimitRefills(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	conn := newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), WebSocketOptions{MaxMessagesPerSecond: 50, MessageBurst: 1})
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			_ = writeMaskedText(client, []byte("ok"))
+			time.Sleep(40 * time.Millisecond)
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		if _, err := conn.ReadText(); err != nil {
+			t.Fatalf("read %d paced under the limit: %v", i, err)
+		}
+	}
+}
+
+// readCloseCode reads one frame from the client side and reports its close
+// code, or -1 when the frame is not a close frame.
+func readCloseCode(client net.Conn) <-chan int {
+	codes := make(chan int, 1)
+	go func() {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(client, header); err != nil {
+			codes <- -1
+			return
+		}
+		payload := make([]byte, int(header[1]&0x7F))
+		if _, err := io.ReadFull(client, payload); err != nil || header[0]&0x0F != OpClose || len(payload) < 2 {
+			codes <- -1
+			return
+		}
+		codes <- int(payload[0])<<8 | int(payload[1])
+	}()
+	return codes
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package redis
+
+import "net"
+
+// connCheck is a no-op where non-blocking socket reads are unavailable;
+// a closed connection then surfaces as an error on the next command.
+func connCheck(net.Conn) error {
+	return nil
+}
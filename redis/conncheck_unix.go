@@ -0,0 +1,46 @@
+//go:build unix
+
+package redis
+
+import (
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+var errUnexpectedRead = errors.New("redis: unexpected read from idle connection")
+
+// connCheck does a non-blocking read on the socket: EAGAIN means the
+// connection is open and idle, while EOF or data means it can't be reused.
+func connCheck(conn net.Conn) error {
+	sysConn, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+	raw, err := sysConn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var checkErr error
+	err = raw.Read(func(fd uintptr) bool {
+		var buf [1]byte
+		n, err := syscall.Read(int(fd), buf[:])
+		switch {
+		case n == 0 && err == nil:
+			checkErr = io.EOF
+		case n > 0:
+			checkErr = errUnexpectedRead
+		case errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EWOULDBLOCK):
+			checkErr = nil
+		default:
+			checkErr = err
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return checkErr
+}
@@ -0,0 +1,172 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// ErrTxAborted indicates EXEC returned a null reply because the transaction
+// was aborted, such as by a WATCH-ed key changing.
+var ErrTxAborted = errors.New("redis: transaction aborted")
+
+// Reply is the result of one queued command. Err is ErrNil for a nil reply
+// or the server's error reply for that command.
+type Reply struct {
+	Value any
+	Err   error
+}
+
+// Pipeline queues commands and sends them in one round trip. It is not safe
+// for concurrent use.
+type Pipeline struct {
+	client   *Client
+	tx       bool
+	commands [][]string
+}
+
+// Pipeline starts a batch of commands sent together on Exec.
+func (c *Client) Pipeline() *Pipeline {
+	return &Pipeline{client: c}
+}
+
+// TxPipeline starts a batch wrapped in MULTI/EXEC so the commands run
+// atomically.
+func (c *Client) TxPipeline() *Pipeline {
+	return &Pipeline{client: c, tx: true}
+}
+
+// Tx runs the commands queued by fn in a MULTI/EXEC transaction.
+func (c *Client) Tx(ctx context.Context, fn func(*Pipeline)) ([]Reply, error) {
+	pipe := c.TxPipeline()
+	fn(pipe)
+	return pipe.Exec(ctx)
+}
+
+// Queue adds a command to the batch.
+func (p *Pipeline) Queue(args ...string) {
+	p.commands = append(p.commands, args)
+}
+
+// Len returns the number of queued commands.
+func (p *Pipeline) Len() int {
+	return len(p.commands)
+}
+
+// Exec sends the queued commands and returns their replies in order. The
+// returned error covers the batch as a whole (connection failures, or a
+// transaction the server rejected); per-command errors are in each Reply.
+// The queue is emptied either way.
+func (p *Pipeline) Exec(ctx context.Context) ([]Reply, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	commands := p.commands
+	p.commands = nil
+	if len(commands) == 0 {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	conn, err := p.client.pool.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var replies []Reply
+	if p.tx {
+		replies, err = conn.execTx(ctx, commands)
+	} else {
+		replies, err = conn.execPipeline(ctx, commands)
+	}
+	var reply replyError
+	p.client.pool.put(conn, err != nil && !errors.As(err, &reply) && !errors.Is(err, ErrTxAborted))
+	return replies, err
+}
+
+func (c *redisConn) execPipeline(ctx context.Context, commands [][]string) ([]Reply, error) {
+	if err := c.writeCommands(ctx, commands); err != nil {
+		return nil, err
+	}
+	replies := make([]Reply, len(commands))
+	for i := range replies {
+		reply, err := c.readReply(ctx)
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+func (c *redisConn) execTx(ctx context.Context, commands [][]string) ([]Reply, error) {
+	batch := make([][]string, 0, len(commands)+2)
+	batch = append(batch, []string{"MULTI"})
+	batch = append(batch, commands...)
+	batch = append(batch, []string{"EXEC"})
+	if err := c.writeCommands(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	// MULTI and each queued command reply +OK/+QUEUED or an error; read them
+	// all so the connection stays in sync, then EXEC's array of results.
+	var queueErr error
+	for i := 0; i < len(commands)+1; i++ {
+		reply, err := c.readReply(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if reply.Err != nil && queueErr == nil {
+			queueErr = reply.Err
+		}
+	}
+
+	line, err := c.readLine(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if line[0] != '*' {
+		if _, err := c.parseResponse(ctx, line); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("redis: unexpected EXEC reply")
+	}
+	count, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, ErrTxAborted
+	}
+	if queueErr != nil {
+		return nil, queueErr
+	}
+
+	replies := make([]Reply, count)
+	for i := range replies {
+		reply, err := c.readReply(ctx)
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+// readReply reads one response, keeping server error replies per command.
+func (c *redisConn) readReply(ctx context.Context) (Reply, error) {
+	value, err := c.readResponse(ctx)
+	var reply replyError
+	if errors.As(err, &reply) {
+		return Reply{Err: err}, nil
+	}
+	if err != nil {
+		return Reply{}, err
+	}
+	if value == nil {
+		return Reply{Err: ErrNil}, nil
+	}
+	return Reply{Value: value}, nil
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo/internal/redistest"
+)
+
+func TestPipelineRepliesInOrder(t *testing.T) {
+	addr, shutdown := redistest.Start(t)
+	defer shutdown()
+	client := New(Options{Address: addr})
+	defer client.Close()
+
+	pipe := client.Pipeline()
+	pipe.Queue("SET", "greeting", "hello")
+	pipe.Queue("INCR", "counter")
+	pipe.Queue("INCR", "counter")
+	pipe.Queue("GET", "greeting")
+	pipe.Queue("GET", "missing")
+	pipe.Queue("NOPE")
+	if pipe.Len() != 6 {
+		t.Fatalf("expected 6 queued commands, got %d", pipe.Len())
+	}
+
+	replies, err := pipe.Exec(context.Background())
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if len(replies) != 6 {
+		t.Fatalf("expected 6 replies, got %d", len(replies))
+	}
+	if replies[0].Value != "OK" || replies[2].Value != int64(2) || string(replies[3].Value.([]byte)) != "hello" {
+		t.Fatalf("unexpected replies: %+v", replies)
+	}
+	if !errors.Is(replies[4].Err, ErrNil) {
+		t.Fatalf("expected ErrNil for missing key, got %v", replies[4].Err)
+	}
+	if replies[5].Err == nil {
+		t.Fatalf("expected error reply for unknown command")
+	}
+	if pipe.Len() != 0 {
+		t.Fatalf("expected queue to be emptied")
+	}
+
+	if value, err := client.Do("GET", "counter"); err != nil || string(value.([]byte)) != "2" {
+		t.Fatalf("expected connection to stay usable, got %v %v", value, err)
+	}
+}
+
+func TestTxRunsCommandsAtomically(t *testing.T) {
+	addr, shutdown := redistest.Start(t)
+	defer shutdown()
+	client := New(Options{Address: addr})
+	defer client.Close()
+
+	replies, err := client.Tx(context.Background(), func(tx *Pipeline) {
+		tx.Queue("INCR", "visits")
+		tx.Queue("SET", "last", "home")
+		tx.Queue("GET", "last")
+	})
+	if err != nil {
+		t.Fatalf("tx: %v", err)
+	}
+	if len(replies) != 3 || replies[0].Value != int64(1) || string(replies[2].Value.([]byte)) != "home" {
+		t.Fatalf("unexpected replies: %+v", replies)
+	}
+}
+
+func TestTxAbortsOnQueueError(t *testing.T) {
+	addr, shutdown := redistest.Start(t)
+	defer shutdown()
+	client := New(Options{Address: addr})
+	defer client.Close()
+
+	_, err := client.Tx(context.Background(), func(tx *Pipeline) {
+		tx.Queue("SET", "key", "value")
+		tx.Queue("NOPE")
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "EXECABORT") {
+		t.Fatalf("expected EXECABORT, got %v", err)
+	}
+	if _, err := client.Do("GET", "key"); !errors.Is(err, ErrNil) {
+		t.Fatalf("expected aborted transaction to leave key unset, got %v", err)
+	}
+}
+
+func TestPipelineEmpty(t *testing.T) {
+	client := New(Options{Address: "127.0.0.1:1"})
+	replies, err := client.Pipeline().Exec(context.Background())
+	if err != nil || replies != nil {
+		t.Fatalf("expected empty pipeline to be a no-op, got %v %v", replies, err)
+	}
+}
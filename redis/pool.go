@@ -0,0 +1,139 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed indicates the client was closed.
+var ErrClosed = errors.New("redis: client closed")
+
+// pool reuses connections, keeping at most maxIdle idle and, when active is
+// set, at most cap(active) checked out at once.
+type pool struct {
+	dial        func(context.Context) (*redisConn, error)
+	maxIdle     int
+	idleTimeout time.Duration
+	active      chan struct{}
+
+	mu     sync.Mutex
+	idle   []*redisConn // oldest first
+	closed bool
+}
+
+func newPool(options Options, dial func(context.Context) (*redisConn, error)) *pool {
+	p := &pool{dial: dial, maxIdle: options.MaxIdle, idleTimeout: options.IdleTimeout}
+	if options.MaxActive > 0 {
+		p.active = make(chan struct{}, options.MaxActive)
+	}
+	return p
+}
+
+// get borrows an idle connection that is still open, or dials a new one. It
+// waits for a free slot when MaxActive connections are checked out.
+func (p *pool) get(ctx context.Context) (*redisConn, error) {
+	if p.active != nil {
+		select {
+		case p.active <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			p.release()
+			return nil, ErrClosed
+		}
+		p.pruneLocked(time.Now())
+		n := len(p.idle)
+		if n == 0 {
+			p.mu.Unlock()
+			break
+		}
+		conn := p.idle[n-1]
+		p.idle[n-1] = nil
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+
+		if conn.alive() {
+			return conn, nil
+		}
+		conn.close()
+	}
+
+	conn, err := p.dial(ctx)
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// put returns a borrowed connection. Broken connections are closed, as are
+// connections beyond MaxIdle.
+func (p *pool) put(conn *redisConn, broken bool) {
+	defer p.release()
+	if broken {
+		conn.close()
+		return
+	}
+
+	now := time.Now()
+	conn.idleSince = now
+	p.mu.Lock()
+	p.pruneLocked(now)
+	if p.closed || len(p.idle) >= p.maxIdle {
+		p.mu.Unlock()
+		conn.close()
+		return
+	}
+	p.idle = append(p.idle, conn)
+	p.mu.Unlock()
+}
+
+func (p *pool) release() {
+	if p.active != nil {
+		<-p.active
+	}
+}
+
+// pruneLocked closes idle connections unused for longer than idleTimeout.
+func (p *pool) pruneLocked(now time.Time) {
+	if p.idleTimeout <= 0 {
+		return
+	}
+	expired := 0
+	for expired < len(p.idle) && now.Sub(p.idle[expired].idleSince) > p.idleTimeout {
+		p.idle[expired].close()
+		p.idle[expired] = nil
+		expired++
+	}
+	if expired > 0 {
+		p.idle = append(p.idle[:0], p.idle[expired:]...)
+	}
+}
+
+func (p *pool) close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.closed = true
+	p.mu.Unlock()
+	for _, conn := range idle {
+		conn.close()
+	}
+}
+
+// alive reports whether an idle connection can be reused: nothing is
+// buffered and the socket is neither closed nor carrying stray data.
+func (c *redisConn) alive() bool {
+	if c.rw.Reader.Buffered() > 0 {
+		return false
+	}
+	return connCheck(c.conn) == nil
+}
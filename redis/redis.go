@@ -16,6 +16,9 @@ import (
 var ErrNil = errors.New("redis: nil")
 
 // Options configures Redis connections.
+// MaxIdle defaults to 8 pooled idle connections (negative disables reuse),
+// MaxActive caps checked-out connections (zero is unlimited), and IdleTimeout
+// defaults to 5 minutes (negative keeps idle connections indefinitely).
 type Options struct {
 	Network      string
 	Address      string
@@ -25,11 +28,16 @@ type Options struct {
 	DialTimeout  time.Duration
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	MaxIdle      int
+	MaxActive    int
+	IdleTimeout  time.Duration
 }
 
-// Client executes Redis commands with per-call connections.
+// Client executes Redis commands over a pool of reused connections.
+// It is safe for concurrent use.
 type Client struct {
 	options Options
+	pool    *pool
 }
 
 // New creates a Redis client with defaults.
@@ -50,7 +58,22 @@ func New(options Options) *Client {
 	if cfg.WriteTimeout == 0 {
 		cfg.WriteTimeout = 2 * time.Second
 	}
-	return &Client{options: cfg}
+	if cfg.MaxIdle == 0 {
+		cfg.MaxIdle = 8
+	}
+	if cfg.IdleTimeout == 0 {
+		cfg.IdleTimeout = 5 * time.Minute
+	}
+	client := &Client{options: cfg}
+	client.pool = newPool(cfg, client.dial)
+	return client
+}
+
+// Close closes idle connections; commands issued afterwards fail with
+// ErrClosed. Connections in use are closed when returned.
+func (c *Client) Close() error {
+	c.pool.close()
+	return nil
 }
 
 // Do executes a Redis command.
@@ -67,13 +90,14 @@ func (c *Client) DoContext(ctx context.Context, args ...string) (any, error) {
 		return nil, err
 	}
 
-	conn, err := c.dial(ctx)
+	conn, err := c.pool.get(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.close()
 
 	resp, err := conn.do(ctx, args...)
+	var reply replyError
+	c.pool.put(conn, err != nil && !errors.As(err, &reply))
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +148,14 @@ type redisConn struct {
 	rw           *bufio.ReadWriter
 	readTimeout  time.Duration
 	writeTimeout time.Duration
+	idleSince    time.Time
+}
+
+// replyError is an error reply from the server; the connection stays usable.
+type replyError string
+
+func (e replyError) Error() string {
+	return string(e)
 }
 
 func (c *redisConn) do(ctx context.Context, args ...string) (any, error) {
@@ -138,40 +170,58 @@ func (c *redisConn) close() {
 }
 
 func (c *redisConn) writeCommand(ctx context.Context, args []string) error {
+	return c.writeCommands(ctx, [][]string{args})
+}
+
+// writeCommands buffers every command and flushes them in one write.
+func (c *redisConn) writeCommands(ctx context.Context, commands [][]string) error {
 	if err := c.applyDeadline(ctx, c.writeTimeout, true); err != nil {
 		return err
 	}
 
-	if _, err := fmt.Fprintf(c.rw, "*%d\r\n", len(args)); err != nil {
-		return err
-	}
-	for _, arg := range args {
-		if _, err := fmt.Fprintf(c.rw, "$%d\r\n%s\r\n", len(arg), arg); err != nil {
+	for _, args := range commands {
+		if _, err := fmt.Fprintf(c.rw, "*%d\r\n", len(args)); err != nil {
 			return err
 		}
+		for _, arg := range args {
+			if _, err := fmt.Fprintf(c.rw, "$%d\r\n%s\r\n", len(arg), arg); err != nil {
+				return err
+			}
+		}
 	}
 	return c.rw.Flush()
 }
 
 func (c *redisConn) readResponse(ctx context.Context) (any, error) {
-	if err := c.applyDeadline(ctx, c.readTimeout, false); err != nil {
+	line, err := c.readLine(ctx)
+	if err != nil {
 		return nil, err
 	}
+	return c.parseResponse(ctx, line)
+}
+
+func (c *redisConn) readLine(ctx context.Context) (string, error) {
+	if err := c.applyDeadline(ctx, c.readTimeout, false); err != nil {
+		return "", err
+	}
 
 	line, err := c.rw.ReadString('\n')
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	line = strings.TrimSuffix(line, "\r\n")
 	if line == "" {
-		return nil, errors.New("redis: empty response")
+		return "", errors.New("redis: empty response")
 	}
+	return line, nil
+}
 
+func (c *redisConn) parseResponse(ctx context.Context, line string) (any, error) {
 	switch line[0] {
 	case '+':
 		return line[1:], nil
 	case '-':
-		return nil, errors.New(line[1:])
+		return nil, replyError(line[1:])
 	case ':':
 		value, err := strconv.ParseInt(line[1:], 10, 64)
 		if err != nil {
@@ -197,13 +247,25 @@ func (c *redisConn) readResponse(ctx context.Context) (any, error) {
 			return nil, err
 		}
 		items := make([]any, 0, count)
+		var replyErr error
 		for i := 0; i < count; i++ {
 			item, err := c.readResponse(ctx)
+			var reply replyError
+			if errors.As(err, &reply) {
+				// Keep reading so the connection stays in sync for reuse.
+				if replyErr == nil {
+					replyErr = err
+				}
+				continue
+			}
 			if err != nil {
 				return nil, err
 			}
 			items = append(items, item)
 		}
+		if replyErr != nil {
+			return nil, replyErr
+		}
 		return items, nil
 	default:
 		return nil, errors.New("redis: unknown response")
@@ -0,0 +1,216 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingServer answers every command with +OK and counts accepted connections.
+// "SLOW" waits before replying, "BAD" replies with an error, and "HANGUP"
+// closes the connection after replying.
+type countingServer struct {
+	addr     string
+	accepted atomic.Int32
+}
+
+func startCountingServer(t *testing.T) *countingServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &countingServer{addr: listener.Addr().String()}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.accepted.Add(1)
+			go server.handle(conn)
+		}
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		wg.Wait()
+	})
+	return server
+}
+
+func (s *countingServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		switch strings.ToUpper(args[0]) {
+		case "SLOW":
+			time.Sleep(50 * time.Millisecond)
+			_, _ = conn.Write([]byte("+OK\r\n"))
+		case "BAD":
+			_, _ = conn.Write([]byte("-ERR bad command\r\n"))
+		case "HANGUP":
+			_, _ = conn.Write([]byte("+OK\r\n"))
+			return
+		default:
+			_, _ = conn.Write([]byte("+OK\r\n"))
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || count < 1 {
+		return nil, errors.New("bad command")
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func TestClientReusesConnections(t *testing.T) {
+	server := startCountingServer(t)
+	client := New(Options{Address: server.addr})
+	defer client.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.Do("PING"); err != nil {
+			t.Fatalf("do: %v", err)
+		}
+	}
+	if got := server.accepted.Load(); got != 1 {
+		t.Fatalf("expected 1 connection, got %d", got)
+	}
+}
+
+func TestClientKeepsConnectionAfterErrorReply(t *testing.T) {
+	server := startCountingServer(t)
+	client := New(Options{Address: server.addr})
+	defer client.Close()
+
+	if _, err := client.Do("BAD"); err == nil || err.Error() != "ERR bad command" {
+		t.Fatalf("expected error reply, got %v", err)
+	}
+	if _, err := client.Do("PING"); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if got := server.accepted.Load(); got != 1 {
+		t.Fatalf("expected error replies to keep the connection, got %d connections", got)
+	}
+}
+
+func TestClientReplacesClosedIdleConnection(t *testing.T) {
+	server := startCountingServer(t)
+	client := New(Options{Address: server.addr})
+	defer client.Close()
+
+	if _, err := client.Do("HANGUP"); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := client.Do("PING"); err != nil {
+		t.Fatalf("expected health check to replace the closed connection, got %v", err)
+	}
+	if got := server.accepted.Load(); got != 2 {
+		t.Fatalf("expected 2 connections, got %d", got)
+	}
+}
+
+func TestClientEvictsIdleConnections(t *testing.T) {
+	server := startCountingServer(t)
+	client := New(Options{Address: server.addr, IdleTimeout: 10 * time.Millisecond})
+	defer client.Close()
+
+	if _, err := client.Do("PING"); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.Do("PING"); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if got := server.accepted.Load(); got != 2 {
+		t.Fatalf("expected idle connection to be evicted, got %d connections", got)
+	}
+}
+
+func TestClientMaxActive(t *testing.T) {
+	server := startCountingServer(t)
+	client := New(Options{Address: server.addr, MaxActive: 1})
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Do("SLOW")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("do: %v", err)
+		}
+	}
+	if got := server.accepted.Load(); got != 1 {
+		t.Fatalf("expected MaxActive to cap connections at 1, got %d", got)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = client.Do("SLOW")
+	}()
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.DoContext(ctx, "PING"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected wait for a free connection to honor the context, got %v", err)
+	}
+	<-done
+}
+
+func TestClientClose(t *testing.T) {
+	server := startCountingServer(t)
+	client := New(Options{Address: server.addr})
+
+	if _, err := client.Do("PING"); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := client.Do("PING"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
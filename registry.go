@@ -2,6 +2,7 @@ package bebo
 
 import (
 	"errors"
+	"sort"
 	"strings"
 	"sync"
 
@@ -260,6 +261,42 @@ func (r *Registry) Validator(name string) (validate.ValidatorFunc, error) {
 	return fn, nil
 }
 
+// RegistrySnapshot lists registered component names, each sorted.
+type RegistrySnapshot struct {
+	Plugins        []string `json:"plugins"`
+	Middleware     []string `json:"middleware"`
+	Authenticators []string `json:"authenticators"`
+	Caches         []string `json:"caches"`
+	Validators     []string `json:"validators"`
+}
+
+// List returns the names of everything registered, for diagnostics pages.
+func (r *Registry) List() RegistrySnapshot {
+	if r == nil {
+		return RegistrySnapshot{}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return RegistrySnapshot{
+		Plugins:        sortedKeys(r.plugins),
+		Middleware:     sortedKeys(r.middleware),
+		Authenticators: sortedKeys(r.authenticators),
+		Caches:         sortedKeys(r.caches),
+		Validators:     sortedKeys(r.validators),
+	}
+}
+
+func sortedKeys[V any](entries map[string]V) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func normalizeRegistryName(name string) (string, error) {
 	key := strings.ToLower(strings.TrimSpace(name))
 	if key == "" {
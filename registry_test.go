@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/devmarvs/bebo/cache"
 	"github.com/devmarvs/bebo/validate"
 )
 
@@ -64,3 +65,42 @@ func (p *testPlugin) Register(r *Registry) error {
 		return nil
 	})
 }
+
+func TestRegistryList(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Use(&testPlugin{}); err != nil {
+		t.Fatalf("use plugin: %v", err)
+	}
+	for _, name := range []string{"timeout", "Audit", "cors"} {
+		if err := reg.RegisterMiddleware(name, func(map[string]any) (Middleware, error) { return nil, nil }); err != nil {
+			t.Fatalf("register middleware %s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"jwt", "basic"} {
+		if err := reg.RegisterAuthenticator(name, func(map[string]any) (Authenticator, error) { return nil, nil }); err != nil {
+			t.Fatalf("register authenticator %s: %v", name, err)
+		}
+	}
+	if err := reg.RegisterCache("memory", func(map[string]any) (cache.Store, error) { return nil, nil }); err != nil {
+		t.Fatalf("register cache: %v", err)
+	}
+	if err := reg.RegisterValidator("list_test_slug", func(string, reflect.Value, string) *validate.FieldError { return nil }); err != nil {
+		t.Fatalf("register validator: %v", err)
+	}
+
+	want := RegistrySnapshot{
+		Plugins:        []string{"test"},
+		Middleware:     []string{"audit", "cors", "timeout"},
+		Authenticators: []string{"basic", "jwt"},
+		Caches:         []string{"memory"},
+		Validators:     []string{"list_test_slug", "starts_with"},
+	}
+	if got := reg.List(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	var empty *Registry
+	if got := empty.List(); got.Plugins != nil {
+		t.Fatalf("expected empty snapshot for nil registry, got %+v", got)
+	}
+}
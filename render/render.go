@@ -1,6 +1,7 @@
 package render
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,6 +31,16 @@ type Options struct {
 	IncludeSubdirs bool
 	// Partials lists glob patterns (relative to the templates dir) treated as partials.
 	Partials []string
+	// Layouts lists additional layouts selectable per render with RenderWithLayout.
+	Layouts []string
+	// PageLayouts maps page globs (relative to the templates dir, e.g.
+	// "admin/**/*.html") to the layout used by Render for matching pages. The
+	// longest matching pattern wins; an empty layout renders the page bare.
+	PageLayouts map[string]string
+	// Watch, with Reload, watches the template directory with fsnotify and
+	// re-parses only after a file changes. When the watcher cannot start, or
+	// templates come from an fs.FS without DevDir, every render re-parses.
+	Watch bool
 }
 
 // RenderFunc allows custom rendering.
@@ -37,17 +48,24 @@ type RenderFunc func(http.ResponseWriter) error
 
 // Engine renders HTML templates.
 type Engine struct {
-	fs        fs.FS
-	dir       string
-	devDir    string
-	layout    string
-	templates map[string]*template.Template
-	loaded    bool
-	funcs     FuncMap
-	reload    bool
-	partials  []string
-	recursive bool
-	mu        sync.RWMutex
+	fs          fs.FS
+	dir         string
+	devDir      string
+	layout      string
+	layouts     []string
+	pageLayouts map[string]string
+	templates   map[string]map[string]executor
+	masters     map[string]map[string]*masterSet
+	ext         string
+	text        bool
+	loaded      bool
+	funcs       FuncMap
+	reload      bool
+	partials    []string
+	recursive   bool
+	watch       bool
+	watcher     *templateWatcher
+	mu          sync.RWMutex
 }
 
 // NewEngine builds a template engine and loads templates.
@@ -57,32 +75,74 @@ func NewEngine(dir, layout string) (*Engine, error) {
 
 // NewEngineWithOptions builds a template engine with options.
 func NewEngineWithOptions(dir string, options Options) (*Engine, error) {
-	engine := &Engine{
-		dir:       dir,
-		devDir:    options.DevDir,
-		layout:    options.Layout,
-		funcs:     options.Funcs,
-		reload:    options.Reload,
-		partials:  options.Partials,
-		recursive: options.IncludeSubdirs,
+	engine := newEngineFromOptions(options)
+	engine.dir = dir
+	if err := engine.Load(); err != nil {
+		return engine, err
 	}
-	return engine, engine.Load()
+	engine.startWatch()
+	return engine, nil
 }
 
 // NewEngineFromFS builds a template engine from an fs.FS.
 func NewEngineFromFS(fsys fs.FS, dir string, options Options) (*Engine, error) {
-	cleanDir := strings.TrimPrefix(path.Clean("/"+dir), "/")
-	engine := &Engine{
-		fs:        fsys,
-		dir:       cleanDir,
-		devDir:    options.DevDir,
-		layout:    options.Layout,
-		funcs:     options.Funcs,
-		reload:    options.Reload,
-		partials:  options.Partials,
-		recursive: options.IncludeSubdirs,
+	engine := newEngineFromOptions(options)
+	engine.fs = fsys
+	engine.dir = strings.TrimPrefix(path.Clean("/"+dir), "/")
+	if err := engine.Load(); err != nil {
+		return engine, err
+	}
+	engine.startWatch()
+	return engine, nil
+}
+
+// newEngineFromOptions returns an unloaded HTML engine configured by options.
+func newEngineFromOptions(options Options) *Engine {
+	return &Engine{
+		devDir:      options.DevDir,
+		layout:      options.Layout,
+		layouts:     options.Layouts,
+		pageLayouts: options.PageLayouts,
+		ext:         htmlExt,
+		funcs:       options.Funcs,
+		reload:      options.Reload,
+		partials:    options.Partials,
+		recursive:   options.IncludeSubdirs,
+		watch:       options.Watch,
 	}
-	return engine, engine.Load()
+}
+
+// startWatch begins watching the on-disk template dir when Reload and Watch
+// are set. Failures leave the per-render reload in place.
+func (e *Engine) startWatch() {
+	if !e.reload || !e.watch {
+		return
+	}
+	dir := e.dir
+	if e.fs != nil {
+		dir = e.devDir
+	}
+	if dir == "" {
+		return
+	}
+	watcher, err := watchDir(dir, e.recursive)
+	if err != nil {
+		return
+	}
+	e.watcher = watcher
+}
+
+// Watching reports whether reloads are driven by a file watcher.
+func (e *Engine) Watching() bool {
+	return e.watcher != nil
+}
+
+// Close stops the template watcher, if any.
+func (e *Engine) Close() error {
+	if e.watcher == nil {
+		return nil
+	}
+	return e.watcher.close()
 }
 
 // AddFuncs registers template functions.
@@ -118,7 +178,7 @@ func (e *Engine) loadFromDisk(dir string) error {
 		return nil
 	}
 
-	files, err := findTemplateFiles(dir, e.recursive)
+	files, err := findTemplateFiles(dir, e.ext, e.recursive)
 	if err != nil {
 		return err
 	}
@@ -126,12 +186,21 @@ func (e *Engine) loadFromDisk(dir string) error {
 		return errors.New("no templates found")
 	}
 
-	layoutPath := ""
-	if e.layout != "" {
-		layoutPath = filepath.Join(dir, e.layout)
+	layouts := e.layoutNames()
+	layoutPaths := make([]string, len(layouts))
+	for i, layout := range layouts {
+		if layout != "" {
+			layoutPaths[i] = filepath.Join(dir, layout)
+		}
+	}
+	layoutFiles := append([]string{}, layoutPaths...)
+	for _, layout := range e.pageLayouts {
+		if layout != "" {
+			layoutFiles = append(layoutFiles, filepath.Join(dir, layout))
+		}
 	}
 
-	pages, partials, err := classifyTemplates(dir, files, layoutPath, e.partials)
+	pages, partials, err := classifyTemplates(dir, files, layoutFiles, e.partials)
 	if err != nil {
 		return err
 	}
@@ -139,21 +208,45 @@ func (e *Engine) loadFromDisk(dir string) error {
 		return errors.New("no page templates found")
 	}
 
-	templates := make(map[string]*template.Template)
-	for _, page := range pages {
-		pageName, err := templateName(dir, page)
-		if err != nil {
-			return err
-		}
-		tmpl, err := parseTemplateSet(dir, layoutPath, page, partials, e.funcs)
-		if err != nil {
-			return err
+	templates := make(map[string]map[string]executor, len(layouts))
+	masters := make(map[string]map[string]*masterSet, len(layouts))
+	for i, layout := range layouts {
+		set := make(map[string]executor, len(pages))
+		master := make(map[string]*masterSet, len(pages))
+		for _, page := range pages {
+			pageName, err := templateName(dir, page)
+			if err != nil {
+				return err
+			}
+			layoutPath := layoutPaths[i]
+			if i == 0 {
+				if assigned, ok := e.pageLayout(pageName); ok {
+					layoutPath = ""
+					if assigned != "" {
+						layoutPath = filepath.Join(dir, assigned)
+					}
+				}
+			}
+			tmpl, err := parseTemplateSet(dir, layoutPath, page, partials, e.newSet)
+			if err != nil {
+				return err
+			}
+			// Execute a clone so the parsed set stays clonable for renders
+			// with request funcs (html/template cannot clone after executing).
+			exec, err := tmpl.Clone()
+			if err != nil {
+				return err
+			}
+			set[pageName] = exec.Template()
+			master[pageName] = newMasterSet(tmpl)
 		}
-		templates[pageName] = tmpl
+		templates[layout] = set
+		masters[layout] = master
 	}
 
 	e.mu.Lock()
 	e.templates = templates
+	e.masters = masters
 	e.loaded = true
 	e.mu.Unlock()
 
@@ -165,7 +258,7 @@ func (e *Engine) loadFromFS() error {
 		return nil
 	}
 
-	files, err := findTemplateFilesFS(e.fs, e.dir, e.recursive)
+	files, err := findTemplateFilesFS(e.fs, e.dir, e.ext, e.recursive)
 	if err != nil {
 		return err
 	}
@@ -173,12 +266,21 @@ func (e *Engine) loadFromFS() error {
 		return errors.New("no templates found")
 	}
 
-	layoutPath := ""
-	if e.layout != "" {
-		layoutPath = path.Join(e.dir, e.layout)
+	layouts := e.layoutNames()
+	layoutPaths := make([]string, len(layouts))
+	for i, layout := range layouts {
+		if layout != "" {
+			layoutPaths[i] = path.Join(e.dir, layout)
+		}
+	}
+	layoutFiles := append([]string{}, layoutPaths...)
+	for _, layout := range e.pageLayouts {
+		if layout != "" {
+			layoutFiles = append(layoutFiles, path.Join(e.dir, layout))
+		}
 	}
 
-	pages, partials, err := classifyTemplatesFS(e.dir, files, layoutPath, e.partials)
+	pages, partials, err := classifyTemplatesFS(e.dir, files, layoutFiles, e.partials)
 	if err != nil {
 		return err
 	}
@@ -186,54 +288,221 @@ func (e *Engine) loadFromFS() error {
 		return errors.New("no page templates found")
 	}
 
-	templates := make(map[string]*template.Template)
-	for _, page := range pages {
-		pageName, err := templateNameFS(e.dir, page)
-		if err != nil {
-			return err
-		}
-		tmpl, err := parseTemplateSetFS(e.fs, e.dir, layoutPath, page, partials, e.funcs)
-		if err != nil {
-			return err
+	templates := make(map[string]map[string]executor, len(layouts))
+	masters := make(map[string]map[string]*masterSet, len(layouts))
+	for i, layout := range layouts {
+		set := make(map[string]executor, len(pages))
+		master := make(map[string]*masterSet, len(pages))
+		for _, page := range pages {
+			pageName, err := templateNameFS(e.dir, page)
+			if err != nil {
+				return err
+			}
+			layoutPath := layoutPaths[i]
+			if i == 0 {
+				if assigned, ok := e.pageLayout(pageName); ok {
+					layoutPath = ""
+					if assigned != "" {
+						layoutPath = path.Join(e.dir, assigned)
+					}
+				}
+			}
+			tmpl, err := parseTemplateSetFS(e.fs, e.dir, layoutPath, page, partials, e.newSet)
+			if err != nil {
+				return err
+			}
+			// Execute a clone so the parsed set stays clonable for renders
+			// with request funcs (html/template cannot clone after executing).
+			exec, err := tmpl.Clone()
+			if err != nil {
+				return err
+			}
+			set[pageName] = exec.Template()
+			master[pageName] = newMasterSet(tmpl)
 		}
-		templates[pageName] = tmpl
+		templates[layout] = set
+		masters[layout] = master
 	}
 
 	e.mu.Lock()
 	e.templates = templates
+	e.masters = masters
 	e.loaded = true
 	e.mu.Unlock()
 
 	return nil
 }
 
-// Render writes a template response.
+// layoutNames returns the default layout followed by any additional layouts.
+func (e *Engine) layoutNames() []string {
+	names := []string{e.layout}
+	for _, layout := range e.layouts {
+		if layout == "" || layout == e.layout {
+			continue
+		}
+		names = append(names, layout)
+	}
+	return names
+}
+
+// pageLayout returns the layout PageLayouts assigns to pageName, preferring
+// the longest matching pattern.
+func (e *Engine) pageLayout(pageName string) (string, bool) {
+	best, layout := "", ""
+	matched := false
+	for pattern, candidate := range e.pageLayouts {
+		if !matchPattern(pattern, pageName) {
+			continue
+		}
+		if !matched || len(pattern) > len(best) || len(pattern) == len(best) && pattern < best {
+			best, layout, matched = pattern, candidate, true
+		}
+	}
+	return layout, matched
+}
+
+// LocalizedName returns the locale-specific variant of name, such as
+// "home.fr.html" for "home.html" and locale "fr", when it is loaded. Regional
+// locales ("fr-CA") fall back to their base language before the base name.
+func (e *Engine) LocalizedName(name, locale string) string {
+	if locale == "" {
+		return name
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	set := e.templates[e.layout]
+	if len(set) == 0 {
+		return name
+	}
+
+	base, ext := name, ""
+	if strings.HasSuffix(name, e.ext) {
+		base, ext = strings.TrimSuffix(name, e.ext), e.ext
+	}
+	candidates := []string{locale}
+	if lang, _, ok := strings.Cut(locale, "-"); ok && lang != "" {
+		candidates = append(candidates, lang)
+	}
+	for _, candidate := range candidates {
+		variant := base + "." + candidate + ext
+		if _, ok := set[variant]; ok {
+			return variant
+		}
+		if ext == "" {
+			if _, ok := set[variant+e.ext]; ok {
+				return variant
+			}
+		}
+	}
+	return name
+}
+
+// Render writes a template response using the default layout, or the layout
+// Options.PageLayouts assigns to the page.
 func (e *Engine) Render(w http.ResponseWriter, status int, name string, data any) error {
-	if e.reload {
+	return e.RenderWithLayout(w, status, e.layout, name, data)
+}
+
+// RenderWithLayout writes a template response wrapped in the given layout.
+// The layout must be the default layout or one listed in Options.Layouts; an
+// empty layout selects the default, which also applies Options.PageLayouts.
+func (e *Engine) RenderWithLayout(w http.ResponseWriter, status int, layout, name string, data any) error {
+	return e.RenderWithFuncs(w, status, layout, name, data, nil)
+}
+
+// RenderWithFuncs is RenderWithLayout with extra template funcs for this
+// render only, typically request-scoped helpers such as a CSRF field. The
+// funcs must also be registered on the engine (e.g. as placeholders) so
+// templates parse. Pages that call one of funcs are rendered from a clone of
+// their template set, which costs more than a plain render; other pages reuse
+// the parsed templates.
+func (e *Engine) RenderWithFuncs(w http.ResponseWriter, status int, layout, name string, data any, funcs FuncMap) error {
+	tmpl, err := e.lookup(layout, name, funcs)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	return tmpl.Execute(w, data)
+}
+
+// RenderToString executes a template with the default layout (or its page
+// layout) and returns the output, e.g. for email bodies or cached fragments.
+func (e *Engine) RenderToString(name string, data any) (string, error) {
+	out, err := e.RenderToBytes(e.layout, name, data)
+	return string(out), err
+}
+
+// RenderToBytes executes a template wrapped in layout into a buffer. Reload
+// and layout selection behave as in RenderWithLayout.
+func (e *Engine) RenderToBytes(layout, name string, data any) ([]byte, error) {
+	return e.RenderToBytesWithFuncs(layout, name, data, nil)
+}
+
+// RenderToBytesWithFuncs is RenderToBytes with extra template funcs for this
+// render only; see RenderWithFuncs.
+func (e *Engine) RenderToBytesWithFuncs(layout, name string, data any, funcs FuncMap) ([]byte, error) {
+	tmpl, err := e.lookup(layout, name, funcs)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// lookup reloads templates when needed and finds name in the layout's set.
+// With funcs, it returns a clone of the parsed set with funcs added.
+func (e *Engine) lookup(layout, name string, funcs FuncMap) (executor, error) {
+	if e.reload && (e.watcher == nil || e.watcher.changed()) {
 		if err := e.Load(); err != nil {
-			return err
+			if e.watcher != nil {
+				// Retry on the next render until the templates parse again.
+				e.watcher.dirty.Store(true)
+			}
+			return nil, err
 		}
 	}
+	if layout == "" {
+		layout = e.layout
+	}
 
 	e.mu.RLock()
 	if !e.loaded || len(e.templates) == 0 {
 		e.mu.RUnlock()
-		return http.ErrMissingFile
+		return nil, http.ErrMissingFile
 	}
 
-	tmpl, ok := e.templates[name]
-	if !ok && !strings.HasSuffix(name, ".html") {
-		tmpl, ok = e.templates[name+".html"]
+	set, ok := e.templates[layout]
+	if !ok {
+		e.mu.RUnlock()
+		return nil, fmt.Errorf("layout %s not configured", layout)
+	}
+	if !strings.HasSuffix(name, e.ext) {
+		if _, ok := set[name]; !ok {
+			name += e.ext
+		}
 	}
+	tmpl, ok := set[name]
+	master := e.masters[layout][name]
 	e.mu.RUnlock()
 
 	if !ok {
-		return http.ErrMissingFile
+		return nil, http.ErrMissingFile
 	}
-
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	w.WriteHeader(status)
-	return tmpl.Execute(w, data)
+	// Only pages that call a request func pay for a clone.
+	if len(funcs) == 0 || !master.references(funcs) {
+		return tmpl, nil
+	}
+	clone, err := master.set.Clone()
+	if err != nil {
+		return nil, err
+	}
+	clone.Funcs(funcs)
+	return clone.Template(), nil
 }
 
 // JSON writes a JSON response.
@@ -257,9 +526,9 @@ func Custom(w http.ResponseWriter, status int, fn RenderFunc) error {
 	return fn(w)
 }
 
-func findTemplateFiles(dir string, recursive bool) ([]string, error) {
+func findTemplateFiles(dir, ext string, recursive bool) ([]string, error) {
 	if !recursive {
-		entries, err := filepath.Glob(filepath.Join(dir, "*.html"))
+		entries, err := filepath.Glob(filepath.Join(dir, "*"+ext))
 		if err != nil {
 			return nil, err
 		}
@@ -275,7 +544,7 @@ func findTemplateFiles(dir string, recursive bool) ([]string, error) {
 		if d.IsDir() {
 			return nil
 		}
-		if strings.HasSuffix(d.Name(), ".html") {
+		if strings.HasSuffix(d.Name(), ext) {
 			entries = append(entries, path)
 		}
 		return nil
@@ -287,16 +556,16 @@ func findTemplateFiles(dir string, recursive bool) ([]string, error) {
 	return entries, nil
 }
 
-func findTemplateFilesFS(fsys fs.FS, dir string, recursive bool) ([]string, error) {
+func findTemplateFilesFS(fsys fs.FS, dir, ext string, recursive bool) ([]string, error) {
 	root := dir
 	if root == "" {
 		root = "."
 	}
 
 	if !recursive {
-		pattern := path.Join(dir, "*.html")
+		pattern := path.Join(dir, "*"+ext)
 		if dir == "" {
-			pattern = "*.html"
+			pattern = "*" + ext
 		}
 		entries, err := fs.Glob(fsys, pattern)
 		if err != nil {
@@ -314,7 +583,7 @@ func findTemplateFilesFS(fsys fs.FS, dir string, recursive bool) ([]string, erro
 		if d.IsDir() {
 			return nil
 		}
-		if strings.HasSuffix(d.Name(), ".html") {
+		if strings.HasSuffix(d.Name(), ext) {
 			entries = append(entries, filePath)
 		}
 		return nil
@@ -334,14 +603,19 @@ func findTemplateFilesFS(fsys fs.FS, dir string, recursive bool) ([]string, erro
 	return entries, nil
 }
 
-func classifyTemplatesFS(dir string, files []string, layoutPath string, patterns []string) ([]string, []string, error) {
+func classifyTemplatesFS(dir string, files []string, layoutPaths []string, patterns []string) ([]string, []string, error) {
 	var pages []string
 	var partials []string
-	layoutPath = path.Clean(layoutPath)
+	layouts := make(map[string]struct{}, len(layoutPaths))
+	for _, layoutPath := range layoutPaths {
+		if layoutPath != "" {
+			layouts[path.Clean(layoutPath)] = struct{}{}
+		}
+	}
 
 	for _, file := range files {
 		clean := path.Clean(file)
-		if layoutPath != "" && clean == layoutPath {
+		if _, ok := layouts[clean]; ok {
 			continue
 		}
 		name, err := templateNameFS(dir, clean)
@@ -376,14 +650,19 @@ func templateNameFS(dir, file string) (string, error) {
 	return "", fmt.Errorf("template path %s is outside %s", clean, base)
 }
 
-func classifyTemplates(dir string, files []string, layoutPath string, patterns []string) ([]string, []string, error) {
+func classifyTemplates(dir string, files []string, layoutPaths []string, patterns []string) ([]string, []string, error) {
 	var pages []string
 	var partials []string
-	layoutPath = filepath.Clean(layoutPath)
+	layouts := make(map[string]struct{}, len(layoutPaths))
+	for _, layoutPath := range layoutPaths {
+		if layoutPath != "" {
+			layouts[filepath.Clean(layoutPath)] = struct{}{}
+		}
+	}
 
 	for _, file := range files {
 		clean := filepath.Clean(file)
-		if layoutPath != "" && clean == layoutPath {
+		if _, ok := layouts[clean]; ok {
 			continue
 		}
 		name, err := templateName(dir, clean)
@@ -476,7 +755,7 @@ func globToRegex(pattern string) (*regexp.Regexp, error) {
 	return regexp.Compile(builder.String())
 }
 
-func parseTemplateSetFS(fsys fs.FS, dir, layoutPath, pagePath string, partials []string, funcs FuncMap) (*template.Template, error) {
+func parseTemplateSetFS(fsys fs.FS, dir, layoutPath, pagePath string, partials []string, newSet func(string) templateSet) (templateSet, error) {
 	pageName, err := templateNameFS(dir, pagePath)
 	if err != nil {
 		return nil, err
@@ -495,10 +774,7 @@ func parseTemplateSetFS(fsys fs.FS, dir, layoutPath, pagePath string, partials [
 		baseName = layoutName
 	}
 
-	base := template.New(baseName)
-	if funcs != nil {
-		base = base.Funcs(template.FuncMap(funcs))
-	}
+	base := newSet(baseName)
 
 	if layoutPath != "" {
 		if err := parseTemplateFileFS(fsys, base, layoutPath, baseName); err != nil {
@@ -526,7 +802,7 @@ func parseTemplateSetFS(fsys fs.FS, dir, layoutPath, pagePath string, partials [
 	return base, nil
 }
 
-func parseTemplateSet(dir, layoutPath, pagePath string, partials []string, funcs FuncMap) (*template.Template, error) {
+func parseTemplateSet(dir, layoutPath, pagePath string, partials []string, newSet func(string) templateSet) (templateSet, error) {
 	pageName, err := templateName(dir, pagePath)
 	if err != nil {
 		return nil, err
@@ -545,10 +821,7 @@ func parseTemplateSet(dir, layoutPath, pagePath string, partials []string, funcs
 		baseName = layoutName
 	}
 
-	base := template.New(baseName)
-	if funcs != nil {
-		base = base.Funcs(template.FuncMap(funcs))
-	}
+	base := newSet(baseName)
 
 	if layoutPath != "" {
 		if err := parseTemplateFile(base, layoutPath, baseName); err != nil {
@@ -576,40 +849,36 @@ func parseTemplateSet(dir, layoutPath, pagePath string, partials []string, funcs
 	return base, nil
 }
 
-func parseTemplateFile(base *template.Template, filePath, name string) error {
+func parseTemplateFile(base templateSet, filePath, name string) error {
 	contents, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
 	}
 
 	if name == base.Name() {
-		_, err = base.Parse(string(contents))
-		return err
+		return base.Parse(string(contents))
 	}
 
-	if base.Lookup(name) != nil {
+	if base.Defined(name) {
 		return fmt.Errorf("template %s already defined", name)
 	}
 
-	_, err = base.New(name).Parse(string(contents))
-	return err
+	return base.Define(name, string(contents))
 }
 
-func parseTemplateFileFS(fsys fs.FS, base *template.Template, filePath, name string) error {
+func parseTemplateFileFS(fsys fs.FS, base templateSet, filePath, name string) error {
 	contents, err := fs.ReadFile(fsys, filePath)
 	if err != nil {
 		return err
 	}
 
 	if name == base.Name() {
-		_, err = base.Parse(string(contents))
-		return err
+		return base.Parse(string(contents))
 	}
 
-	if base.Lookup(name) != nil {
+	if base.Defined(name) {
 		return fmt.Errorf("template %s already defined", name)
 	}
 
-	_, err = base.New(name).Parse(string(contents))
-	return err
+	return base.Define(name, string(contents))
 }
@@ -29,3 +29,81 @@ func TestEngineFromFS(t *testing.T) {
 		t.Fatalf("expected rendered content, got %q", body)
 	}
 }
+
+func TestEngineFromFSWithLayout(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/layout.html": {Data: []byte("public:{{ template \"content\" . }}")},
+		"templates/admin.html":  {Data: []byte("admin:{{ template \"content\" . }}")},
+		"templates/home.html":   {Data: []byte("{{ define \"content\" }}{{ . }}{{ end }}")},
+	}
+
+	engine, err := NewEngineFromFS(fsys, "templates", Options{Layout: "layout.html", Layouts: []string{"admin.html"}})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := engine.RenderWithLayout(rec, http.StatusOK, "admin.html", "home.html", "world"); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if body := rec.Body.String(); body != "admin:world" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestEngineFromFSPageLayouts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/layout.html":       {Data: []byte("public:{{ template \"content\" . }}")},
+		"templates/admin/layout.html": {Data: []byte("admin:{{ template \"content\" . }}")},
+		"templates/home.html":         {Data: []byte("{{ define \"content\" }}{{ . }}{{ end }}")},
+		"templates/admin/users.html":  {Data: []byte("{{ define \"content\" }}users {{ . }}{{ end }}")},
+	}
+
+	engine, err := NewEngineFromFS(fsys, "templates", Options{
+		Layout:         "layout.html",
+		IncludeSubdirs: true,
+		PageLayouts:    map[string]string{"admin/*.html": "admin/layout.html"},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := engine.Render(rec, http.StatusOK, "admin/users.html", "list"); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if body := rec.Body.String(); body != "admin:users list" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	rec = httptest.NewRecorder()
+	if err := engine.Render(rec, http.StatusOK, "home.html", "world"); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if body := rec.Body.String(); body != "public:world" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestEngineFromFSBlocks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/layout.html": {Data: []byte("{{ block \"title\" . }}Site{{ end }}:{{ block \"content\" . }}empty{{ end }}")},
+		"templates/home.html":   {Data: []byte("{{ define \"title\" }}Home{{ end }}{{ define \"content\" }}{{ . }}{{ end }}")},
+		"templates/blank.html":  {Data: []byte("")},
+	}
+
+	engine, err := NewEngineFromFS(fsys, "templates", Options{Layout: "layout.html"})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+
+	for name, want := range map[string]string{"home.html": "Home:world", "blank.html": "Site:empty"} {
+		rec := httptest.NewRecorder()
+		if err := engine.Render(rec, http.StatusOK, name, "world"); err != nil {
+			t.Fatalf("render %s: %v", name, err)
+		}
+		if body := rec.Body.String(); body != want {
+			t.Fatalf("unexpected %s body: %q", name, body)
+		}
+	}
+}
@@ -43,3 +43,289 @@ func TestRenderWithPartials(t *testing.T) {
 		t.Fatalf("unexpected body: %s", body)
 	}
 }
+
+func TestRenderWithLayout(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"layout.html": "public:{{ template \"content\" . }}",
+		"admin.html":  "admin:{{ template \"content\" . }}",
+		"home.html":   "{{ define \"content\" }}{{ .Title }}{{ end }}",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	engine, err := NewEngineWithOptions(dir, Options{Layout: "layout.html", Layouts: []string{"admin.html"}})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	data := map[string]string{"Title": "home"}
+	rec := httptest.NewRecorder()
+	if err := engine.Render(rec, 200, "home.html", data); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if body := rec.Body.String(); body != "public:home" {
+		t.Fatalf("unexpected default layout body: %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	if err := engine.RenderWithLayout(rec, 200, "admin.html", "home", data); err != nil {
+		t.Fatalf("render with layout: %v", err)
+	}
+	if body := rec.Body.String(); body != "admin:home" {
+		t.Fatalf("unexpected admin layout body: %s", body)
+	}
+
+	if err := engine.RenderWithLayout(httptest.NewRecorder(), 200, "admin.html", "admin.html", data); err == nil {
+		t.Fatalf("expected layouts to be excluded from pages")
+	}
+	if err := engine.RenderWithLayout(httptest.NewRecorder(), 200, "missing.html", "home.html", data); err == nil {
+		t.Fatalf("expected error for unknown layout")
+	}
+}
+
+func TestRenderPageLayouts(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"layout.html":               "public:{{ template \"content\" . }}",
+		"admin/layout.html":         "admin:{{ template \"content\" . }}",
+		"admin/reports/layout.html": "reports:{{ template \"content\" . }}",
+		"home.html":                 "{{ define \"content\" }}home{{ end }}",
+		"admin/users.html":          "{{ define \"content\" }}users{{ end }}",
+		"admin/reports/daily.html":  "{{ define \"content\" }}daily{{ end }}",
+		"fragments/row.html":        "{{ define \"content\" }}row{{ end }}{{ template \"content\" . }}",
+	}
+	for name, contents := range files {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	engine, err := NewEngineWithOptions(dir, Options{
+		Layout:         "layout.html",
+		IncludeSubdirs: true,
+		PageLayouts: map[string]string{
+			"admin/**":             "admin/layout.html",
+			"admin/reports/*.html": "admin/reports/layout.html",
+			"fragments/*.html":     "",
+		},
+	})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	cases := map[string]string{
+		"home.html":                "public:home",
+		"admin/users":              "admin:users",
+		"admin/reports/daily.html": "reports:daily",
+		"fragments/row.html":       "row",
+	}
+	for name, want := range cases {
+		rec := httptest.NewRecorder()
+		if err := engine.Render(rec, 200, name, nil); err != nil {
+			t.Fatalf("render %s: %v", name, err)
+		}
+		if body := rec.Body.String(); body != want {
+			t.Fatalf("render %s: expected %q, got %q", name, want, body)
+		}
+	}
+
+	if err := engine.Render(httptest.NewRecorder(), 200, "admin/layout.html", nil); err == nil {
+		t.Fatalf("expected page layouts to be excluded from pages")
+	}
+}
+
+func TestRenderBlocksAndSections(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"layout.html": `<title>{{ block "title" . }}Site{{ end }}</title>` +
+			`<main>{{ block "content" . }}{{ end }}</main>` +
+			`<aside>{{ block "sidebar" . }}default sidebar{{ end }}</aside>` +
+			`{{ block "scripts" . }}{{ end }}`,
+		"home.html": `{{ define "title" }}Home | {{ .Name }}{{ end }}` +
+			`{{ define "content" }}Hello {{ .Name }}{{ end }}` +
+			`{{ define "scripts" }}<script src="/home.js"></script>{{ end }}`,
+		"about.html": `{{ define "content" }}About{{ end }}` +
+			`{{ define "sidebar" }}links{{ end }}`,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	engine, err := NewEngineWithOptions(dir, Options{Layout: "layout.html"})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	cases := map[string]string{
+		"home.html":  `<title>Home | Ada</title><main>Hello Ada</main><aside>default sidebar</aside><script src="/home.js"></script>`,
+		"about.html": `<title>Site</title><main>About</main><aside>links</aside>`,
+		// Rendering home first must not leak its overrides into about.
+	}
+	for _, name := range []string{"home.html", "about.html", "home.html"} {
+		rec := httptest.NewRecorder()
+		if err := engine.Render(rec, 200, name, map[string]string{"Name": "Ada"}); err != nil {
+			t.Fatalf("render %s: %v", name, err)
+		}
+		if body := rec.Body.String(); body != cases[name] {
+			t.Fatalf("unexpected %s body: %s", name, body)
+		}
+	}
+}
+
+func TestRenderPageOverridesPartialBlock(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "partials"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	files := map[string]string{
+		"layout.html":        `{{ template "nav" . }}|{{ template "content" . }}`,
+		"partials/_nav.html": `{{ define "nav" }}{{ block "nav_extra" . }}none{{ end }}{{ end }}`,
+		"plain.html":         `{{ define "content" }}plain{{ end }}`,
+		"dashboard.html":     `{{ define "content" }}dash{{ end }}{{ define "nav_extra" }}admin{{ end }}`,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	engine, err := NewEngineWithOptions(dir, Options{Layout: "layout.html", IncludeSubdirs: true})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	for name, want := range map[string]string{"plain.html": "none|plain", "dashboard.html": "admin|dash"} {
+		rec := httptest.NewRecorder()
+		if err := engine.Render(rec, 200, name, nil); err != nil {
+			t.Fatalf("render %s: %v", name, err)
+		}
+		if body := rec.Body.String(); body != want {
+			t.Fatalf("unexpected %s body: %s", name, body)
+		}
+	}
+}
+
+func TestRenderToString(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"layout.html": "<body>{{ template \"content\" . }}</body>",
+		"admin.html":  "<admin>{{ template \"content\" . }}</admin>",
+		"email.html":  "{{ define \"content\" }}Hi {{ .Name }}{{ end }}",
+		"broken.html": "{{ define \"content\" }}{{ .Name.Missing }}{{ end }}",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	engine, err := NewEngineWithOptions(dir, Options{Layout: "layout.html", Layouts: []string{"admin.html"}, Reload: true})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+	data := map[string]string{"Name": "Ada"}
+
+	out, err := engine.RenderToString("email", data)
+	if err != nil || out != "<body>Hi Ada</body>" {
+		t.Fatalf("render to string: %q %v", out, err)
+	}
+	bytesOut, err := engine.RenderToBytes("admin.html", "email.html", data)
+	if err != nil || string(bytesOut) != "<admin>Hi Ada</admin>" {
+		t.Fatalf("render to bytes: %q %v", bytesOut, err)
+	}
+
+	// Reload picks up edits, matching Render.
+	if err := os.WriteFile(filepath.Join(dir, "email.html"), []byte("{{ define \"content\" }}Bye {{ .Name }}{{ end }}"), 0o644); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	if out, err := engine.RenderToString("email.html", data); err != nil || out != "<body>Bye Ada</body>" {
+		t.Fatalf("expected reloaded output, got %q %v", out, err)
+	}
+
+	if _, err := engine.RenderToString("missing.html", data); err == nil {
+		t.Fatalf("expected error for missing template")
+	}
+	if out, err := engine.RenderToString("broken.html", data); err == nil || out != "" {
+		t.Fatalf("expected execution error without partial output, got %q %v", out, err)
+	}
+}
+
+func TestRenderWithFuncs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greet.html"), []byte(`{{ user }}:{{ . }}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	engine, err := NewEngineWithOptions(dir, Options{Funcs: FuncMap{"user": func() string { return "guest" }}})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	render := func(funcs FuncMap) string {
+		rec := httptest.NewRecorder()
+		if err := engine.RenderWithFuncs(rec, 200, "", "greet", "hi", funcs); err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		return rec.Body.String()
+	}
+	if body := render(nil); body != "guest:hi" {
+		t.Fatalf("unexpected default body %q", body)
+	}
+	if body := render(FuncMap{"user": func() string { return "<ada>" }}); body != "&lt;ada&gt;:hi" {
+		t.Fatalf("unexpected request funcs body %q", body)
+	}
+	if body := render(nil); body != "guest:hi" {
+		t.Fatalf("request funcs leaked into the engine: %q", body)
+	}
+}
+
+func TestRenderWithFuncsClonesOnlyReferencingPages(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "plain.html"), []byte(`{{ . }}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nested.html"), []byte(`{{ with . }}{{ if true }}{{ user | printf "%s" }}{{ end }}{{ end }}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	engine, err := NewEngineWithOptions(dir, Options{Funcs: FuncMap{"user": func() string { return "guest" }}})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+	funcs := FuncMap{"user": func() string { return "ada" }}
+
+	exec, err := engine.lookup("", "plain", funcs)
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if exec != engine.templates[""]["plain.html"] {
+		t.Fatalf("expected page without request funcs to reuse the parsed template")
+	}
+
+	exec, err = engine.lookup("", "nested", funcs)
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if exec == engine.templates[""]["nested.html"] {
+		t.Fatalf("expected page calling a request func to be cloned")
+	}
+	out := httptest.NewRecorder()
+	if err := exec.Execute(out, "hi"); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if out.Body.String() != "ada" {
+		t.Fatalf("unexpected body %q", out.Body.String())
+	}
+}
@@ -0,0 +1,186 @@
+package render
+
+import (
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+	"text/template/parse"
+)
+
+const (
+	htmlExt = ".html"
+	textExt = ".txt"
+)
+
+// executor is a parsed template ready to run; both *html/template.Template
+// and *text/template.Template satisfy it.
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// templateSet is the parsing surface shared by html/template and
+// text/template, so both engines load layouts and partials the same way.
+type templateSet interface {
+	Name() string
+	Parse(text string) error
+	Define(name, text string) error
+	Defined(name string) bool
+	Funcs(funcs FuncMap)
+	Clone() (templateSet, error)
+	Template() executor
+	Trees() []*parse.Tree
+}
+
+// masterSet is a parsed page kept clonable for renders with request funcs,
+// together with the identifiers (func names) its templates reference.
+type masterSet struct {
+	set    templateSet
+	idents map[string]struct{}
+}
+
+func newMasterSet(set templateSet) *masterSet {
+	idents := map[string]struct{}{}
+	for _, tree := range set.Trees() {
+		if tree != nil {
+			collectIdentifiers(tree.Root, idents)
+		}
+	}
+	return &masterSet{set: set, idents: idents}
+}
+
+// references reports whether the page calls any of funcs.
+func (m *masterSet) references(funcs FuncMap) bool {
+	for name := range funcs {
+		if _, ok := m.idents[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func collectIdentifiers(node parse.Node, idents map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectIdentifiers(child, idents)
+		}
+	case *parse.ActionNode:
+		collectIdentifiers(n.Pipe, idents)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectIdentifiers(cmd, idents)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectIdentifiers(arg, idents)
+		}
+	case *parse.ChainNode:
+		collectIdentifiers(n.Node, idents)
+	case *parse.IdentifierNode:
+		idents[n.Ident] = struct{}{}
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, idents)
+	case *parse.RangeNode:
+		collectBranch(&n.BranchNode, idents)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, idents)
+	case *parse.TemplateNode:
+		collectIdentifiers(n.Pipe, idents)
+	}
+}
+
+func collectBranch(n *parse.BranchNode, idents map[string]struct{}) {
+	collectIdentifiers(n.Pipe, idents)
+	collectIdentifiers(n.List, idents)
+	collectIdentifiers(n.ElseList, idents)
+}
+
+// newSet starts a template set using the engine's template package.
+func (e *Engine) newSet(name string) templateSet {
+	if e.text {
+		tmpl := texttemplate.New(name)
+		if e.funcs != nil {
+			tmpl = tmpl.Funcs(texttemplate.FuncMap(e.funcs))
+		}
+		return textSet{tmpl}
+	}
+	tmpl := htmltemplate.New(name)
+	if e.funcs != nil {
+		tmpl = tmpl.Funcs(htmltemplate.FuncMap(e.funcs))
+	}
+	return htmlSet{tmpl}
+}
+
+type htmlSet struct{ t *htmltemplate.Template }
+
+func (s htmlSet) Name() string { return s.t.Name() }
+
+func (s htmlSet) Parse(text string) error {
+	_, err := s.t.Parse(text)
+	return err
+}
+
+func (s htmlSet) Define(name, text string) error {
+	_, err := s.t.New(name).Parse(text)
+	return err
+}
+
+func (s htmlSet) Defined(name string) bool { return s.t.Lookup(name) != nil }
+func (s htmlSet) Template() executor       { return s.t }
+func (s htmlSet) Funcs(funcs FuncMap)      { s.t.Funcs(htmltemplate.FuncMap(funcs)) }
+
+func (s htmlSet) Trees() []*parse.Tree {
+	var trees []*parse.Tree
+	for _, tmpl := range s.t.Templates() {
+		trees = append(trees, tmpl.Tree)
+	}
+	return trees
+}
+
+func (s htmlSet) Clone() (templateSet, error) {
+	clone, err := s.t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return htmlSet{clone}, nil
+}
+
+type textSet struct{ t *texttemplate.Template }
+
+func (s textSet) Name() string { return s.t.Name() }
+
+func (s textSet) Parse(text string) error {
+	_, err := s.t.Parse(text)
+	return err
+}
+
+func (s textSet) Define(name, text string) error {
+	_, err := s.t.New(name).Parse(text)
+	return err
+}
+
+func (s textSet) Defined(name string) bool { return s.t.Lookup(name) != nil }
+func (s textSet) Template() executor       { return s.t }
+func (s textSet) Funcs(funcs FuncMap)      { s.t.Funcs(texttemplate.FuncMap(funcs)) }
+
+func (s textSet) Trees() []*parse.Tree {
+	var trees []*parse.Tree
+	for _, tmpl := range s.t.Templates() {
+		trees = append(trees, tmpl.Tree)
+	}
+	return trees
+}
+
+func (s textSet) Clone() (templateSet, error) {
+	clone, err := s.t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return textSet{clone}, nil
+}
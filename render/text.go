@@ -0,0 +1,73 @@
+package render
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// TextEngine renders plain-text templates (*.txt) with text/template, so
+// output such as email bodies is not HTML-escaped. Layouts, partials,
+// page layouts, localized names and reloading work as in Engine.
+type TextEngine struct {
+	engine *Engine
+}
+
+// NewTextEngine builds a text template engine and loads *.txt templates.
+func NewTextEngine(dir string, options Options) (*TextEngine, error) {
+	engine := newEngineFromOptions(options)
+	engine.dir = dir
+	engine.ext = textExt
+	engine.text = true
+	if err := engine.Load(); err != nil {
+		return &TextEngine{engine: engine}, err
+	}
+	engine.startWatch()
+	return &TextEngine{engine: engine}, nil
+}
+
+// NewTextEngineFromFS builds a text template engine from an fs.FS.
+func NewTextEngineFromFS(fsys fs.FS, dir string, options Options) (*TextEngine, error) {
+	engine := newEngineFromOptions(options)
+	engine.fs = fsys
+	engine.dir = strings.TrimPrefix(path.Clean("/"+dir), "/")
+	engine.ext = textExt
+	engine.text = true
+	if err := engine.Load(); err != nil {
+		return &TextEngine{engine: engine}, err
+	}
+	engine.startWatch()
+	return &TextEngine{engine: engine}, nil
+}
+
+// Load parses templates.
+func (t *TextEngine) Load() error {
+	return t.engine.Load()
+}
+
+// AddFuncs registers template functions.
+func (t *TextEngine) AddFuncs(funcs FuncMap) error {
+	return t.engine.AddFuncs(funcs)
+}
+
+// LocalizedName returns the locale-specific variant of name, such as
+// "welcome.fr.txt", when it is loaded.
+func (t *TextEngine) LocalizedName(name, locale string) string {
+	return t.engine.LocalizedName(name, locale)
+}
+
+// RenderToString executes a template with the default layout (or its page
+// layout) and returns the output.
+func (t *TextEngine) RenderToString(name string, data any) (string, error) {
+	return t.engine.RenderToString(name, data)
+}
+
+// RenderToBytes executes a template wrapped in layout into a buffer.
+func (t *TextEngine) RenderToBytes(layout, name string, data any) ([]byte, error) {
+	return t.engine.RenderToBytes(layout, name, data)
+}
+
+// Close stops the template watcher, if any.
+func (t *TextEngine) Close() error {
+	return t.engine.Close()
+}
@@ -0,0 +1,67 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTextEngineDoesNotEscape(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"layout.txt":     "{{ template \"content\" . }}\n-- {{ signature }}",
+		"_footer.txt":    "{{ define \"footer\" }}Unsubscribe: {{ .Link }}{{ end }}",
+		"welcome.txt":    "{{ define \"content\" }}Hi {{ .Name | upper }} <{{ .Email }}>\n{{ template \"footer\" . }}{{ end }}",
+		"ignored.html":   "<p>not loaded</p>",
+		"welcome.fr.txt": "{{ define \"content\" }}Salut {{ .Name }}{{ end }}",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	engine, err := NewTextEngine(dir, Options{
+		Layout: "layout.txt",
+		Funcs: FuncMap{
+			"upper":     strings.ToUpper,
+			"signature": func() string { return "Tom & Jerry's" },
+		},
+	})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	data := map[string]string{"Name": "ada", "Email": "ada@example.com", "Link": "https://example.com/u?a=1&b=2"}
+	out, err := engine.RenderToString("welcome", data)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "Hi ADA <ada@example.com>\nUnsubscribe: https://example.com/u?a=1&b=2\n-- Tom & Jerry's"
+	if out != want {
+		t.Fatalf("unexpected output:\n%q\nwant\n%q", out, want)
+	}
+
+	if name := engine.LocalizedName("welcome.txt", "fr-CA"); name != "welcome.fr.txt" {
+		t.Fatalf("unexpected localized name %q", name)
+	}
+	if _, err := engine.RenderToString("ignored.html", data); err == nil {
+		t.Fatalf("expected html files to be skipped by the text engine")
+	}
+}
+
+func TestTextEngineFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"emails/reset.txt": {Data: []byte("Reset <{{ . }}>")},
+	}
+	engine, err := NewTextEngineFromFS(fsys, "emails", Options{})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+	out, err := engine.RenderToBytes("", "reset.txt", "https://x.test/?t=a&b")
+	if err != nil || string(out) != "Reset <https://x.test/?t=a&b>" {
+		t.Fatalf("unexpected output %q %v", out, err)
+	}
+}
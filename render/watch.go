@@ -0,0 +1,94 @@
+package render
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// newWatcher is swapped in tests to simulate platforms without fsnotify.
+var newWatcher = fsnotify.NewWatcher
+
+// templateWatcher marks the engine dirty when files under a directory change.
+type templateWatcher struct {
+	watcher   *fsnotify.Watcher
+	recursive bool
+	dirty     atomic.Bool
+	failed    atomic.Bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// watchDir starts watching dir, including subdirectories when recursive.
+func watchDir(dir string, recursive bool) (*templateWatcher, error) {
+	watcher, err := newWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &templateWatcher{watcher: watcher, recursive: recursive, done: make(chan struct{})}
+	if err := w.add(dir); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+func (w *templateWatcher) add(dir string) error {
+	if !w.recursive {
+		return w.watcher.Add(dir)
+	}
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return w.watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+func (w *templateWatcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) && w.recursive {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = w.add(event.Name)
+				}
+			}
+			if event.Op != fsnotify.Chmod {
+				w.dirty.Store(true)
+			}
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			// Events may have been dropped; reload on every render from now on.
+			w.failed.Store(true)
+		}
+	}
+}
+
+// changed reports whether a reload is needed and clears the dirty flag.
+func (w *templateWatcher) changed() bool {
+	return w.failed.Load() || w.dirty.Swap(false)
+}
+
+func (w *templateWatcher) close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		err = w.watcher.Close()
+		<-w.done
+	})
+	return err
+}
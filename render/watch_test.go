@@ -0,0 +1,108 @@
+package render
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func renderBody(t *testing.T, engine *Engine, name string) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	if err := engine.Render(rec, 200, name, nil); err != nil {
+		t.Fatalf("render %s: %v", name, err)
+	}
+	return rec.Body.String()
+}
+
+func TestEngineWatchReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "home.html")
+	if err := os.WriteFile(page, []byte("v1"), 0o644); err != nil {
+		t.Fatalf("write page: %v", err)
+	}
+
+	engine, err := NewEngineWithOptions(dir, Options{Reload: true, Watch: true, IncludeSubdirs: true})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+	defer engine.Close()
+	if !engine.Watching() {
+		t.Skip("fsnotify watcher unavailable")
+	}
+
+	tmpl := engine.templates[""]["home.html"]
+	if body := renderBody(t, engine, "home.html"); body != "v1" {
+		t.Fatalf("unexpected body: %s", body)
+	}
+	if engine.templates[""]["home.html"] != tmpl {
+		t.Fatal("expected templates not to be re-parsed without changes")
+	}
+
+	if err := os.WriteFile(page, []byte("v2"), 0o644); err != nil {
+		t.Fatalf("rewrite page: %v", err)
+	}
+	waitForBody(t, engine, "home.html", "v2")
+
+	// Directories created after startup are watched too.
+	sub := filepath.Join(dir, "users")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(sub, "show.html"), []byte("user"), 0o644); err != nil {
+		t.Fatalf("write nested page: %v", err)
+	}
+	waitForBody(t, engine, "users/show.html", "user")
+}
+
+func TestEngineWatchFallsBackToPerRenderReload(t *testing.T) {
+	original := newWatcher
+	newWatcher = func() (*fsnotify.Watcher, error) { return nil, errors.New("unsupported") }
+	defer func() { newWatcher = original }()
+
+	dir := t.TempDir()
+	page := filepath.Join(dir, "home.html")
+	if err := os.WriteFile(page, []byte("v1"), 0o644); err != nil {
+		t.Fatalf("write page: %v", err)
+	}
+
+	engine, err := NewEngineWithOptions(dir, Options{Reload: true, Watch: true})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+	if engine.Watching() {
+		t.Fatal("expected watcher to be unavailable")
+	}
+
+	if body := renderBody(t, engine, "home.html"); body != "v1" {
+		t.Fatalf("unexpected body: %s", body)
+	}
+	if err := os.WriteFile(page, []byte("v2"), 0o644); err != nil {
+		t.Fatalf("rewrite page: %v", err)
+	}
+	if body := renderBody(t, engine, "home.html"); body != "v2" {
+		t.Fatalf("expected immediate reload, got %s", body)
+	}
+}
+
+func waitForBody(t *testing.T, engine *Engine, name, want string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		rec := httptest.NewRecorder()
+		err := engine.Render(rec, 200, name, nil)
+		if err == nil && rec.Body.String() == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s to render %q (got %q, %v)", name, want, rec.Body.String(), err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
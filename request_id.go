@@ -21,3 +21,12 @@ func NewRequestID() string {
 func RequestIDFromHeader(r *http.Request) string {
 	return r.Header.Get(RequestIDHeader)
 }
+
+var requestIDKey = NewContextKey[string]("bebo.request_id")
+
+// SetRequestID records the request id assigned by middleware such as
+// middleware.RequestID. The default error handler adds error.request_id to
+// JSON error bodies only for requests with a recorded id.
+func SetRequestID(ctx *Context, requestID string) {
+	requestIDKey.Set(ctx, requestID)
+}
@@ -16,6 +16,9 @@ type RequestMetadata struct {
 	RequestID   string
 	Traceparent string
 	Tracestate  string
+	// Headers holds further headers to propagate, such as a custom request id
+	// header set through middleware.RequestIDWithOptions.
+	Headers http.Header
 }
 
 type requestMetadataKey struct{}
@@ -73,6 +76,11 @@ func InjectRequestMetadata(r *http.Request, metadata RequestMetadata) {
 	if metadata.Tracestate != "" {
 		r.Header.Set(TracestateHeader, metadata.Tracestate)
 	}
+	for name, values := range metadata.Headers {
+		if len(values) > 0 && values[0] != "" {
+			r.Header.Set(name, values[0])
+		}
+	}
 }
 
 // InjectRequestMetadataIfMissing sets request metadata headers only when missing.
@@ -92,6 +100,11 @@ func InjectRequestMetadataIfMissing(r *http.Request, metadata RequestMetadata) {
 	if metadata.Tracestate != "" && r.Header.Get(TracestateHeader) == "" {
 		r.Header.Set(TracestateHeader, metadata.Tracestate)
 	}
+	for name, values := range metadata.Headers {
+		if len(values) > 0 && values[0] != "" && r.Header.Get(name) == "" {
+			r.Header.Set(name, values[0])
+		}
+	}
 }
 
 // TraceIDs parses trace and span ids from traceparent.
@@ -1,12 +1,26 @@
 package bebo
 
-import "time"
+import (
+	"reflect"
+	"time"
+)
 
 type routeConfig struct {
-	name       string
-	timeout    time.Duration
-	host       string
-	middleware []Middleware
+	name         string
+	timeout      time.Duration
+	host         string
+	middleware   []Middleware
+	errorHandler ErrorHandler
+	schemas      routeSchemas
+	security     []map[string][]string
+}
+
+// routeSchemas holds the Go types documented for a route's query string and
+// JSON bodies.
+type routeSchemas struct {
+	query     reflect.Type
+	request   reflect.Type
+	responses map[int]reflect.Type
 }
 
 // RouteOption customizes route registration.
@@ -39,3 +53,57 @@ func WithMiddleware(middleware ...Middleware) RouteOption {
 		cfg.middleware = append(cfg.middleware, middleware...)
 	}
 }
+
+// WithRouteErrorHandler handles errors from this route instead of the app's
+// error handler.
+func WithRouteErrorHandler(handler ErrorHandler) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.errorHandler = handler
+	}
+}
+
+// WithQueryModel documents the struct a route binds with BindQuery, so
+// OpenAPI output lists its fields as query parameters. Names follow the
+// binding rules (`query`, then `form` or `json` tags) and `validate:"required"`
+// marks a parameter required.
+func WithQueryModel(model any) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.schemas.query = sampleType(model)
+	}
+}
+
+// WithRequestSchema documents the route's JSON request body in OpenAPI
+// output using the type of sample, e.g. WithRequestSchema(CreateUserInput{}).
+// A reflect.Type may be passed instead of a value.
+func WithRequestSchema(sample any) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.schemas.request = sampleType(sample)
+	}
+}
+
+// WithResponseSchema documents the JSON response body for status in OpenAPI
+// output. Documented responses replace the route's default response.
+func WithResponseSchema(status int, sample any) RouteOption {
+	return func(cfg *routeConfig) {
+		if cfg.schemas.responses == nil {
+			cfg.schemas.responses = map[int]reflect.Type{}
+		}
+		cfg.schemas.responses[status] = sampleType(sample)
+	}
+}
+
+// WithSecurity documents that the route requires the named OpenAPI security
+// scheme, optionally with scopes. Each call adds an alternative requirement,
+// and routes with their own requirements ignore OpenAPIOptions.Security.
+func WithSecurity(scheme string, scopes ...string) RouteOption {
+	return func(cfg *routeConfig) {
+		cfg.security = append(cfg.security, map[string][]string{scheme: append([]string{}, scopes...)})
+	}
+}
+
+func sampleType(sample any) reflect.Type {
+	if t, ok := sample.(reflect.Type); ok {
+		return t
+	}
+	return reflect.TypeOf(sample)
+}
@@ -0,0 +1,42 @@
+package session
+
+import "encoding/json"
+
+// FlashKey is the session value key holding pending flash messages.
+const FlashKey = "bebo_flash"
+
+// FlashMessage is a one-time message stored in the session.
+type FlashMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Flash queues a message for the next request. Call Save to persist it.
+func (s *Session) Flash(kind, text string) {
+	messages := decodeFlashes(s.Get(FlashKey))
+	messages = append(messages, FlashMessage{Type: kind, Text: text})
+	payload, err := json.Marshal(messages)
+	if err != nil {
+		return
+	}
+	s.Set(FlashKey, string(payload))
+}
+
+// Flashes returns pending messages and removes them from the session, so each
+// message is read exactly once. Call Save to persist the removal.
+func (s *Session) Flashes() []FlashMessage {
+	messages := decodeFlashes(s.Get(FlashKey))
+	s.Delete(FlashKey)
+	return messages
+}
+
+func decodeFlashes(value string) []FlashMessage {
+	if value == "" {
+		return nil
+	}
+	var messages []FlashMessage
+	if err := json.Unmarshal([]byte(value), &messages); err != nil {
+		return nil
+	}
+	return messages
+}
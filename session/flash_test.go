@@ -0,0 +1,49 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSessionFlashReadOnce(t *testing.T) {
+	store := NewMemoryStore("bebo_session", time.Minute)
+
+	sess, err := store.Get(httptest.NewRequest(http.MethodPost, "/notes", nil))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	sess.Flash("success", "note saved")
+	rec := httptest.NewRecorder()
+	if err := sess.Save(rec); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatalf("expected session cookie")
+	}
+
+	next := func() *Session {
+		req := httptest.NewRequest(http.MethodGet, "/notes", nil)
+		req.AddCookie(cookies[0])
+		sess, err := store.Get(req)
+		if err != nil {
+			t.Fatalf("get after redirect: %v", err)
+		}
+		return sess
+	}
+
+	redirected := next()
+	flashes := redirected.Flashes()
+	if len(flashes) != 1 || flashes[0].Type != "success" || flashes[0].Text != "note saved" {
+		t.Fatalf("unexpected flashes %+v", flashes)
+	}
+	if err := redirected.Save(httptest.NewRecorder()); err != nil {
+		t.Fatalf("save after read: %v", err)
+	}
+
+	if flashes := next().Flashes(); len(flashes) != 0 {
+		t.Fatalf("expected flash to be consumed, got %+v", flashes)
+	}
+}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"container/heap"
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -58,6 +59,7 @@ type MemoryStore struct {
 	mu          sync.RWMutex
 	sessions    map[string]memoryEntry
 	expirations expirationHeap
+	sweeper     sweeper
 }
 
 // MemoryOption configures a MemoryStore.
@@ -107,6 +109,26 @@ func NewMemoryStore(name string, ttl time.Duration, options ...MemoryOption) *Me
 	return store
 }
 
+// Cleanup removes expired sessions. Expired entries are also dropped lazily
+// as requests arrive, so a sweeper is only needed for idle stores.
+func (s *MemoryStore) Cleanup(_ context.Context) error {
+	s.mu.Lock()
+	s.cleanupExpiredLocked(time.Now())
+	s.mu.Unlock()
+	return nil
+}
+
+// StartSweeper runs Cleanup every interval until ctx is cancelled or
+// StopSweeper is called. Calling it again replaces the running sweeper.
+func (s *MemoryStore) StartSweeper(ctx context.Context, interval time.Duration) {
+	s.sweeper.start(ctx, interval, s.Cleanup)
+}
+
+// StopSweeper stops the sweeper and waits for it to exit.
+func (s *MemoryStore) StopSweeper() {
+	s.sweeper.stop()
+}
+
 // Get loads a session from the request.
 func (s *MemoryStore) Get(r *http.Request) (*Session, error) {
 	values := map[string]string{}
@@ -216,6 +238,13 @@ func (s *MemoryStore) Clear(w http.ResponseWriter, session *Session) {
 	}
 }
 
+func (s *MemoryStore) deleteID(id string) error {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	return nil
+}
+
 func (s *MemoryStore) maybeCleanup(now time.Time) {
 	if s.TTL <= 0 {
 		return
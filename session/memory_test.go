@@ -82,3 +82,41 @@ func TestMemoryStoreCleanupRemovesExpired(t *testing.T) {
 		t.Fatalf("expected expired session cleanup, got %d", got)
 	}
 }
+
+func TestMemoryStoreRegenerate(t *testing.T) {
+	store := NewMemoryStore("bebo_session", time.Minute)
+
+	sess, _ := store.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	sess.Set("cart", "3")
+	rec := httptest.NewRecorder()
+	if err := sess.Save(rec); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	fixed := rec.Result().Cookies()[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(fixed)
+	loaded, _ := store.Get(req)
+	loaded.Set("user", "123")
+	rec = httptest.NewRecorder()
+	if err := loaded.Regenerate(rec); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	fresh := rec.Result().Cookies()[0]
+	if fresh.Value == fixed.Value || loaded.ID != fresh.Value {
+		t.Fatalf("expected a new session ID, got %q (old %q)", fresh.Value, fixed.Value)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(fixed)
+	if old, _ := store.Get(req); !old.IsNew() {
+		t.Fatalf("expected old session ID to be deleted")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(fresh)
+	moved, _ := store.Get(req)
+	if moved.Get("user") != "123" || moved.Get("cart") != "3" {
+		t.Fatalf("expected values to move to the new ID, got %v", moved.Values)
+	}
+}
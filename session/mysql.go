@@ -0,0 +1,274 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultMySQLTable = "bebo_sessions"
+
+// MySQLOptions configures a MySQL store.
+type MySQLOptions struct {
+	DisableDefaults bool
+	DB              *sql.DB
+	Name            string
+	Table           string
+	TTL             time.Duration
+	Timeout         time.Duration
+	Path            string
+	Secure          bool
+	HTTPOnly        bool
+	SameSite        http.SameSite
+}
+
+// MySQLStore stores sessions in MySQL using a session ID cookie.
+// Expiry times are stored in UTC as DATETIME, so open the DB with
+// parseTime=true (go-sql-driver/mysql) to scan them.
+type MySQLStore struct {
+	DB       *sql.DB
+	Name     string
+	Table    string
+	TTL      time.Duration
+	Timeout  time.Duration
+	Path     string
+	Secure   bool
+	HTTPOnly bool
+	SameSite http.SameSite
+	now      func() time.Time
+	sweeper  sweeper
+}
+
+// NewMySQLStore builds a MySQL-backed store.
+func NewMySQLStore(options MySQLOptions) (*MySQLStore, error) {
+	if options.DB == nil {
+		return nil, errors.New("mysql db is required")
+	}
+	name := strings.TrimSpace(options.Name)
+	if name == "" {
+		name = "bebo_session"
+	}
+	table := strings.TrimSpace(options.Table)
+	if table == "" {
+		table = DefaultMySQLTable
+	}
+	if !validMySQLTable(table) {
+		return nil, fmt.Errorf("invalid mysql table name: %s", table)
+	}
+
+	store := &MySQLStore{
+		DB:       options.DB,
+		Name:     name,
+		Table:    table,
+		TTL:      options.TTL,
+		Timeout:  options.Timeout,
+		Path:     options.Path,
+		Secure:   options.Secure,
+		HTTPOnly: options.HTTPOnly,
+		SameSite: options.SameSite,
+		now:      time.Now,
+	}
+	if !options.DisableDefaults {
+		if store.Path == "" {
+			store.Path = "/"
+		}
+		if !store.HTTPOnly {
+			store.HTTPOnly = true
+		}
+		if store.SameSite == 0 {
+			store.SameSite = http.SameSiteLaxMode
+		}
+	}
+	return store, nil
+}
+
+// EnsureTable creates the session table if it does not exist.
+func (s *MySQLStore) EnsureTable(ctx context.Context) error {
+	createTable := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id VARCHAR(128) NOT NULL PRIMARY KEY,
+		data BLOB NOT NULL,
+		expires_at DATETIME(6) NULL,
+		INDEX expires_idx (expires_at)
+	)`, s.Table)
+	_, err := s.DB.ExecContext(ctx, createTable)
+	return err
+}
+
+// Cleanup removes expired sessions.
+func (s *MySQLStore) Cleanup(ctx context.Context) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at < ?", s.Table)
+	_, err := s.DB.ExecContext(ctx, query, s.now().UTC())
+	return err
+}
+
+// StartSweeper runs Cleanup every interval until ctx is cancelled or
+// StopSweeper is called. Calling it again replaces the running sweeper.
+func (s *MySQLStore) StartSweeper(ctx context.Context, interval time.Duration) {
+	s.sweeper.start(ctx, interval, withTimeout(s.Timeout, s.Cleanup))
+}
+
+// StopSweeper stops the sweeper and waits for it to exit.
+func (s *MySQLStore) StopSweeper() {
+	s.sweeper.stop()
+}
+
+// Get loads a session from the request.
+func (s *MySQLStore) Get(r *http.Request) (*Session, error) {
+	values := map[string]string{}
+	cookie, err := r.Cookie(s.Name)
+	if err != nil || cookie.Value == "" {
+		id, err := newSessionID()
+		if err != nil {
+			return nil, err
+		}
+		return &Session{ID: id, Values: values, store: s, isNew: true}, nil
+	}
+
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	var payload []byte
+	var expires sql.NullTime
+	query := fmt.Sprintf("SELECT data, expires_at FROM %s WHERE id = ?", s.Table)
+	err = s.DB.QueryRowContext(ctx, query, cookie.Value).Scan(&payload, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		id, err := newSessionID()
+		if err != nil {
+			return nil, err
+		}
+		return &Session{ID: id, Values: values, store: s, isNew: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if expires.Valid && s.now().After(expires.Time) {
+		_ = s.deleteByID(ctx, cookie.Value)
+		id, err := newSessionID()
+		if err != nil {
+			return nil, err
+		}
+		return &Session{ID: id, Values: values, store: s, isNew: true}, nil
+	}
+
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, err
+	}
+
+	return &Session{ID: cookie.Value, Values: values, store: s}, nil
+}
+
+// Save persists a session.
+func (s *MySQLStore) Save(w http.ResponseWriter, session *Session) error {
+	if session == nil {
+		return errors.New("session missing")
+	}
+	id := session.ID
+	if id == "" {
+		newID, err := newSessionID()
+		if err != nil {
+			return err
+		}
+		id = newID
+		session.ID = id
+	}
+
+	payload, err := json.Marshal(session.Values)
+	if err != nil {
+		return err
+	}
+
+	var expires sql.NullTime
+	if s.TTL > 0 {
+		expires = sql.NullTime{Time: s.now().Add(s.TTL).UTC(), Valid: true}
+	}
+
+	ctx, cancel := s.ctx()
+	defer cancel()
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, data, expires_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE data = VALUES(data), expires_at = VALUES(expires_at)`, s.Table)
+	if _, err := s.DB.ExecContext(ctx, query, id, payload, expires); err != nil {
+		return err
+	}
+
+	s.setCookie(w, id)
+	session.isNew = false
+	return nil
+}
+
+// Clear removes a session.
+func (s *MySQLStore) Clear(w http.ResponseWriter, session *Session) {
+	if session != nil && session.ID != "" {
+		ctx, cancel := s.ctx()
+		defer cancel()
+		_ = s.deleteByID(ctx, session.ID)
+	}
+
+	s.clearCookie(w)
+	if session != nil {
+		session.Values = map[string]string{}
+		session.isNew = true
+		session.ID = ""
+	}
+}
+
+func (s *MySQLStore) deleteID(id string) error {
+	ctx, cancel := s.ctx()
+	defer cancel()
+	return s.deleteByID(ctx, id)
+}
+
+func (s *MySQLStore) deleteByID(ctx context.Context, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", s.Table)
+	_, err := s.DB.ExecContext(ctx, query, id)
+	return err
+}
+
+func (s *MySQLStore) setCookie(w http.ResponseWriter, id string) {
+	cookie := &http.Cookie{
+		Name:     s.Name,
+		Value:    id,
+		Path:     s.Path,
+		Secure:   s.Secure,
+		HttpOnly: s.HTTPOnly,
+		SameSite: s.SameSite,
+	}
+	if s.TTL > 0 {
+		cookie.MaxAge = int(s.TTL.Seconds())
+		cookie.Expires = s.now().Add(s.TTL)
+	}
+
+	http.SetCookie(w, cookie)
+}
+
+func (s *MySQLStore) clearCookie(w http.ResponseWriter) {
+	cookie := &http.Cookie{
+		Name:     s.Name,
+		Value:    "",
+		Path:     s.Path,
+		MaxAge:   -1,
+		Expires:  time.Unix(0, 0),
+		Secure:   s.Secure,
+		HttpOnly: s.HTTPOnly,
+		SameSite: s.SameSite,
+	}
+	http.SetCookie(w, cookie)
+}
+
+func (s *MySQLStore) ctx() (context.Context, context.CancelFunc) {
+	if s.Timeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), s.Timeout)
+}
+
+func validMySQLTable(name string) bool {
+	return tableNamePattern.MatchString(name)
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewMySQLStoreValidatesTable(t *testing.T) {
+	if _, err := NewMySQLStore(MySQLOptions{}); err == nil {
+		t.Fatalf("expected error without db")
+	}
+	if _, err := NewMySQLStore(MySQLOptions{DB: &sql.DB{}, Table: "sessions; DROP TABLE users"}); err == nil {
+		t.Fatalf("expected error for unsafe table name")
+	}
+	store, err := NewMySQLStore(MySQLOptions{DB: &sql.DB{}, Table: "app.sessions"})
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	if store.Path != "/" || !store.HTTPOnly || store.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("expected default cookie settings, got %+v", store)
+	}
+}
+
+func TestMySQLStoreLifecycle(t *testing.T) {
+	db := sql.OpenDB(&fakeMySQL{rows: map[string]fakeMySQLRow{}})
+	defer db.Close()
+
+	store, err := NewMySQLStore(MySQLOptions{DB: db, TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	sess, _ := store.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	sess.Set("user_id", "123")
+	rec := httptest.NewRecorder()
+	if err := sess.Save(rec); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	cookie := rec.Result().Cookies()[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	loaded, err := store.Get(req)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if loaded.IsNew() || loaded.Get("user_id") != "123" {
+		t.Fatalf("expected stored session, got %v", loaded.Values)
+	}
+
+	loaded.Set("user_id", "456")
+	if err := loaded.Save(httptest.NewRecorder()); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	updated, _ := store.Get(req)
+	if updated.Get("user_id") != "456" {
+		t.Fatalf("expected upserted value, got %q", updated.Get("user_id"))
+	}
+
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	expired, err := store.Get(req)
+	if err != nil {
+		t.Fatalf("get expired: %v", err)
+	}
+	if !expired.IsNew() {
+		t.Fatalf("expected expired session to be replaced")
+	}
+}
+
+type fakeMySQLRow struct {
+	data    []byte
+	expires any
+}
+
+// fakeMySQL is a database/sql driver understanding the statements MySQLStore issues.
+type fakeMySQL struct {
+	mu   sync.Mutex
+	rows map[string]fakeMySQLRow
+}
+
+func (d *fakeMySQL) Connect(context.Context) (driver.Conn, error) { return &fakeMySQLConn{db: d}, nil }
+func (d *fakeMySQL) Driver() driver.Driver                        { return nil }
+
+type fakeMySQLConn struct {
+	db *fakeMySQL
+}
+
+func (c *fakeMySQLConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.Contains(query, "$") {
+		return nil, errors.New("postgres placeholder in mysql query: " + query)
+	}
+	return &fakeMySQLStmt{db: c.db, query: query}, nil
+}
+func (c *fakeMySQLConn) Close() error              { return nil }
+func (c *fakeMySQLConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeMySQLStmt struct {
+	db    *fakeMySQL
+	query string
+}
+
+func (s *fakeMySQLStmt) Close() error  { return nil }
+func (s *fakeMySQLStmt) NumInput() int { return strings.Count(s.query, "?") }
+
+func (s *fakeMySQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "INSERT") && strings.Contains(s.query, "ON DUPLICATE KEY UPDATE"):
+		s.db.rows[args[0].(string)] = fakeMySQLRow{data: args[1].([]byte), expires: args[2]}
+	case strings.HasPrefix(s.query, "DELETE") && strings.Contains(s.query, "WHERE id = ?"):
+		delete(s.db.rows, args[0].(string))
+	default:
+		return nil, errors.New("unexpected exec: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeMySQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.HasPrefix(s.query, "SELECT data, expires_at") {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	row, ok := s.db.rows[args[0].(string)]
+	if !ok {
+		return &fakeMySQLRows{}, nil
+	}
+	return &fakeMySQLRows{values: [][]driver.Value{{row.data, row.expires}}}, nil
+}
+
+type fakeMySQLRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeMySQLRows) Columns() []string { return []string{"data", "expires_at"} }
+func (r *fakeMySQLRows) Close() error      { return nil }
+
+func (r *fakeMySQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -40,6 +40,7 @@ type PostgresStore struct {
 	HTTPOnly bool
 	SameSite http.SameSite
 	now      func() time.Time
+	sweeper  sweeper
 }
 
 // NewPostgresStore builds a Postgres-backed store.
@@ -107,6 +108,17 @@ func (s *PostgresStore) Cleanup(ctx context.Context) error {
 	return err
 }
 
+// StartSweeper runs Cleanup every interval until ctx is cancelled or
+// StopSweeper is called. Calling it again replaces the running sweeper.
+func (s *PostgresStore) StartSweeper(ctx context.Context, interval time.Duration) {
+	s.sweeper.start(ctx, interval, withTimeout(s.Timeout, s.Cleanup))
+}
+
+// StopSweeper stops the sweeper and waits for it to exit.
+func (s *PostgresStore) StopSweeper() {
+	s.sweeper.stop()
+}
+
 // Get loads a session from the request.
 func (s *PostgresStore) Get(r *http.Request) (*Session, error) {
 	values := map[string]string{}
@@ -209,6 +221,12 @@ func (s *PostgresStore) Clear(w http.ResponseWriter, session *Session) {
 	}
 }
 
+func (s *PostgresStore) deleteID(id string) error {
+	ctx, cancel := s.ctx()
+	defer cancel()
+	return s.deleteByID(ctx, id)
+}
+
 func (s *PostgresStore) deleteByID(ctx context.Context, id string) error {
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", s.Table)
 	_, err := s.DB.ExecContext(ctx, query, id)
@@ -10,7 +10,8 @@ import (
 	"github.com/devmarvs/bebo/redis"
 )
 
-// RedisOptions configures a Redis store.
+// RedisOptions configures a Redis store. MaxIdle, MaxActive, and IdleTimeout
+// size the connection pool; see redis.Options.
 type RedisOptions struct {
 	DisableDefaults bool
 	Name            string
@@ -24,6 +25,9 @@ type RedisOptions struct {
 	DialTimeout     time.Duration
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
+	MaxIdle         int
+	MaxActive       int
+	IdleTimeout     time.Duration
 	Path            string
 	Secure          bool
 	HTTPOnly        bool
@@ -31,6 +35,8 @@ type RedisOptions struct {
 }
 
 // RedisStore stores sessions in Redis using a session ID cookie.
+// Entries are written with the TTL as a key expiry, so Redis removes expired
+// sessions itself and no sweeper is needed.
 type RedisStore struct {
 	options RedisOptions
 	client  *redis.Client
@@ -81,11 +87,19 @@ func NewRedisStore(options RedisOptions) *RedisStore {
 		DialTimeout:  cfg.DialTimeout,
 		ReadTimeout:  cfg.ReadTimeout,
 		WriteTimeout: cfg.WriteTimeout,
+		MaxIdle:      cfg.MaxIdle,
+		MaxActive:    cfg.MaxActive,
+		IdleTimeout:  cfg.IdleTimeout,
 	})
 
 	return &RedisStore{options: cfg, client: client}
 }
 
+// Close closes the store's pooled Redis connections.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
 // Get loads a session from the request.
 func (s *RedisStore) Get(r *http.Request) (*Session, error) {
 	values := map[string]string{}
@@ -225,3 +239,7 @@ func (s *RedisStore) del(id string) error {
 	_, err := s.client.Do("DEL", s.key(id))
 	return err
 }
+
+func (s *RedisStore) deleteID(id string) error {
+	return s.del(id)
+}
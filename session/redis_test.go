@@ -12,6 +12,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/devmarvs/bebo/internal/redistest"
 )
 
 func TestRedisStoreRoundTrip(t *testing.T) {
@@ -55,6 +57,91 @@ func TestRedisStoreRoundTrip(t *testing.T) {
 	}
 }
 
+func TestRedisStoreRegenerateDeletesOldID(t *testing.T) {
+	addr, shutdown := startRedisServer(t)
+	defer shutdown()
+
+	store := NewRedisStore(RedisOptions{
+		Address:      addr,
+		DialTimeout:  500 * time.Millisecond,
+		ReadTimeout:  500 * time.Millisecond,
+		WriteTimeout: 500 * time.Millisecond,
+		TTL:          time.Minute,
+	})
+
+	sess, _ := store.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	rec := httptest.NewRecorder()
+	if err := store.Save(rec, sess); err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+	fixed := rec.Result().Cookies()[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(fixed)
+	loaded, err := store.Get(req)
+	if err != nil {
+		t.Fatalf("get loaded: %v", err)
+	}
+	loaded.Set("user_id", "123")
+	rec = httptest.NewRecorder()
+	if err := loaded.Regenerate(rec); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	fresh := rec.Result().Cookies()[0]
+	if fresh.Value == fixed.Value {
+		t.Fatalf("expected a new session ID")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(fixed)
+	if old, _ := store.Get(req); !old.IsNew() {
+		t.Fatalf("expected old session ID to be deleted")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(fresh)
+	moved, _ := store.Get(req)
+	if moved.Get("user_id") != "123" {
+		t.Fatalf("expected user_id on the new ID, got %q", moved.Get("user_id"))
+	}
+}
+
+func TestRedisStoreTTLExpiry(t *testing.T) {
+	clock := redistest.NewClock()
+	addr, shutdown := redistest.StartWithOptions(t, redistest.Options{Now: clock.Now})
+	defer shutdown()
+
+	store := NewRedisStore(RedisOptions{Address: addr, TTL: time.Minute})
+	defer store.Close()
+
+	sess, _ := store.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	sess.Set("user_id", "123")
+	rec := httptest.NewRecorder()
+	if err := store.Save(rec, sess); err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+	cookie := rec.Result().Cookies()[0]
+	load := func() *Session {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookie)
+		loaded, err := store.Get(req)
+		if err != nil {
+			t.Fatalf("get loaded: %v", err)
+		}
+		return loaded
+	}
+
+	clock.Advance(59 * time.Second)
+	if loaded := load(); loaded.IsNew() || loaded.Get("user_id") != "123" {
+		t.Fatalf("expected session before ttl, got new=%v user_id=%q", loaded.IsNew(), loaded.Get("user_id"))
+	}
+
+	clock.Advance(2 * time.Second)
+	if loaded := load(); !loaded.IsNew() || loaded.Get("user_id") != "" {
+		t.Fatalf("expected session to expire after ttl, got user_id=%q", loaded.Get("user_id"))
+	}
+}
+
 func startRedisServer(t *testing.T) (string, func()) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -40,6 +40,7 @@ type Session struct {
 	Values map[string]string
 	store  Store
 	isNew  bool
+	stale  bool
 }
 
 // IsNew reports whether the session was newly created for this request.
@@ -47,6 +48,20 @@ func (s *Session) IsNew() bool {
 	return s.isNew
 }
 
+// NeedsResave reports whether the session was validated with an old key and
+// should be saved again to re-sign it with the primary key.
+func (s *Session) NeedsResave() bool {
+	return s.stale
+}
+
+// ResaveIfRotated saves the session when it was signed with an old key.
+func (s *Session) ResaveIfRotated(w http.ResponseWriter) error {
+	if !s.stale {
+		return nil
+	}
+	return s.Save(w)
+}
+
 // NewCookieStore creates a cookie store with key rotation support.
 func NewCookieStore(name string, key []byte, oldKeys ...[]byte) *CookieStore {
 	keys := make([][]byte, 0, 1+len(oldKeys))
@@ -69,12 +84,12 @@ func (s *CookieStore) Get(r *http.Request) (*Session, error) {
 		return &Session{Values: values, store: s, isNew: true}, nil
 	}
 
-	decoded, err := decode(cookie.Value, s.Keys)
+	decoded, keyIndex, err := decode(cookie.Value, s.Keys)
 	if err != nil {
 		return &Session{Values: values, store: s, isNew: true}, ErrInvalidCookie
 	}
 
-	return &Session{Values: decoded, store: s}, nil
+	return &Session{Values: decoded, store: s, stale: keyIndex > 0}, nil
 }
 
 // Save writes the session cookie.
@@ -103,6 +118,7 @@ func (s *CookieStore) Save(w http.ResponseWriter, session *Session) error {
 
 	http.SetCookie(w, cookie)
 	session.isNew = false
+	session.stale = false
 	return nil
 }
 
@@ -148,6 +164,39 @@ func (s *Session) Save(w http.ResponseWriter) error {
 	return s.store.Save(w, s)
 }
 
+// idStore is implemented by stores that keep server-side state keyed by session ID.
+type idStore interface {
+	deleteID(id string) error
+}
+
+// Regenerate moves the session to a fresh ID, keeping its values, and deletes
+// the old entry from the store. Call it after login to prevent session
+// fixation. Cookie sessions have no server-side ID and are just re-signed.
+func (s *Session) Regenerate(w http.ResponseWriter) error {
+	if s.store == nil {
+		return errors.New("session store missing")
+	}
+	ids, ok := s.store.(idStore)
+	if !ok {
+		return s.store.Save(w, s)
+	}
+
+	oldID := s.ID
+	newID, err := newSessionID()
+	if err != nil {
+		return err
+	}
+	s.ID = newID
+	if err := s.store.Save(w, s); err != nil {
+		s.ID = oldID
+		return err
+	}
+	if oldID == "" {
+		return nil
+	}
+	return ids.deleteID(oldID)
+}
+
 // Clear clears the session from the configured store.
 func (s *Session) Clear(w http.ResponseWriter) {
 	if s.store == nil {
@@ -172,40 +221,32 @@ func encode(values map[string]string, keys [][]byte) (string, error) {
 	return encodedPayload + "." + encodedSig, nil
 }
 
-func decode(value string, keys [][]byte) (map[string]string, error) {
+// decode verifies the cookie and returns the index of the key that signed it.
+func decode(value string, keys [][]byte) (map[string]string, int, error) {
 	parts := strings.Split(value, ".")
 	if len(parts) != 2 {
-		return nil, ErrInvalidCookie
+		return nil, 0, ErrInvalidCookie
 	}
 
 	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
 	if err != nil {
-		return nil, ErrInvalidCookie
+		return nil, 0, ErrInvalidCookie
 	}
 	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return nil, ErrInvalidCookie
+		return nil, 0, ErrInvalidCookie
 	}
 
-	valid := false
-	for _, key := range keys {
-		if len(key) == 0 {
-			continue
-		}
-		if hmac.Equal(signature, sign(payload, key)) {
-			valid = true
-			break
-		}
-	}
-	if !valid {
-		return nil, ErrInvalidCookie
+	keyIndex := verify(payload, signature, keys)
+	if keyIndex < 0 {
+		return nil, 0, ErrInvalidCookie
 	}
 
 	values := map[string]string{}
 	if err := json.Unmarshal(payload, &values); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return values, nil
+	return values, keyIndex, nil
 }
 
 func sign(payload []byte, key []byte) []byte {
@@ -213,3 +254,16 @@ func sign(payload []byte, key []byte) []byte {
 	_, _ = h.Write(payload)
 	return h.Sum(nil)
 }
+
+// verify returns the index of the key that produced signature, or -1.
+func verify(payload, signature []byte, keys [][]byte) int {
+	for i, key := range keys {
+		if len(key) == 0 {
+			continue
+		}
+		if hmac.Equal(signature, sign(payload, key)) {
+			return i
+		}
+	}
+	return -1
+}
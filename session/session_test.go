@@ -0,0 +1,103 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCookieStoreKeyRotationResaves(t *testing.T) {
+	oldKey := []byte("old-key-old-key-old-key-old-key!")
+	newKey := []byte("new-key-new-key-new-key-new-key!")
+
+	oldStore := NewCookieStore("bebo_session", oldKey)
+	sess, _ := oldStore.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	sess.Set("user", "123")
+	rec := httptest.NewRecorder()
+	if err := sess.Save(rec); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	oldCookie := rec.Result().Cookies()[0]
+
+	rotated := NewCookieStore("bebo_session", newKey, oldKey)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(oldCookie)
+	loaded, err := rotated.Get(req)
+	if err != nil {
+		t.Fatalf("get with old key: %v", err)
+	}
+	if loaded.Get("user") != "123" {
+		t.Fatalf("expected value from old cookie")
+	}
+	if !loaded.NeedsResave() {
+		t.Fatalf("expected session signed with old key to need resave")
+	}
+
+	rec = httptest.NewRecorder()
+	if err := loaded.ResaveIfRotated(rec); err != nil {
+		t.Fatalf("resave: %v", err)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatalf("expected re-signed cookie")
+	}
+	if loaded.NeedsResave() {
+		t.Fatalf("expected resave flag cleared after save")
+	}
+
+	newOnly := NewCookieStore("bebo_session", newKey)
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	resigned, err := newOnly.Get(req)
+	if err != nil {
+		t.Fatalf("expected cookie signed with new key: %v", err)
+	}
+	if resigned.NeedsResave() || resigned.Get("user") != "123" {
+		t.Fatalf("unexpected re-signed session state")
+	}
+}
+
+func TestCookieStoreResaveIfRotatedSkipsPrimary(t *testing.T) {
+	store := NewCookieStore("bebo_session", []byte("primary-key-primary-key-primary!"))
+	sess, _ := store.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	sess.Set("user", "123")
+	rec := httptest.NewRecorder()
+	if err := sess.Save(rec); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	loaded, err := store.Get(req)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	rec = httptest.NewRecorder()
+	if err := loaded.ResaveIfRotated(rec); err != nil {
+		t.Fatalf("resave: %v", err)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Fatalf("expected no cookie for session signed with primary key")
+	}
+}
+
+func TestCookieStoreRegenerateResigns(t *testing.T) {
+	store := NewCookieStore("bebo_session", []byte("new-key"), []byte("old-key"))
+	sess, _ := store.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	sess.Set("user", "123")
+
+	rec := httptest.NewRecorder()
+	if err := sess.Regenerate(rec); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	loaded, err := store.Get(req)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if loaded.Get("user") != "123" || loaded.NeedsResave() {
+		t.Fatalf("expected values signed with the primary key")
+	}
+}
@@ -0,0 +1,73 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweeper runs a cleanup function on a ticker in a single goroutine.
+type sweeper struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start replaces any running sweep loop with one calling cleanup every
+// interval until ctx is cancelled or stop is called. Runs never overlap.
+func (s *sweeper) start(ctx context.Context, interval time.Duration, cleanup func(context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+	if interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.cancel = cancel
+	s.done = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = cleanup(ctx)
+			}
+		}
+	}()
+}
+
+// stop cancels the sweep loop and waits for it to exit.
+func (s *sweeper) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+func (s *sweeper) stopLocked() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.cancel = nil
+	s.done = nil
+}
+
+// withTimeout bounds each cleanup run by timeout, when positive.
+func withTimeout(timeout time.Duration, cleanup func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if timeout <= 0 {
+			return cleanup(ctx)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return cleanup(ctx)
+	}
+}
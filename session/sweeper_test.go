@@ -0,0 +1,70 @@
+package session
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreSweeperRemovesExpired(t *testing.T) {
+	store := NewMemoryStore("bebo_session", 20*time.Millisecond)
+	sess := &Session{ID: "expiring", Values: map[string]string{"user": "1"}, store: store}
+	if err := store.Save(httptest.NewRecorder(), sess); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store.StartSweeper(ctx, 5*time.Millisecond)
+	defer store.StopSweeper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.RLock()
+		remaining := len(store.sessions)
+		store.mu.RUnlock()
+		if remaining == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected sweeper to remove expired session")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSweeperStopsAndRestarts(t *testing.T) {
+	var runs atomic.Int32
+	var s sweeper
+	cleanup := func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.start(ctx, time.Millisecond, cleanup)
+	s.start(ctx, time.Millisecond, cleanup)
+	done := s.done
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected sweeper to exit on context cancellation")
+	}
+	s.stop()
+	s.stop()
+
+	s.start(context.Background(), time.Millisecond, cleanup)
+	time.Sleep(10 * time.Millisecond)
+	s.stop()
+	after := runs.Load()
+	if after == 0 {
+		t.Fatalf("expected cleanup to run")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if runs.Load() != after {
+		t.Fatalf("expected no cleanup runs after stop")
+	}
+}
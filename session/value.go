@@ -0,0 +1,105 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// SignValue signs a single cookie value with keys[0], without the session
+// map encoding. The signature covers the cookie name, so a value signed for
+// one cookie is rejected under another. The value stays readable by the
+// client; use EncryptValue for confidential data.
+func SignValue(name, value string, keys [][]byte) (string, error) {
+	if len(keys) == 0 || len(keys[0]) == 0 {
+		return "", errors.New("session key required")
+	}
+	sig := sign(valuePayload(name, value), keys[0])
+	return base64.RawURLEncoding.EncodeToString([]byte(value)) + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyValue checks a value produced by SignValue against keys and returns
+// it with the index of the key that signed it. An index above zero means an
+// old key matched and the cookie should be re-signed.
+func VerifyValue(name, signed string, keys [][]byte) (string, int, error) {
+	encodedValue, encodedSig, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", 0, ErrInvalidCookie
+	}
+	value, err := base64.RawURLEncoding.DecodeString(encodedValue)
+	if err != nil {
+		return "", 0, ErrInvalidCookie
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return "", 0, ErrInvalidCookie
+	}
+
+	keyIndex := verify(valuePayload(name, string(value)), signature, keys)
+	if keyIndex < 0 {
+		return "", 0, ErrInvalidCookie
+	}
+	return string(value), keyIndex, nil
+}
+
+// EncryptValue seals value with AES-GCM under keys[0], which must be 16, 24,
+// or 32 bytes long. The cookie name is bound as additional data.
+func EncryptValue(name, value string, keys [][]byte) (string, error) {
+	if len(keys) == 0 || len(keys[0]) == 0 {
+		return "", errors.New("session key required")
+	}
+	aead, err := newGCM(keys[0])
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue opens a value produced by EncryptValue, trying each key in
+// order, and returns the index of the key that opened it.
+func DecryptValue(name, encrypted string, keys [][]byte) (string, int, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", 0, ErrInvalidCookie
+	}
+
+	for i, key := range keys {
+		if len(key) == 0 {
+			continue
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return "", 0, err
+		}
+		if len(sealed) < aead.NonceSize() {
+			return "", 0, ErrInvalidCookie
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		plain, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+		if err == nil {
+			return string(plain), i, nil
+		}
+	}
+	return "", 0, ErrInvalidCookie
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New("encryption key must be 16, 24, or 32 bytes")
+	}
+	return cipher.NewGCM(block)
+}
+
+func valuePayload(name, value string) []byte {
+	return []byte(name + "\x00" + value)
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSignValueRoundTrip(t *testing.T) {
+	keys := [][]byte{[]byte("primary-key")}
+	signed, err := SignValue("remember", "user:123", keys)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	value, keyIndex, err := VerifyValue("remember", signed, keys)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if value != "user:123" || keyIndex != 0 {
+		t.Fatalf("expected user:123 from key 0, got %q from key %d", value, keyIndex)
+	}
+}
+
+func TestVerifyValueRejectsTamperingAndOtherNames(t *testing.T) {
+	keys := [][]byte{[]byte("primary-key")}
+	signed, _ := SignValue("remember", "user:123", keys)
+
+	if _, _, err := VerifyValue("other", signed, keys); !errors.Is(err, ErrInvalidCookie) {
+		t.Fatalf("expected value signed for another cookie to be rejected, got %v", err)
+	}
+
+	forged, _ := SignValue("remember", "user:999", [][]byte{[]byte("attacker")})
+	tampered := strings.SplitN(forged, ".", 2)[0] + "." + strings.SplitN(signed, ".", 2)[1]
+	if _, _, err := VerifyValue("remember", tampered, keys); !errors.Is(err, ErrInvalidCookie) {
+		t.Fatalf("expected tampered value to be rejected, got %v", err)
+	}
+	if _, _, err := VerifyValue("remember", "garbage", keys); !errors.Is(err, ErrInvalidCookie) {
+		t.Fatalf("expected malformed value to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyValueKeyRotation(t *testing.T) {
+	oldKey := []byte("old-key")
+	signed, _ := SignValue("remember", "user:123", [][]byte{oldKey})
+
+	value, keyIndex, err := VerifyValue("remember", signed, [][]byte{[]byte("new-key"), oldKey})
+	if err != nil {
+		t.Fatalf("verify with old key: %v", err)
+	}
+	if value != "user:123" || keyIndex != 1 {
+		t.Fatalf("expected value from old key at index 1, got %q from key %d", value, keyIndex)
+	}
+}
+
+func TestEncryptValueRoundTripAndRotation(t *testing.T) {
+	oldKey := []byte("0123456789abcdef0123456789abcdef")
+	newKey := []byte("fedcba9876543210fedcba9876543210")
+
+	encrypted, err := EncryptValue("token", "secret-value", [][]byte{oldKey})
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if strings.Contains(encrypted, "secret") {
+		t.Fatalf("expected value to be confidential, got %q", encrypted)
+	}
+
+	value, keyIndex, err := DecryptValue("token", encrypted, [][]byte{newKey, oldKey})
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if value != "secret-value" || keyIndex != 1 {
+		t.Fatalf("expected secret-value from key 1, got %q from key %d", value, keyIndex)
+	}
+
+	if _, _, err := DecryptValue("other", encrypted, [][]byte{oldKey}); !errors.Is(err, ErrInvalidCookie) {
+		t.Fatalf("expected value encrypted for another cookie to be rejected, got %v", err)
+	}
+	if _, _, err := DecryptValue("token", encrypted, [][]byte{newKey}); !errors.Is(err, ErrInvalidCookie) {
+		t.Fatalf("expected unknown key to be rejected, got %v", err)
+	}
+}
+
+func TestEncryptValueRequiresValidKey(t *testing.T) {
+	if _, err := EncryptValue("token", "value", [][]byte{[]byte("short")}); err == nil {
+		t.Fatalf("expected error for invalid AES key length")
+	}
+	if _, err := EncryptValue("token", "value", nil); err == nil {
+		t.Fatalf("expected error for missing key")
+	}
+}
@@ -0,0 +1,104 @@
+package bebo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEEvent is a server-sent event. ID and Retry control client reconnection:
+// browsers resend the last ID in Last-Event-ID and wait Retry before reconnecting.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// SSEWriter streams server-sent events to the client.
+type SSEWriter struct {
+	ctx        *Context
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	mu         sync.Mutex
+}
+
+// SSE prepares the response for server-sent events and flushes the headers.
+// The returned writer stops sending once the request context is canceled.
+func (c *Context) SSE() (*SSEWriter, error) {
+	headers := c.ResponseWriter.Header()
+	headers.Set("Content-Type", "text/event-stream")
+	headers.Set("Cache-Control", "no-cache")
+	headers.Set("X-Accel-Buffering", "no")
+	headers.Del("Content-Length")
+
+	controller := http.NewResponseController(c.ResponseWriter)
+	// Long-lived streams must not be cut off by the server write timeout.
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	c.ResponseWriter.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		if errors.Is(err, http.ErrNotSupported) {
+			return nil, errors.New("response writer does not support flushing")
+		}
+		return nil, err
+	}
+	return &SSEWriter{ctx: c, w: c.ResponseWriter, controller: controller}, nil
+}
+
+// Done is closed when the client disconnects or the request is canceled.
+func (s *SSEWriter) Done() <-chan struct{} {
+	return s.ctx.Request.Context().Done()
+}
+
+// Send writes an event with the given name and data, then flushes.
+func (s *SSEWriter) Send(event, data string) error {
+	return s.SendEvent(SSEEvent{Event: event, Data: data})
+}
+
+// SendEvent writes an event, including id and retry fields, then flushes.
+// It returns the request context error once the client has gone away.
+func (s *SSEWriter) SendEvent(event SSEEvent) error {
+	if err := s.ctx.Request.Context().Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := WriteSSEEvent(s.w, event); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
+
+// WriteSSEEvent encodes event in the text/event-stream format and writes it
+// to w in a single call. Multi-line data becomes several data fields.
+func WriteSSEEvent(w io.Writer, event SSEEvent) error {
+	var b strings.Builder
+	if event.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", event.Retry.Milliseconds())
+	}
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", sseField(event.ID))
+	}
+	if event.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", sseField(event.Event))
+	}
+	data := strings.ReplaceAll(event.Data, "\r\n", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sseField strips line breaks so a value cannot start a new field.
+func sseField(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
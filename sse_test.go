@@ -0,0 +1,127 @@
+package bebo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContextSSESendsEvents(t *testing.T) {
+	app := New()
+	app.GET("/events", func(ctx *Context) error {
+		stream, err := ctx.SSE()
+		if err != nil {
+			return err
+		}
+		if err := stream.SendEvent(SSEEvent{ID: "1", Event: "progress", Data: "line one\nline two", Retry: 3 * time.Second}); err != nil {
+			return err
+		}
+		return stream.Send("done", "ok")
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("expected event stream content type, got %q", got)
+	}
+	if rec.Header().Get("X-Accel-Buffering") != "no" {
+		t.Fatalf("expected proxy buffering disabled")
+	}
+	if !rec.Flushed {
+		t.Fatalf("expected response flushed")
+	}
+	want := "retry: 3000\nid: 1\nevent: progress\ndata: line one\ndata: line two\n\nevent: done\ndata: ok\n\n"
+	if rec.Body.String() != want {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+}
+
+func TestContextSSEStripsLineBreaksFromFields(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := NewContext(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil, New())
+	stream, err := ctx.SSE()
+	if err != nil {
+		t.Fatalf("sse: %v", err)
+	}
+	if err := stream.SendEvent(SSEEvent{ID: "1\nevent: injected", Data: "x"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if strings.Contains(rec.Body.String(), "\nevent: injected") {
+		t.Fatalf("expected id line breaks stripped, got %q", rec.Body.String())
+	}
+}
+
+func TestContextSSEStopsOnCancel(t *testing.T) {
+	reqCtx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(reqCtx)
+	ctx := NewContext(httptest.NewRecorder(), req, nil, New())
+
+	stream, err := ctx.SSE()
+	if err != nil {
+		t.Fatalf("sse: %v", err)
+	}
+	cancel()
+
+	select {
+	case <-stream.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected done channel closed")
+	}
+	if err := stream.Send("tick", "1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+}
+
+func TestContextSSEStreamsThroughMiddleware(t *testing.T) {
+	release := make(chan struct{})
+	app := New()
+	app.Use(func(next Handler) Handler {
+		return func(ctx *Context) error {
+			ctx.ResponseWriter = &unwrapWriter{ResponseWriter: ctx.ResponseWriter}
+			return next(ctx)
+		}
+	})
+	app.GET("/events", func(ctx *Context) error {
+		stream, err := ctx.SSE()
+		if err != nil {
+			return err
+		}
+		if err := stream.Send("tick", "1"); err != nil {
+			return err
+		}
+		<-release
+		return nil
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+	defer close(release)
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read event: %v", err)
+	}
+	if line != "event: tick\n" {
+		t.Fatalf("unexpected first line %q", line)
+	}
+}
+
+type unwrapWriter struct {
+	http.ResponseWriter
+}
+
+func (w *unwrapWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
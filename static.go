@@ -19,6 +19,17 @@ type staticConfig struct {
 	etag         bool
 	indexFile    string
 	paramName    string
+	fingerprints AssetFingerprints
+}
+
+// ImmutableCacheControl is sent for fingerprinted assets, whose URL changes
+// whenever their content does.
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+// AssetFingerprints maps fingerprinted asset names back to their source
+// files; assets.Manifest implements it.
+type AssetFingerprints interface {
+	Original(fingerprinted string) (string, bool)
 }
 
 // StaticOption configures static file handling.
@@ -45,6 +56,26 @@ func StaticIndex(name string) StaticOption {
 	}
 }
 
+// StaticFingerprinted serves fingerprinted names known to fingerprints from
+// their source file with ImmutableCacheControl. Other paths are served as usual.
+func StaticFingerprinted(fingerprints AssetFingerprints) StaticOption {
+	return func(cfg *staticConfig) {
+		cfg.fingerprints = fingerprints
+	}
+}
+
+// resolveFingerprint swaps a fingerprinted rel for its source file.
+func resolveFingerprint(rel string, cfg staticConfig) (string, staticConfig) {
+	if cfg.fingerprints == nil || rel == "" {
+		return rel, cfg
+	}
+	if original, ok := cfg.fingerprints.Original(rel); ok {
+		cfg.cacheControl = ImmutableCacheControl
+		return original, cfg
+	}
+	return rel, cfg
+}
+
 // File registers a static route for a single file on disk.
 func (a *App) File(route, filePath string, options ...StaticOption) {
 	cfg := staticConfig{
@@ -83,7 +114,7 @@ func (a *App) Static(prefix, dir string, options ...StaticOption) {
 
 	pattern := buildStaticPattern(prefix, cfg.paramName)
 	handler := func(ctx *Context) error {
-		rel := ctx.Param(cfg.paramName)
+		rel, cfg := resolveFingerprint(ctx.Param(cfg.paramName), cfg)
 		return serveStatic(ctx, dir, rel, cfg)
 	}
 
@@ -105,7 +136,7 @@ func (a *App) StaticFS(prefix string, fsys fs.FS, options ...StaticOption) {
 
 	pattern := buildStaticPattern(prefix, cfg.paramName)
 	handler := func(ctx *Context) error {
-		rel := ctx.Param(cfg.paramName)
+		rel, cfg := resolveFingerprint(ctx.Param(cfg.paramName), cfg)
 		return serveStaticFS(ctx, fsys, rel, cfg)
 	}
 
@@ -234,9 +265,13 @@ func buildETag(modTime time.Time, size int64) string {
 	return fmt.Sprintf("\"%x-%x\"", modTime.UnixNano(), size)
 }
 
+// matchETag reports whether an If-None-Match header matches etag, using the
+// weak comparison RFC 9110 specifies for If-None-Match.
 func matchETag(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
 	for _, part := range strings.Split(header, ",") {
-		if strings.TrimSpace(part) == etag {
+		part = strings.TrimSpace(part)
+		if part == "*" || strings.TrimPrefix(part, "W/") == etag {
 			return true
 		}
 	}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"testing"
 	"testing/fstest"
+
+	"github.com/devmarvs/bebo/assets"
 )
 
 func TestStaticETag(t *testing.T) {
@@ -83,3 +85,32 @@ func TestFile(t *testing.T) {
 		t.Fatalf("expected body %q, got %q", "home", rec.Body.String())
 	}
 }
+
+func TestStaticFingerprinted(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.css"), []byte("body{}"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	manifest, err := assets.NewManifest(dir, "/static")
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+
+	app := New(WithTemplateFuncs(manifest.FuncMap()))
+	app.Static("/static", dir, StaticFingerprinted(manifest))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, manifest.URL("app.css"), nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "body{}" {
+		t.Fatalf("expected fingerprinted asset, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Cache-Control"); got != ImmutableCacheControl {
+		t.Fatalf("expected immutable cache control, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/app.css", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "public, max-age=86400" {
+		t.Fatalf("expected plain asset with default caching, got %d %q", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+}
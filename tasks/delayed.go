@@ -0,0 +1,217 @@
+package tasks
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+type delayedJob struct {
+	job   Job
+	at    time.Time
+	index int
+	stop  func() bool
+}
+
+// delayHeap orders delayed jobs by due time.
+type delayHeap []*delayedJob
+
+func (h delayHeap) Len() int           { return len(h) }
+func (h delayHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h delayHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *delayHeap) Push(x any) {
+	item := x.(*delayedJob)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *delayHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	item.index = -1
+	*h = old[:len(old)-1]
+	return item
+}
+
+// EnqueueAfter schedules a job to run once delay has elapsed.
+func (r *Runner) EnqueueAfter(delay time.Duration, job Job) error {
+	return r.EnqueueAt(time.Now().Add(delay), job)
+}
+
+// EnqueueAt schedules a job to run at the given time. Jobs due now are
+// enqueued immediately. A delayed job whose Context is canceled before it is
+// due is removed and reported to OnDeadLetter.
+func (r *Runner) EnqueueAt(at time.Time, job Job) error {
+	if job.Handler == nil {
+		return ErrHandlerMissing
+	}
+	if !at.After(time.Now()) {
+		return r.Enqueue(job)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrRunnerClosed
+	}
+
+	item := &delayedJob{job: job, at: at}
+	if job.Context != nil {
+		item.stop = context.AfterFunc(job.Context, func() {
+			r.cancelDelayed(item)
+		})
+	}
+	heap.Push(&r.delayed, item)
+	r.enqueued.Add(1)
+	r.schedulerOnce.Do(func() {
+		r.schedulerDone = make(chan struct{})
+		go r.runScheduler()
+	})
+	r.wakeScheduler()
+	return nil
+}
+
+// Scheduled returns the number of delayed jobs waiting to become due.
+func (r *Runner) Scheduled() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.delayed)
+}
+
+func (r *Runner) wakeScheduler() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Runner) cancelDelayed(item *delayedJob) {
+	r.mu.Lock()
+	if item.index < 0 {
+		r.mu.Unlock()
+		return
+	}
+	heap.Remove(&r.delayed, item.index)
+	r.mu.Unlock()
+
+	r.deadLetter(item.job, 0, item.job.Context.Err())
+	r.wakeScheduler()
+}
+
+func (r *Runner) runScheduler() {
+	defer close(r.schedulerDone)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		r.mu.Lock()
+		var due *delayedJob
+		wait := time.Hour
+		if len(r.delayed) > 0 {
+			next := r.delayed[0]
+			wait = time.Until(next.at)
+			if wait <= 0 {
+				due = heap.Pop(&r.delayed).(*delayedJob)
+			}
+		}
+		r.mu.Unlock()
+
+		if due != nil {
+			if due.stop != nil && !due.stop() {
+				// Canceled after it was popped; cancelDelayed skips popped jobs.
+				r.deadLetter(due.job, 0, due.job.Context.Err())
+				continue
+			}
+			due.stop = nil
+			select {
+			case r.queue <- due.job:
+			case <-r.stopScheduler:
+				r.mu.Lock()
+				heap.Push(&r.delayed, due)
+				r.mu.Unlock()
+				return
+			}
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-r.wake:
+		case <-r.stopScheduler:
+			return
+		}
+	}
+}
+
+// stopDelayed halts the scheduler, then drains pending delayed jobs into the
+// queue or discards them according to Options.DrainDelayed.
+func (r *Runner) stopDelayed(ctx context.Context) {
+	close(r.stopScheduler)
+	r.mu.Lock()
+	done := r.schedulerDone
+	r.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+
+	r.mu.Lock()
+	var pending, canceled []*delayedJob
+	for len(r.delayed) > 0 {
+		item := heap.Pop(&r.delayed).(*delayedJob)
+		if item.stop != nil && !item.stop() {
+			canceled = append(canceled, item)
+			continue
+		}
+		pending = append(pending, item)
+	}
+	r.mu.Unlock()
+
+	for _, item := range canceled {
+		r.deadLetter(item.job, 0, item.job.Context.Err())
+	}
+
+	for i, item := range pending {
+		if !r.opts.drainDelayed {
+			r.deadLetter(item.job, 0, ErrRunnerClosed)
+			continue
+		}
+		select {
+		case r.queue <- item.job:
+		case <-ctx.Done():
+			for _, rest := range pending[i:] {
+				r.deadLetter(rest.job, 0, ctx.Err())
+			}
+			return
+		}
+	}
+}
+
+func (r *Runner) deadLetter(job Job, attempts int, err error) {
+	r.deadLettered.Add(1)
+	r.notifyDeadLetter(job, attempts, err)
+}
+
+// notifyDeadLetter calls the job or runner dead-letter callback without
+// counting it, for wrappers that already passed through deadLetter.
+func (r *Runner) notifyDeadLetter(job Job, attempts int, err error) {
+	onDeadLetter := job.OnDeadLetter
+	if onDeadLetter == nil {
+		onDeadLetter = r.opts.onDeadLetter
+	}
+	if onDeadLetter != nil {
+		onDeadLetter(DeadLetter{Name: job.Name, Attempts: attempts, Err: err})
+	}
+}
@@ -0,0 +1,31 @@
+package tasks
+
+import "github.com/devmarvs/bebo/metrics"
+
+// RegisterMetrics exposes the runner stats on registry as bebo_tasks_*
+// samples, labeled runner=name when name is set.
+func (r *Runner) RegisterMetrics(registry *metrics.Registry, name string) {
+	if registry == nil {
+		return
+	}
+	var labels map[string]string
+	if name != "" {
+		labels = map[string]string{"runner": name}
+	}
+
+	registry.AddCollector(func() []metrics.Sample {
+		stats := r.Stats()
+		sample := func(metric, help, kind string, value float64) metrics.Sample {
+			return metrics.Sample{Name: metric, Help: help, Type: kind, Labels: labels, Value: value}
+		}
+		return []metrics.Sample{
+			sample("bebo_tasks_enqueued_total", "Jobs accepted by the task runner", metrics.CounterType, float64(stats.Enqueued)),
+			sample("bebo_tasks_succeeded_total", "Jobs that completed successfully", metrics.CounterType, float64(stats.Succeeded)),
+			sample("bebo_tasks_failed_total", "Failed job attempts, retries included", metrics.CounterType, float64(stats.Failed)),
+			sample("bebo_tasks_dead_lettered_total", "Jobs that failed permanently or were dropped", metrics.CounterType, float64(stats.DeadLettered)),
+			sample("bebo_tasks_queued", "Jobs waiting for a worker", metrics.GaugeType, float64(stats.Queued)),
+			sample("bebo_tasks_scheduled", "Delayed jobs not yet due", metrics.GaugeType, float64(stats.Scheduled)),
+			sample("bebo_tasks_active_workers", "Workers currently running a job", metrics.GaugeType, float64(stats.ActiveWorkers)),
+		}
+	})
+}
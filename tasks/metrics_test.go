@@ -0,0 +1,40 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo/metrics"
+)
+
+func TestRunnerRegisterMetrics(t *testing.T) {
+	runner := New(Options{QueueSize: 2})
+	_ = runner.Enqueue(Job{Name: "pending", Handler: func(ctx context.Context) error { return nil }})
+
+	registry := metrics.New()
+	runner.RegisterMetrics(registry, "emails")
+
+	rec := httptest.NewRecorder()
+	metrics.PrometheusHandler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE bebo_tasks_enqueued_total counter",
+		`bebo_tasks_enqueued_total{runner="emails"} 1`,
+		"# TYPE bebo_tasks_queued gauge",
+		`bebo_tasks_queued{runner="emails"} 1`,
+		`bebo_tasks_active_workers{runner="emails"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in:\n%s", want, body)
+		}
+	}
+
+	snap := registry.Snapshot()
+	if len(snap.Custom) != 7 {
+		t.Fatalf("expected task samples in JSON snapshot, got %d", len(snap.Custom))
+	}
+}
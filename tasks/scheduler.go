@@ -0,0 +1,183 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSchedulerStopped indicates the scheduler has been stopped.
+var ErrSchedulerStopped = errors.New("task scheduler is stopped")
+
+// SchedulerOptions configures a Scheduler.
+type SchedulerOptions struct {
+	// AllowOverlap enqueues a run even while the previous run of the same
+	// schedule is still executing. By default such runs are skipped.
+	AllowOverlap bool
+	// OnSkip is called with the job name when a run is skipped for overlap.
+	OnSkip func(name string)
+}
+
+// Scheduler enqueues jobs on a Runner according to recurring schedules.
+type Scheduler struct {
+	runner  *Runner
+	opts    SchedulerOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	entries []*scheduleEntry
+	started bool
+	stopped bool
+	loops   sync.WaitGroup
+	active  sync.WaitGroup
+}
+
+type scheduleEntry struct {
+	schedule Schedule
+	job      Job
+	running  atomic.Bool
+}
+
+// NewScheduler creates a scheduler that enqueues jobs on runner.
+func NewScheduler(runner *Runner, options SchedulerOptions) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{runner: runner, opts: options, ctx: ctx, cancel: cancel}
+}
+
+// Every runs job at a fixed interval.
+func (s *Scheduler) Every(interval time.Duration, job Job) error {
+	return s.Add(Every(interval), job)
+}
+
+// Cron runs job on a cron expression (see ParseCron).
+func (s *Scheduler) Cron(expr string, job Job) error {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return err
+	}
+	return s.Add(schedule, job)
+}
+
+// Add registers job on schedule. Jobs added after Start begin immediately.
+func (s *Scheduler) Add(schedule Schedule, job Job) error {
+	if job.Handler == nil {
+		return ErrHandlerMissing
+	}
+	if schedule == nil {
+		return errors.New("task schedule is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return ErrSchedulerStopped
+	}
+	entry := &scheduleEntry{schedule: schedule, job: job}
+	s.entries = append(s.entries, entry)
+	if s.started {
+		s.loops.Add(1)
+		go s.run(entry)
+	}
+	return nil
+}
+
+// Start begins scheduling registered jobs.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	for _, entry := range s.entries {
+		s.loops.Add(1)
+		go s.run(entry)
+	}
+}
+
+// Stop halts scheduling and waits for runs already enqueued to finish or
+// for ctx to be done. It does not shut down the Runner.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		s.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(entry *scheduleEntry) {
+	defer s.loops.Done()
+
+	next := entry.schedule.Next(time.Now())
+	for !next.IsZero() {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+		s.fire(entry)
+		next = entry.schedule.Next(time.Now())
+	}
+}
+
+func (s *Scheduler) fire(entry *scheduleEntry) {
+	if !s.opts.AllowOverlap && !entry.running.CompareAndSwap(false, true) {
+		if s.opts.OnSkip != nil {
+			s.opts.OnSkip(entry.job.Name)
+		}
+		return
+	}
+
+	var once sync.Once
+	finish := func() {
+		once.Do(func() {
+			entry.running.Store(false)
+			s.active.Done()
+		})
+	}
+	s.active.Add(1)
+
+	// A run ends when the handler succeeds or the runner gives up on it, so
+	// retries of a failing run still count as executing.
+	job := entry.job
+	handler := job.Handler
+	job.Handler = func(ctx context.Context) error {
+		err := handler(ctx)
+		if err == nil {
+			finish()
+		}
+		return err
+	}
+	job.OnDeadLetter = func(letter DeadLetter) {
+		finish()
+		s.runner.notifyDeadLetter(entry.job, letter.Attempts, letter.Err)
+	}
+
+	if err := s.runner.EnqueueContext(s.ctx, job); err != nil {
+		finish()
+		if s.ctx.Err() == nil {
+			s.runner.deadLetter(entry.job, 0, err)
+		}
+	}
+}
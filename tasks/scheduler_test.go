@@ -0,0 +1,208 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchedulerEveryRunsRepeatedly(t *testing.T) {
+	runner := New(Options{QueueSize: 4})
+	runner.Start(context.Background())
+	defer runner.Shutdown(context.Background())
+
+	runs := make(chan struct{}, 8)
+	scheduler := NewScheduler(runner, SchedulerOptions{})
+	err := scheduler.Every(5*time.Millisecond, Job{
+		Name: "cleanup",
+		Handler: func(ctx context.Context) error {
+			runs <- struct{}{}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("every: %v", err)
+	}
+	scheduler.Start()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatalf("expected run %d", i+1)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := scheduler.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if err := scheduler.Every(time.Second, Job{Handler: func(context.Context) error { return nil }}); err != ErrSchedulerStopped {
+		t.Fatalf("expected ErrSchedulerStopped, got %v", err)
+	}
+}
+
+func TestSchedulerSkipsOverlappingRuns(t *testing.T) {
+	runner := New(Options{Workers: 4, QueueSize: 4})
+	runner.Start(context.Background())
+	defer runner.Shutdown(context.Background())
+
+	var started, skipped atomic.Int64
+	release := make(chan struct{})
+	scheduler := NewScheduler(runner, SchedulerOptions{
+		OnSkip: func(name string) {
+			if name == "slow" {
+				skipped.Add(1)
+			}
+		},
+	})
+	_ = scheduler.Every(2*time.Millisecond, Job{
+		Name: "slow",
+		Handler: func(ctx context.Context) error {
+			started.Add(1)
+			<-release
+			return nil
+		},
+	})
+	scheduler.Start()
+
+	deadline := time.Now().Add(time.Second)
+	for skipped.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := started.Load(); got != 1 {
+		t.Fatalf("expected a single running invocation, got %d", got)
+	}
+	if skipped.Load() < 3 {
+		t.Fatalf("expected overlapping runs to be skipped, got %d", skipped.Load())
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := scheduler.Stop(stopCtx); err != context.DeadlineExceeded {
+		t.Fatalf("expected stop to wait for the running job, got %v", err)
+	}
+
+	close(release)
+	if err := scheduler.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+}
+
+func TestSchedulerAllowOverlap(t *testing.T) {
+	runner := New(Options{Workers: 4, QueueSize: 4})
+	runner.Start(context.Background())
+	defer runner.Shutdown(context.Background())
+
+	var started atomic.Int64
+	release := make(chan struct{})
+	scheduler := NewScheduler(runner, SchedulerOptions{AllowOverlap: true})
+	_ = scheduler.Every(2*time.Millisecond, Job{
+		Name: "slow",
+		Handler: func(ctx context.Context) error {
+			started.Add(1)
+			<-release
+			return nil
+		},
+	})
+	scheduler.Start()
+
+	deadline := time.Now().Add(time.Second)
+	for started.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if started.Load() < 2 {
+		t.Fatalf("expected overlapping invocations, got %d", started.Load())
+	}
+
+	close(release)
+	if err := scheduler.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+}
+
+func TestSchedulerFailedRunReleasesOverlapGuard(t *testing.T) {
+	retry := RetryPolicy{MaxRetries: 1, Backoff: func(int) time.Duration { return 0 }}
+	runner := New(Options{Workers: 2, QueueSize: 4, Retry: &retry})
+	runner.Start(context.Background())
+	defer runner.Shutdown(context.Background())
+
+	dead := make(chan DeadLetter, 4)
+	var attempts atomic.Int64
+	scheduler := NewScheduler(runner, SchedulerOptions{})
+	_ = scheduler.Every(2*time.Millisecond, Job{
+		Name: "failing",
+		Handler: func(ctx context.Context) error {
+			attempts.Add(1)
+			return errors.New("boom")
+		},
+		OnDeadLetter: func(letter DeadLetter) { dead <- letter },
+	})
+	scheduler.Start()
+
+	select {
+	case letter := <-dead:
+		if letter.Name != "failing" || letter.Attempts != 2 {
+			t.Fatalf("unexpected dead letter: %+v", letter)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected dead letter")
+	}
+
+	// The run finished via dead letter, so later runs are not blocked.
+	deadline := time.Now().Add(time.Second)
+	for attempts.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if attempts.Load() < 3 {
+		t.Fatal("expected the schedule to continue after a failed run")
+	}
+	if err := scheduler.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+}
+
+func TestParseCronNext(t *testing.T) {
+	base := time.Date(2026, time.March, 6, 17, 52, 30, 0, time.UTC) // Friday
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 9-17 * * 1-5", time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, time.March, 6, 18, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, time.March, 7, 0, 0, 0, 0, time.UTC)},
+		{"30 2 1 * *", time.Date(2026, time.April, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 13 * 7", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"5,55 17 * * *", time.Date(2026, time.March, 6, 17, 55, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+	for _, tc := range cases {
+		schedule, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.expr, err)
+		}
+		if got := schedule.Next(base); !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+
+	impossible, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := impossible.Next(base); !got.IsZero() {
+		t.Fatalf("expected zero time, got %v", got)
+	}
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every nope"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
@@ -3,8 +3,12 @@ package tasks
 import (
 	"context"
 	"errors"
+	"math/rand/v2"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/devmarvs/bebo/internal/backoff"
 )
 
 // ErrRunnerClosed indicates the runner is shutting down.
@@ -64,6 +68,9 @@ type Options struct {
 	OnRetry      func(RetryInfo)
 	OnDeadLetter func(DeadLetter)
 	Sleep        func(time.Duration)
+	// DrainDelayed runs pending EnqueueAt/EnqueueAfter jobs on Shutdown instead
+	// of discarding them (discarded jobs are reported to OnDeadLetter).
+	DrainDelayed bool
 }
 
 type runnerOptions struct {
@@ -74,13 +81,14 @@ type runnerOptions struct {
 	onRetry      func(RetryInfo)
 	onDeadLetter func(DeadLetter)
 	sleep        func(time.Duration)
+	drainDelayed bool
 }
 
-// DefaultRetryPolicy returns a retry configuration with exponential backoff.
+// DefaultRetryPolicy returns a retry configuration with jittered exponential backoff.
 func DefaultRetryPolicy() RetryPolicy {
 	return RetryPolicy{
 		MaxRetries: 2,
-		Backoff:    ExponentialBackoff(100*time.Millisecond, 2*time.Second),
+		Backoff:    ExponentialBackoffJitter(100*time.Millisecond, 2*time.Second),
 		RetryIf:    DefaultRetryDecider,
 	}
 }
@@ -105,14 +113,40 @@ type Runner struct {
 	mu        sync.Mutex
 	closed    bool
 	wg        sync.WaitGroup
+
+	delayed       delayHeap
+	schedulerOnce sync.Once
+	schedulerDone chan struct{}
+	wake          chan struct{}
+	stopScheduler chan struct{}
+
+	enqueued     atomic.Uint64
+	succeeded    atomic.Uint64
+	failed       atomic.Uint64
+	deadLettered atomic.Uint64
+	active       atomic.Int64
+}
+
+// Stats reports runner counters. Failed counts every failed attempt, retries
+// included; DeadLettered counts jobs that failed permanently or were dropped.
+type Stats struct {
+	Enqueued      uint64 `json:"enqueued"`
+	Succeeded     uint64 `json:"succeeded"`
+	Failed        uint64 `json:"failed"`
+	DeadLettered  uint64 `json:"dead_lettered"`
+	Queued        int    `json:"queued"`
+	Scheduled     int    `json:"scheduled"`
+	ActiveWorkers int    `json:"active_workers"`
 }
 
 // New creates a new Runner.
 func New(options Options) *Runner {
 	opts := normalizeOptions(options)
 	return &Runner{
-		opts:  opts,
-		queue: make(chan Job, opts.queueSize),
+		opts:          opts,
+		queue:         make(chan Job, opts.queueSize),
+		wake:          make(chan struct{}, 1),
+		stopScheduler: make(chan struct{}),
 	}
 }
 
@@ -145,12 +179,26 @@ func (r *Runner) EnqueueContext(ctx context.Context, job Job) error {
 	}
 	select {
 	case r.queue <- job:
+		r.enqueued.Add(1)
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 
+// Stats returns a snapshot of the runner counters and queue sizes.
+func (r *Runner) Stats() Stats {
+	return Stats{
+		Enqueued:      r.enqueued.Load(),
+		Succeeded:     r.succeeded.Load(),
+		Failed:        r.failed.Load(),
+		DeadLettered:  r.deadLettered.Load(),
+		Queued:        len(r.queue),
+		Scheduled:     r.Scheduled(),
+		ActiveWorkers: int(r.active.Load()),
+	}
+}
+
 // Shutdown stops accepting new jobs and waits for workers to finish.
 func (r *Runner) Shutdown(ctx context.Context) error {
 	if ctx == nil {
@@ -160,6 +208,11 @@ func (r *Runner) Shutdown(ctx context.Context) error {
 	r.closeOnce.Do(func() {
 		r.mu.Lock()
 		r.closed = true
+		r.mu.Unlock()
+
+		r.stopDelayed(ctx)
+
+		r.mu.Lock()
 		close(r.queue)
 		r.mu.Unlock()
 	})
@@ -181,7 +234,9 @@ func (r *Runner) Shutdown(ctx context.Context) error {
 func (r *Runner) worker(ctx context.Context) {
 	defer r.wg.Done()
 	for job := range r.queue {
+		r.active.Add(1)
 		r.runJob(ctx, job)
+		r.active.Add(-1)
 	}
 }
 
@@ -214,11 +269,6 @@ func (r *Runner) runJob(ctx context.Context, job Job) {
 	if onRetry == nil {
 		onRetry = r.opts.onRetry
 	}
-	onDeadLetter := job.OnDeadLetter
-	if onDeadLetter == nil {
-		onDeadLetter = r.opts.onDeadLetter
-	}
-
 	baseCtx := ctx
 	if job.Context != nil {
 		baseCtx = job.Context
@@ -238,18 +288,12 @@ func (r *Runner) runJob(ctx context.Context, job Job) {
 		cancel()
 
 		if err == nil {
+			r.succeeded.Add(1)
 			return
 		}
-		if !retry.RetryIf(err) {
-			if onDeadLetter != nil {
-				onDeadLetter(DeadLetter{Name: job.Name, Attempts: attempts, Err: err})
-			}
-			return
-		}
-		if retries >= retry.MaxRetries {
-			if onDeadLetter != nil {
-				onDeadLetter(DeadLetter{Name: job.Name, Attempts: attempts, Err: err})
-			}
+		r.failed.Add(1)
+		if !retry.RetryIf(err) || retries >= retry.MaxRetries {
+			r.deadLetter(job, attempts, err)
 			return
 		}
 
@@ -261,9 +305,7 @@ func (r *Runner) runJob(ctx context.Context, job Job) {
 
 		if delay > 0 {
 			if err := sleepWithContext(baseCtx, delay, r.opts.sleep); err != nil {
-				if onDeadLetter != nil {
-					onDeadLetter(DeadLetter{Name: job.Name, Attempts: attempts, Err: err})
-				}
+				r.deadLetter(job, attempts, err)
 				return
 			}
 		}
@@ -304,6 +346,7 @@ func normalizeOptions(options Options) runnerOptions {
 		onRetry:      options.OnRetry,
 		onDeadLetter: options.OnDeadLetter,
 		sleep:        sleep,
+		drainDelayed: options.DrainDelayed,
 	}
 }
 
@@ -344,6 +387,25 @@ func ExponentialBackoff(base, max time.Duration) BackoffFunc {
 	}
 }
 
+// ExponentialBackoffJitter returns an exponential backoff with full jitter:
+// each delay is random in [0, base*2^(attempt-1)], capped at max, so many
+// clients retrying at once spread out instead of retrying in lockstep.
+func ExponentialBackoffJitter(base, max time.Duration) BackoffFunc {
+	return ExponentialBackoffJitterSource(base, max, nil)
+}
+
+// ExponentialBackoffJitterSource is ExponentialBackoffJitter drawing from
+// source, e.g. a seeded generator for deterministic tests.
+func ExponentialBackoffJitterSource(base, max time.Duration, source *rand.Rand) BackoffFunc {
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 2 * time.Second
+	}
+	return BackoffFunc(backoff.FullJitter(base, max, source))
+}
+
 // DefaultRetryDecider retries unless the error is from context cancellation.
 func DefaultRetryDecider(err error) bool {
 	if err == nil {
@@ -3,6 +3,7 @@ package tasks
 import (
 	"context"
 	"errors"
+	"math/rand/v2"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -149,3 +150,186 @@ func TestRunnerUsesJobContext(t *testing.T) {
 		t.Fatalf("shutdown: %v", err)
 	}
 }
+
+func TestRunnerEnqueueAfterRunsInDueOrder(t *testing.T) {
+	runner := New(Options{QueueSize: 4})
+	runner.Start(context.Background())
+
+	order := make(chan string, 3)
+	record := func(name string) Job {
+		return Job{Name: name, Handler: func(context.Context) error {
+			order <- name
+			return nil
+		}}
+	}
+
+	start := time.Now()
+	if err := runner.EnqueueAfter(60*time.Millisecond, record("late")); err != nil {
+		t.Fatalf("enqueue after: %v", err)
+	}
+	if err := runner.EnqueueAt(start.Add(20*time.Millisecond), record("early")); err != nil {
+		t.Fatalf("enqueue at: %v", err)
+	}
+	if err := runner.EnqueueAfter(0, record("now")); err != nil {
+		t.Fatalf("enqueue now: %v", err)
+	}
+	if runner.Scheduled() != 2 {
+		t.Fatalf("expected 2 scheduled jobs, got %d", runner.Scheduled())
+	}
+
+	for _, want := range []string{"now", "early", "late"} {
+		select {
+		case got := <-order:
+			if got != want {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("late job ran too early: %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := runner.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestRunnerShutdownDiscardsOrDrainsDelayed(t *testing.T) {
+	for _, drain := range []bool{false, true} {
+		ran := make(chan struct{}, 1)
+		dead := make(chan DeadLetter, 1)
+		runner := New(Options{
+			QueueSize:    1,
+			DrainDelayed: drain,
+			OnDeadLetter: func(letter DeadLetter) { dead <- letter },
+		})
+		runner.Start(context.Background())
+
+		job := Job{Name: "reminder", Handler: func(context.Context) error {
+			ran <- struct{}{}
+			return nil
+		}}
+		if err := runner.EnqueueAfter(time.Hour, job); err != nil {
+			t.Fatalf("enqueue after: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := runner.Shutdown(ctx); err != nil {
+			t.Fatalf("shutdown: %v", err)
+		}
+		cancel()
+
+		if drain {
+			select {
+			case <-ran:
+			default:
+				t.Fatalf("expected drained job to run")
+			}
+		} else {
+			select {
+			case letter := <-dead:
+				if !errors.Is(letter.Err, ErrRunnerClosed) {
+					t.Fatalf("expected ErrRunnerClosed, got %v", letter.Err)
+				}
+			default:
+				t.Fatalf("expected discarded job reported")
+			}
+		}
+		if err := runner.EnqueueAfter(time.Minute, job); !errors.Is(err, ErrRunnerClosed) {
+			t.Fatalf("expected ErrRunnerClosed after shutdown, got %v", err)
+		}
+	}
+}
+
+func TestRunnerDelayedJobCanceledByContext(t *testing.T) {
+	dead := make(chan DeadLetter, 1)
+	runner := New(Options{OnDeadLetter: func(letter DeadLetter) { dead <- letter }})
+	runner.Start(context.Background())
+	defer func() {
+		_ = runner.Shutdown(context.Background())
+	}()
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	job := Job{Name: "canceled", Context: jobCtx, Handler: func(context.Context) error {
+		t.Error("canceled job should not run")
+		return nil
+	}}
+	if err := runner.EnqueueAfter(time.Hour, job); err != nil {
+		t.Fatalf("enqueue after: %v", err)
+	}
+	cancel()
+
+	select {
+	case letter := <-dead:
+		if !errors.Is(letter.Err, context.Canceled) {
+			t.Fatalf("expected context canceled, got %v", letter.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected canceled job reported")
+	}
+	if runner.Scheduled() != 0 {
+		t.Fatalf("expected canceled job removed, got %d scheduled", runner.Scheduled())
+	}
+}
+
+func TestRunnerStats(t *testing.T) {
+	retry := RetryPolicy{MaxRetries: 1, Backoff: func(int) time.Duration { return 0 }}
+	runner := New(Options{QueueSize: 4, Retry: &retry})
+
+	release := make(chan struct{})
+	running := make(chan struct{})
+	_ = runner.Enqueue(Job{Name: "block", Handler: func(ctx context.Context) error {
+		close(running)
+		<-release
+		return nil
+	}})
+	_ = runner.Enqueue(Job{Name: "fail", Handler: func(ctx context.Context) error { return errors.New("boom") }})
+	_ = runner.EnqueueAfter(time.Hour, Job{Name: "later", Handler: func(ctx context.Context) error { return nil }})
+
+	stats := runner.Stats()
+	if stats.Enqueued != 3 || stats.Queued != 2 || stats.Scheduled != 1 || stats.ActiveWorkers != 0 {
+		t.Fatalf("unexpected stats before start: %+v", stats)
+	}
+
+	runner.Start(context.Background())
+	<-running
+	if stats := runner.Stats(); stats.ActiveWorkers != 1 || stats.Queued != 1 {
+		t.Fatalf("unexpected stats while running: %+v", stats)
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := runner.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	stats = runner.Stats()
+	want := Stats{Enqueued: 3, Succeeded: 1, Failed: 2, DeadLettered: 2}
+	if stats != want {
+		t.Fatalf("expected %+v, got %+v", want, stats)
+	}
+}
+
+func TestExponentialBackoffJitter(t *testing.T) {
+	seeded := func() BackoffFunc {
+		return ExponentialBackoffJitterSource(10*time.Millisecond, 80*time.Millisecond, rand.New(rand.NewPCG(7, 7)))
+	}
+	a, b := seeded(), seeded()
+	for attempt := 1; attempt <= 20; attempt++ {
+		delay := a(attempt)
+		if delay != b(attempt) {
+			t.Fatalf("attempt %d: expected deterministic delays from a seeded source", attempt)
+		}
+		if ceiling := ExponentialBackoff(10*time.Millisecond, 80*time.Millisecond)(attempt); delay < 0 || delay > ceiling {
+			t.Fatalf("attempt %d: %v outside [0, %v]", attempt, delay, ceiling)
+		}
+	}
+	if delay := ExponentialBackoffJitter(0, 0)(1); delay < 0 || delay > 100*time.Millisecond {
+		t.Fatalf("unexpected default jitter: %v", delay)
+	}
+}
@@ -0,0 +1,54 @@
+package bebo
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// TemplateDataFunc provides shared data for every HTML render.
+type TemplateDataFunc func(*Context) (map[string]any, error)
+
+// TemplateData registers a provider whose values are merged into ctx.HTML data.
+// Providers run per render in registration order; later providers override earlier
+// keys and handler-supplied map keys override provider keys. Nil data is replaced
+// by the merged provider values. Providers are skipped for non-map data such as
+// structs (including web.TemplateData), which is passed through unchanged and
+// logged at debug level; such data must carry the values itself. A provider error
+// aborts the render.
+func (a *App) TemplateData(fn TemplateDataFunc) {
+	if fn == nil {
+		return
+	}
+	a.templateData = append(a.templateData, fn)
+}
+
+func (c *Context) templateData(data any) (any, error) {
+	if c.app == nil || len(c.app.templateData) == 0 {
+		return data, nil
+	}
+
+	var payload map[string]any
+	switch typed := data.(type) {
+	case nil:
+	case map[string]any:
+		payload = typed
+	default:
+		c.Logger().Debug("template data providers skipped for non-map data", slog.String("type", fmt.Sprintf("%T", data)))
+		return data, nil
+	}
+
+	merged := make(map[string]any, len(payload))
+	for _, provider := range c.app.templateData {
+		values, err := provider(c)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+	for key, value := range payload {
+		merged[key] = value
+	}
+	return merged, nil
+}
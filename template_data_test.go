@@ -0,0 +1,159 @@
+package bebo
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devmarvs/bebo/render"
+)
+
+func newTemplateDataApp(t *testing.T, options ...Option) *App {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "layout.html"), []byte("{{ template \"content\" . }}"), 0o644); err != nil {
+		t.Fatalf("write layout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte("{{ define \"content\" }}{{ .User }}|{{ .Title }}{{ end }}"), 0o644); err != nil {
+		t.Fatalf("write page: %v", err)
+	}
+
+	engine, err := render.NewEngineWithOptions(dir, render.Options{Layout: "layout.html"})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+	return New(append(options, WithRenderer(engine))...)
+}
+
+func TestTemplateDataMergesProviders(t *testing.T) {
+	app := newTemplateDataApp(t)
+	app.TemplateData(func(*Context) (map[string]any, error) {
+		return map[string]any{"User": "ada", "Title": "default"}, nil
+	})
+	app.GET("/", func(ctx *Context) error {
+		return ctx.HTML(http.StatusOK, "page.html", map[string]any{"Title": "home"})
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); body != "ada|home" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestTemplateDataSkipsStructData(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	app := newTemplateDataApp(t, WithLogger(logger))
+	calls := 0
+	app.TemplateData(func(*Context) (map[string]any, error) {
+		calls++
+		return map[string]any{"User": "ada"}, nil
+	})
+	app.GET("/", func(ctx *Context) error {
+		return ctx.HTML(http.StatusOK, "page.html", struct{ User, Title string }{"grace", "home"})
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if body := rec.Body.String(); body != "grace|home" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if calls != 0 {
+		t.Fatalf("expected providers to be skipped for struct data, got %d calls", calls)
+	}
+	if !strings.Contains(logs.String(), "template data providers skipped") {
+		t.Fatalf("expected skip to be logged, got %q", logs.String())
+	}
+}
+
+func TestTemplateDataProviderError(t *testing.T) {
+	app := newTemplateDataApp(t)
+	providerErr := errors.New("load user")
+	app.TemplateData(func(*Context) (map[string]any, error) {
+		return nil, providerErr
+	})
+
+	var got error
+	app.GET("/", func(ctx *Context) error {
+		got = ctx.HTML(http.StatusOK, "page.html", nil)
+		return got
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !errors.Is(got, providerErr) {
+		t.Fatalf("expected provider error, got %v", got)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+}
+
+func TestContextRenderString(t *testing.T) {
+	app := newTemplateDataApp(t)
+	app.TemplateData(func(*Context) (map[string]any, error) {
+		return map[string]any{"User": "ada"}, nil
+	})
+	app.GET("/", func(ctx *Context) error {
+		body, err := ctx.RenderString("page.html", map[string]any{"Title": "welcome"})
+		if err != nil {
+			return err
+		}
+		return ctx.Text(http.StatusAccepted, "rendered:"+body)
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected handler to control the response, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); body != "rendered:ada|welcome" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestContextRenderText(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "receipt.txt"), []byte("{{ .User }} paid {{ .Amount }} & thanks"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	text, err := render.NewTextEngine(dir, render.Options{})
+	if err != nil {
+		t.Fatalf("text engine: %v", err)
+	}
+
+	app := New(WithTextRenderer(text))
+	app.TemplateData(func(*Context) (map[string]any, error) {
+		return map[string]any{"User": "<ada>"}, nil
+	})
+	var body string
+	app.GET("/", func(ctx *Context) error {
+		var err error
+		body, err = ctx.RenderText("receipt", map[string]any{"Amount": "$5"})
+		if err != nil {
+			return err
+		}
+		return ctx.Text(http.StatusOK, "sent")
+	})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusOK || body != "<ada> paid $5 & thanks" {
+		t.Fatalf("unexpected render %d %q", rec.Code, body)
+	}
+}
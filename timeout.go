@@ -8,10 +8,21 @@ import (
 	"time"
 
 	"github.com/devmarvs/bebo/apperr"
+	"github.com/devmarvs/bebo/internal/httpx"
 )
 
 // TimeoutHandler wraps a handler with a timeout.
 func TimeoutHandler(next Handler, duration time.Duration) Handler {
+	return TimeoutHandlerWith(next, duration, func(_ *Context, cause error) error {
+		return apperr.Timeout("request timeout", cause)
+	})
+}
+
+// TimeoutHandlerWith wraps a handler with a timeout and builds the error returned
+// when the deadline passes. The handler runs against a buffered writer, so output
+// it produced before the deadline is discarded and the error response is written
+// in its place.
+func TimeoutHandlerWith(next Handler, duration time.Duration, onTimeout func(*Context, error) error) Handler {
 	return func(ctx *Context) error {
 		if duration <= 0 {
 			return next(ctx)
@@ -21,21 +32,32 @@ func TimeoutHandler(next Handler, duration time.Duration) Handler {
 		defer cancel()
 
 		writer := newTimeoutWriter(ctx.ResponseWriter)
-		ctx.ResponseWriter = writer
-		ctx.Request = ctx.Request.WithContext(reqCtx)
+		inner := *ctx
+		inner.ResponseWriter = writer
+		inner.Request = ctx.Request.WithContext(reqCtx)
+		inner.values = make(map[any]any, len(ctx.values))
+		for key, value := range ctx.values {
+			inner.values[key] = value
+		}
 
 		done := make(chan error, 1)
 		go func() {
-			done <- next(ctx)
+			done <- next(&inner)
 		}()
 
 		select {
 		case err := <-done:
 			writer.commit()
+			// An aborted context keeps its discarding writer so outer
+			// middleware cannot write after the abort response.
+			if !inner.aborted {
+				inner.ResponseWriter = ctx.ResponseWriter
+			}
+			*ctx = inner
 			return err
 		case <-reqCtx.Done():
 			writer.timeout()
-			return apperr.Timeout("request timeout", reqCtx.Err())
+			return onTimeout(ctx, reqCtx.Err())
 		}
 	}
 }
@@ -44,7 +66,7 @@ type timeoutWriter struct {
 	w        http.ResponseWriter
 	header   http.Header
 	buffer   bytes.Buffer
-	code     int
+	code     httpx.WriteHeaderOnce
 	timedOut bool
 	mu       sync.Mutex
 }
@@ -59,9 +81,7 @@ func (tw *timeoutWriter) Header() http.Header {
 
 func (tw *timeoutWriter) WriteHeader(code int) {
 	tw.mu.Lock()
-	if tw.code == 0 {
-		tw.code = code
-	}
+	tw.code.Record(code)
 	tw.mu.Unlock()
 }
 
@@ -86,8 +106,8 @@ func (tw *timeoutWriter) commit() {
 			tw.w.Header().Add(key, value)
 		}
 	}
-	if tw.code != 0 {
-		tw.w.WriteHeader(tw.code)
+	if tw.code.Written() {
+		tw.w.WriteHeader(tw.code.Status())
 	}
 	_, _ = tw.w.Write(tw.buffer.Bytes())
 }
@@ -0,0 +1,327 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/devmarvs/bebo/apperr"
+)
+
+// Schema is a compiled JSON Schema supporting a minimal subset of keywords:
+// type, required, properties, additionalProperties (boolean), items, enum,
+// minimum, maximum, minLength, maxLength, minItems and maxItems. Unknown
+// keywords are ignored.
+type Schema struct {
+	types                []string
+	required             []string
+	properties           map[string]*Schema
+	additionalProperties *bool
+	items                *Schema
+	enum                 []any
+	minimum              *float64
+	maximum              *float64
+	minLength            *int
+	maxLength            *int
+	minItems             *int
+	maxItems             *int
+}
+
+type rawSchema struct {
+	Type                 json.RawMessage            `json:"type"`
+	Required             []string                   `json:"required"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	AdditionalProperties *bool                      `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	Enum                 []any                      `json:"enum"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	MinItems             *int                       `json:"minItems"`
+	MaxItems             *int                       `json:"maxItems"`
+}
+
+var schemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// ParseSchema compiles a JSON Schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var raw rawSchema
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+
+	schema := &Schema{
+		required:             raw.Required,
+		additionalProperties: raw.AdditionalProperties,
+		enum:                 raw.Enum,
+		minimum:              raw.Minimum,
+		maximum:              raw.Maximum,
+		minLength:            raw.MinLength,
+		maxLength:            raw.MaxLength,
+		minItems:             raw.MinItems,
+		maxItems:             raw.MaxItems,
+	}
+	if len(raw.Type) > 0 {
+		var single string
+		if err := json.Unmarshal(raw.Type, &single); err == nil {
+			schema.types = []string{single}
+		} else if err := json.Unmarshal(raw.Type, &schema.types); err != nil {
+			return nil, errors.New("invalid json schema: type must be a string or array")
+		}
+		for _, name := range schema.types {
+			if !schemaTypes[name] {
+				return nil, fmt.Errorf("invalid json schema: unknown type %q", name)
+			}
+		}
+	}
+
+	if len(raw.Properties) > 0 {
+		schema.properties = make(map[string]*Schema, len(raw.Properties))
+		for name, data := range raw.Properties {
+			child, err := ParseSchema(data)
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", name, err)
+			}
+			schema.properties[name] = child
+		}
+	}
+	if len(raw.Items) > 0 {
+		items, err := ParseSchema(raw.Items)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+		schema.items = items
+	}
+
+	return schema, nil
+}
+
+// ValidateJSON decodes data and validates it against the schema. Violations
+// are returned as a validation error carrying field errors, named by path
+// such as "address.city" or "tags[1]".
+func (s *Schema) ValidateJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return apperr.BadRequest("invalid JSON", err)
+	}
+	if decoder.More() {
+		return apperr.BadRequest("invalid JSON", errors.New("unexpected data after json value"))
+	}
+	return s.Validate(value)
+}
+
+// Validate checks a decoded JSON value (as produced by encoding/json into
+// an any) against the schema.
+func (s *Schema) Validate(value any) error {
+	var errs []FieldError
+	s.validate("", value, &errs)
+	if len(errs) > 0 {
+		return apperr.Validation("validation failed", &Errors{Fields: errs})
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, value any, errs *[]FieldError) {
+	name := path
+	if name == "" {
+		name = "body"
+	}
+	fail := func(message string) {
+		*errs = append(*errs, FieldError{Field: name, Message: name + " " + message})
+	}
+
+	if len(s.types) > 0 && !matchesAnyType(value, s.types) {
+		fail("must be " + joinTypes(s.types))
+		return
+	}
+	if s.enum != nil && !inEnum(value, s.enum) {
+		fail("must be one of " + formatEnum(s.enum))
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.minLength != nil && length < *s.minLength {
+			fail("is too short")
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail("is too long")
+		}
+	case json.Number, float64:
+		number, ok := numberValue(v)
+		if !ok {
+			return
+		}
+		if s.minimum != nil && number < *s.minimum {
+			fail("must be at least " + formatNumber(*s.minimum))
+		}
+		if s.maximum != nil && number > *s.maximum {
+			fail("must be at most " + formatNumber(*s.maximum))
+		}
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("is too short")
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			fail("is too long")
+		}
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case map[string]any:
+		for _, field := range s.required {
+			if _, ok := v[field]; !ok {
+				childName := joinPath(path, field)
+				*errs = append(*errs, FieldError{Field: childName, Message: childName + " is required"})
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child, ok := s.properties[key]
+			if !ok {
+				if s.additionalProperties != nil && !*s.additionalProperties {
+					childName := joinPath(path, key)
+					*errs = append(*errs, FieldError{Field: childName, Message: childName + " is not allowed"})
+				}
+				continue
+			}
+			child.validate(joinPath(path, key), v[key], errs)
+		}
+	}
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func matchesAnyType(value any, types []string) bool {
+	for _, name := range types {
+		if matchesType(value, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(value any, name string) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := numberValue(value)
+		return ok
+	case "integer":
+		number, ok := numberValue(value)
+		return ok && !math.IsInf(number, 0) && number == math.Trunc(number)
+	}
+	return false
+}
+
+func numberValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		number, err := strconv.ParseFloat(v.String(), 64)
+		return number, err == nil
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func joinTypes(types []string) string {
+	articles := make([]string, len(types))
+	for i, name := range types {
+		article := "a "
+		if name == "object" || name == "array" || name == "integer" {
+			article = "an "
+		}
+		if name == "null" {
+			article = ""
+		}
+		articles[i] = article + name
+	}
+	return strings.Join(articles, " or ")
+}
+
+// normalizeJSON converts json.Number values to float64 so request values
+// (decoded with UseNumber) compare equal to enum values from the schema.
+func normalizeJSON(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if number, ok := numberValue(v); ok {
+			return number
+		}
+		return v.String()
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeJSON(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalizeJSON(item)
+		}
+		return out
+	}
+	return value
+}
+
+func inEnum(value any, enum []any) bool {
+	normalized := normalizeJSON(value)
+	for _, candidate := range enum {
+		if reflect.DeepEqual(normalized, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, value := range enum {
+		encoded, _ := json.Marshal(value)
+		parts[i] = string(encoded)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,85 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+const userSchema = `{
+  "type": "object",
+  "required": ["name", "age"],
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "minLength": 2},
+    "age": {"type": "integer", "minimum": 18},
+    "role": {"enum": ["admin", "member"]},
+    "tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+    "address": {
+      "type": "object",
+      "required": ["city"],
+      "properties": {"city": {"type": "string"}}
+    }
+  }
+}`
+
+func TestSchemaValid(t *testing.T) {
+	schema, err := ParseSchema([]byte(userSchema))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	body := `{"name":"Ada","age":36,"role":"admin","tags":["a"],"address":{"city":"London"}}`
+	if err := schema.ValidateJSON([]byte(body)); err != nil {
+		t.Fatalf("expected valid body, got %v", err)
+	}
+}
+
+func TestSchemaFieldErrors(t *testing.T) {
+	schema, err := ParseSchema([]byte(userSchema))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	body := `{"name":"A","age":17.5,"role":"owner","tags":["a",2],"address":{},"extra":true}`
+	verr, ok := As(schema.ValidateJSON([]byte(body)))
+	if !ok {
+		t.Fatalf("expected validation errors")
+	}
+
+	want := []FieldError{
+		{Field: "address.city", Message: "address.city is required"},
+		{Field: "age", Message: "age must be an integer"},
+		{Field: "extra", Message: "extra is not allowed"},
+		{Field: "name", Message: "name is too short"},
+		{Field: "role", Message: `role must be one of "admin", "member"`},
+		{Field: "tags[1]", Message: "tags[1] must be a string"},
+	}
+	if !reflect.DeepEqual(verr.Fields, want) {
+		t.Fatalf("unexpected field errors: %+v", verr.Fields)
+	}
+}
+
+func TestSchemaRangesAndTypes(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{"type":["number","null"],"minimum":1,"maximum":10}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := schema.ValidateJSON([]byte(`null`)); err != nil {
+		t.Fatalf("expected null to be allowed, got %v", err)
+	}
+	verr, ok := As(schema.ValidateJSON([]byte(`11`)))
+	if !ok || verr.Fields[0].Message != "body must be at most 10" {
+		t.Fatalf("unexpected errors: %+v", verr)
+	}
+	verr, ok = As(schema.ValidateJSON([]byte(`"3"`)))
+	if !ok || verr.Fields[0].Message != "body must be a number or null" {
+		t.Fatalf("unexpected errors: %+v", verr)
+	}
+}
+
+func TestParseSchemaRejectsUnknownType(t *testing.T) {
+	if _, err := ParseSchema([]byte(`{"properties":{"id":{"type":"uuid"}}}`)); err == nil {
+		t.Fatalf("expected error for unknown type")
+	}
+	if _, err := ParseSchema([]byte(`{`)); err == nil {
+		t.Fatalf("expected error for invalid json")
+	}
+}
@@ -2,8 +2,10 @@ package validate
 
 import (
 	"errors"
+	"fmt"
 	"net/mail"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -66,7 +68,9 @@ func As(err error) (*Errors, bool) {
 	return nil, false
 }
 
-// Struct validates struct fields using `validate` tags.
+// Struct validates struct fields using `validate` tags. Rules run left to
+// right and every failure is reported, unless the tag includes "bail". Rules
+// after "dive" validate each element of a slice, array, or map.
 func Struct(value any) error {
 	rv := reflect.ValueOf(value)
 	if !rv.IsValid() {
@@ -95,65 +99,104 @@ func Struct(value any) error {
 			continue
 		}
 
-		name := fieldName(field)
-		rules := strings.Split(tag, ",")
-		fieldValue := rv.Field(i)
+		validateValue(fieldName(field), rv.Field(i), strings.Split(tag, ","), &errs)
+	}
 
-		if fieldValue.Kind() == reflect.Pointer {
-			if fieldValue.IsNil() {
-				if hasRule(rules, "required") {
-					errs = append(errs, FieldError{Field: name, Message: name + " is required"})
-				}
-				continue
+	if len(errs) > 0 {
+		err := apperr.Validation("validation failed", &Errors{Fields: errs})
+		notifyHooks(value, err)
+		return err
+	}
+
+	return nil
+}
+
+// validateValue applies rules to value. Rules after "dive" apply to each
+// slice, array, or map element, named like "tags[0]" or "labels[env]".
+func validateValue(name string, value reflect.Value, rules []string, errs *[]FieldError) {
+	rules, elemRules, dive := splitDive(rules)
+
+	if value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			if hasRule(rules, "required") {
+				*errs = append(*errs, FieldError{Field: name, Message: name + " is required"})
 			}
-			fieldValue = fieldValue.Elem()
+			return
 		}
+		value = value.Elem()
+	}
 
-		for _, rule := range rules {
-			rule = strings.TrimSpace(rule)
-			if rule == "" {
+	// "bail" anywhere in the tag stops at the field's first failing rule.
+	bail := hasRule(rules, "bail")
+	fieldStart := len(*errs)
+	for _, rule := range rules {
+		if bail && len(*errs) > fieldStart {
+			break
+		}
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		nameRule, param := splitRule(rule)
+		switch nameRule {
+		case "bail":
+		case "required":
+			if isZeroValue(value) {
+				*errs = append(*errs, FieldError{Field: name, Message: name + " is required"})
+			}
+		case "email":
+			if value.Kind() != reflect.String {
 				continue
 			}
-			nameRule, param := splitRule(rule)
-			switch nameRule {
-			case "required":
-				if isZeroValue(fieldValue) {
-					errs = append(errs, FieldError{Field: name, Message: name + " is required"})
-				}
-			case "email":
-				if fieldValue.Kind() != reflect.String {
-					continue
+			if text := value.String(); text != "" {
+				if _, err := mail.ParseAddress(text); err != nil {
+					*errs = append(*errs, FieldError{Field: name, Message: name + " must be a valid email"})
 				}
-				if value := fieldValue.String(); value != "" {
-					if _, err := mail.ParseAddress(value); err != nil {
-						errs = append(errs, FieldError{Field: name, Message: name + " must be a valid email"})
-					}
-				}
-			case "min":
-				if err := validateMin(name, fieldValue, param); err != nil {
-					errs = append(errs, *err)
-				}
-			case "max":
-				if err := validateMax(name, fieldValue, param); err != nil {
-					errs = append(errs, *err)
-				}
-			default:
-				if fn := lookupValidator(nameRule); fn != nil {
-					if err := fn(name, fieldValue, param); err != nil {
-						errs = append(errs, *err)
-					}
+			}
+		case "min":
+			if err := validateMin(name, value, param); err != nil {
+				*errs = append(*errs, *err)
+			}
+		case "max":
+			if err := validateMax(name, value, param); err != nil {
+				*errs = append(*errs, *err)
+			}
+		default:
+			if fn := lookupValidator(nameRule); fn != nil {
+				if err := fn(name, value, param); err != nil {
+					*errs = append(*errs, *err)
 				}
 			}
 		}
 	}
 
-	if len(errs) > 0 {
-		err := apperr.Validation("validation failed", &Errors{Fields: errs})
-		notifyHooks(value, err)
-		return err
+	if !dive || (bail && len(*errs) > fieldStart) {
+		return
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(fmt.Sprintf("%s[%d]", name, i), value.Index(i), elemRules, errs)
+		}
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			validateValue(fmt.Sprintf("%s[%v]", name, key.Interface()), value.MapIndex(key), elemRules, errs)
+		}
 	}
+}
 
-	return nil
+// splitDive separates a field's own rules from the element rules after "dive".
+func splitDive(rules []string) ([]string, []string, bool) {
+	for i, rule := range rules {
+		if strings.TrimSpace(rule) == "dive" {
+			return rules[:i], rules[i+1:], true
+		}
+	}
+	return rules, nil, false
 }
 
 func fieldName(field reflect.StructField) string {
@@ -92,3 +92,81 @@ func TestValidationHooksOnError(t *testing.T) {
 		t.Fatalf("expected validation hook to be called")
 	}
 }
+
+type bailInput struct {
+	Password string `json:"password" validate:"required,bail,min=8"`
+	Nickname string `json:"nickname" validate:"min=3,max=1"`
+}
+
+func TestStructValidationBail(t *testing.T) {
+	err := Struct(bailInput{Nickname: "ab"})
+	verr, ok := As(err)
+	if !ok {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	var password []string
+	nickname := 0
+	for _, field := range verr.Fields {
+		switch field.Field {
+		case "password":
+			password = append(password, field.Message)
+		case "nickname":
+			nickname++
+		}
+	}
+	if len(password) != 1 || password[0] != "password is required" {
+		t.Fatalf("expected only the required error for password, got %v", password)
+	}
+	if nickname != 2 {
+		t.Fatalf("expected fields without bail to report every failure, got %d", nickname)
+	}
+
+	err = Struct(bailInput{Password: "short", Nickname: "a"})
+	verr, _ = As(err)
+	if len(verr.Fields) != 2 || verr.Fields[0].Message != "password is too short" {
+		t.Fatalf("expected rules after bail to run while earlier rules pass, got %v", verr.Fields)
+	}
+}
+
+type diveInput struct {
+	Tags   []string          `json:"tags" validate:"min=1,dive,min=1"`
+	Labels map[string]string `json:"labels" validate:"dive,required,max=5"`
+	Grid   [][]int           `json:"grid" validate:"dive,dive,max=9"`
+}
+
+func TestStructValidationDive(t *testing.T) {
+	input := diveInput{
+		Tags:   []string{"go", "", "web"},
+		Labels: map[string]string{"env": "production", "team": "", "tier": "web"},
+		Grid:   [][]int{{1, 2}, {3, 10}},
+	}
+	verr, ok := As(Struct(input))
+	if !ok {
+		t.Fatalf("expected validation errors")
+	}
+
+	want := []FieldError{
+		{Field: "tags[1]", Message: "tags[1] is too short"},
+		{Field: "labels[env]", Message: "labels[env] is too long"},
+		{Field: "labels[team]", Message: "labels[team] is required"},
+		{Field: "grid[1][1]", Message: "grid[1][1] must be at most 9"},
+	}
+	if !reflect.DeepEqual(verr.Fields, want) {
+		t.Fatalf("expected %v, got %v", want, verr.Fields)
+	}
+}
+
+func TestStructValidationDiveRunsFieldRulesFirst(t *testing.T) {
+	verr, ok := As(Struct(diveInput{}))
+	if !ok {
+		t.Fatalf("expected validation errors")
+	}
+	if len(verr.Fields) != 1 || verr.Fields[0].Field != "tags" {
+		t.Fatalf("expected only the collection-level tags error, got %v", verr.Fields)
+	}
+
+	if err := Struct(diveInput{Tags: []string{"go"}, Labels: map[string]string{"env": "dev"}}); err != nil {
+		t.Fatalf("expected valid elements to pass, got %v", err)
+	}
+}
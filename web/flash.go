@@ -0,0 +1,53 @@
+package web
+
+import (
+	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/flash"
+)
+
+var flashesKey = bebo.NewContextKey[[]flash.Message]("bebo.web.flashes")
+
+// Flashes reads and clears the request's flash messages in one call. The
+// first call pops them from the store, saving the session so they do not
+// show again; later calls in the same request return the same messages
+// without another save. With cookie-backed stores the save sets a cookie, so
+// call it before the response is written (ctx.HTML data providers run in
+// time, template execution does not).
+func Flashes(ctx *bebo.Context, store *flash.Store) ([]flash.Message, error) {
+	if messages, ok := flashesKey.Get(ctx); ok {
+		return messages, nil
+	}
+	if store == nil {
+		return nil, flash.ErrStoreMissing
+	}
+
+	// Skip the save, and its cookie, when there is nothing to clear.
+	messages, err := store.Peek(ctx.Request)
+	if err == nil && len(messages) > 0 {
+		messages, err = store.Pop(ctx.ResponseWriter, ctx.Request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	flashesKey.Set(ctx, messages)
+	return messages, nil
+}
+
+// FlashData returns a template data provider exposing the request's flash
+// messages as "Flash" to every ctx.HTML render with map (or nil) data:
+//
+//	app.TemplateData(web.FlashData(&store))
+//
+//	{{ range .Flash }}<li class="flash-{{ .Type }}">{{ .Text }}</li>{{ end }}
+//
+// Messages are consumed only when a page renders, so redirects and JSON
+// responses leave them in place.
+func FlashData(store *flash.Store) bebo.TemplateDataFunc {
+	return func(ctx *bebo.Context) (map[string]any, error) {
+		messages, err := Flashes(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"Flash": messages}, nil
+	}
+}
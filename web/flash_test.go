@@ -0,0 +1,96 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/flash"
+	"github.com/devmarvs/bebo/render"
+	"github.com/devmarvs/bebo/session"
+)
+
+func newFlashApp(t *testing.T, store *flash.Store) *bebo.App {
+	t.Helper()
+	dir := t.TempDir()
+	page := `{{ range .Flash }}[{{ .Type }}:{{ .Text }}]{{ end }}`
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(page), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	engine, err := render.NewEngineWithOptions(dir, render.Options{})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	app := bebo.New(bebo.WithRenderer(engine))
+	app.TemplateData(FlashData(store))
+	app.POST("/save", func(ctx *bebo.Context) error {
+		if err := store.Add(ctx.ResponseWriter, ctx.Request, flash.Message{Type: "success", Text: "saved"}); err != nil {
+			return err
+		}
+		http.Redirect(ctx.ResponseWriter, ctx.Request, "/", http.StatusSeeOther)
+		return nil
+	})
+	app.GET("/", func(ctx *bebo.Context) error {
+		// A second read in the same request returns the same messages.
+		if _, err := Flashes(ctx, store); err != nil {
+			return err
+		}
+		return ctx.HTML(http.StatusOK, "page.html", nil)
+	})
+	return app
+}
+
+func serveWithCookies(app *bebo.App, method, path string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFlashDataShowsMessagesOnce(t *testing.T) {
+	store := flash.New(session.NewCookieStore("flash", []byte("secret")))
+	app := newFlashApp(t, &store)
+
+	saved := serveWithCookies(app, http.MethodPost, "/save", nil)
+	if saved.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect, got %d", saved.Code)
+	}
+
+	shown := serveWithCookies(app, http.MethodGet, "/", saved.Result().Cookies())
+	if body := shown.Body.String(); body != "[success:saved]" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	cleared := shown.Result().Cookies()
+	if len(cleared) != 1 {
+		t.Fatalf("expected a single clearing cookie, got %d", len(cleared))
+	}
+
+	again := serveWithCookies(app, http.MethodGet, "/", cleared)
+	if body := again.Body.String(); body != "" {
+		t.Fatalf("expected flashes to be consumed, got %q", body)
+	}
+	if len(again.Result().Cookies()) != 0 {
+		t.Fatalf("expected no cookie write without flashes")
+	}
+}
+
+func TestFlashesMissingStore(t *testing.T) {
+	app := bebo.New()
+	var got error
+	app.GET("/", func(ctx *bebo.Context) error {
+		_, got = Flashes(ctx, nil)
+		return nil
+	})
+	serveWithCookies(app, http.MethodGet, "/", nil)
+	if !errors.Is(got, flash.ErrStoreMissing) {
+		t.Fatalf("expected ErrStoreMissing, got %v", got)
+	}
+}
@@ -0,0 +1,18 @@
+package web
+
+import "github.com/devmarvs/bebo"
+
+// PrincipalData returns a template data provider exposing the authenticated
+// principal as "Principal" to every ctx.HTML render with map (or nil) data:
+//
+//	app.TemplateData(web.PrincipalData())
+//
+//	{{ with .Principal }}Signed in as {{ .ID }}{{ end }}
+//
+// Unauthenticated requests get a nil Principal. Like every provider it is
+// skipped for struct data; web.TemplateData carries the principal instead.
+func PrincipalData() bebo.TemplateDataFunc {
+	return func(ctx *bebo.Context) (map[string]any, error) {
+		return map[string]any{"Principal": ctx.Principal()}, nil
+	}
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devmarvs/bebo"
+	"github.com/devmarvs/bebo/middleware"
+	"github.com/devmarvs/bebo/render"
+)
+
+type principalAuth struct{}
+
+func (principalAuth) Authenticate(*bebo.Context) (*bebo.Principal, error) {
+	return &bebo.Principal{ID: "user-7"}, nil
+}
+
+func TestPrincipalData(t *testing.T) {
+	dir := t.TempDir()
+	page := `{{ with .Principal }}user={{ .ID }}{{ else }}anonymous{{ end }}`
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(page), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	engine, err := render.NewEngineWithOptions(dir, render.Options{})
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	app := bebo.New(bebo.WithRenderer(engine))
+	app.TemplateData(PrincipalData())
+	handler := func(ctx *bebo.Context) error {
+		return ctx.HTML(http.StatusOK, "page.html", nil)
+	}
+	app.GET("/public", handler)
+	app.GET("/private", handler, middleware.RequireAuth(principalAuth{}))
+
+	for path, want := range map[string]string{"/public": "anonymous", "/private": "user=user-7"} {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if body := rec.Body.String(); body != want {
+			t.Fatalf("%s: expected %q, got %q", path, want, body)
+		}
+	}
+}
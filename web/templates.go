@@ -16,6 +16,8 @@ type TemplateData struct {
 	Data      any
 	CSRFToken string
 	Flash     []flash.Message
+	// Principal is the authenticated principal, or nil.
+	Principal *bebo.Principal
 }
 
 // TemplateDataFrom builds TemplateData from the request context.
@@ -23,13 +25,14 @@ func TemplateDataFrom(ctx *bebo.Context, store *flash.Store, data any) (Template
 	view := TemplateData{
 		Data:      data,
 		CSRFToken: middleware.CSRFToken(ctx),
+		Principal: ctx.Principal(),
 	}
 
 	if store == nil {
 		return view, nil
 	}
 
-	messages, err := store.Pop(ctx.ResponseWriter, ctx.Request)
+	messages, err := Flashes(ctx, store)
 	if err != nil {
 		return view, err
 	}
@@ -37,12 +40,13 @@ func TemplateDataFrom(ctx *bebo.Context, store *flash.Store, data any) (Template
 	return view, nil
 }
 
-// Funcs returns template helpers for CSRF fields.
+// Funcs returns template helpers for CSRF fields, including the request-scoped
+// csrf_token and csrf_field funcs from middleware.CSRFTemplateFuncs.
 func Funcs() render.FuncMap {
-	return render.FuncMap{
-		"csrfField":      CSRFField,
-		"csrfFieldNamed": CSRFFieldNamed,
-	}
+	funcs := middleware.CSRFTemplateFuncs()
+	funcs["csrfField"] = CSRFField
+	funcs["csrfFieldNamed"] = CSRFFieldNamed
+	return funcs
 }
 
 // CSRFField renders a hidden CSRF field using the default name.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/devmarvs/bebo"
 	"github.com/devmarvs/bebo/flash"
+	"github.com/devmarvs/bebo/middleware"
 	"github.com/devmarvs/bebo/router"
 	"github.com/devmarvs/bebo/session"
 )
@@ -33,7 +34,7 @@ func TestTemplateDataFrom(t *testing.T) {
 
 	app := bebo.New()
 	ctx := bebo.NewContext(rec2, req2, router.Params{}, app)
-	ctx.Set("bebo.csrf", "token")
+	middleware.SetCSRFToken(ctx, "token")
 
 	view, err := TemplateDataFrom(ctx, &store, map[string]string{"Title": "Hello"})
 	if err != nil {
@@ -45,6 +46,18 @@ func TestTemplateDataFrom(t *testing.T) {
 	if len(view.Flash) != 1 {
 		t.Fatalf("expected 1 flash message, got %d", len(view.Flash))
 	}
+	if view.Principal != nil {
+		t.Fatalf("expected nil principal, got %+v", view.Principal)
+	}
+
+	bebo.SetPrincipal(ctx, &bebo.Principal{ID: "user-1"})
+	view, err = TemplateDataFrom(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("template data: %v", err)
+	}
+	if view.Principal == nil || view.Principal.ID != "user-1" {
+		t.Fatalf("expected principal, got %+v", view.Principal)
+	}
 }
 
 func TestCSRFFieldEscapes(t *testing.T) {